@@ -3,6 +3,10 @@ package v1alpha1
 const (
 	SuccessfullyReconciled = "SuccessfullyReconciled"
 	ReconcileFailed        = "ReconcileFailed"
+	Suspended              = "Suspended"
+	Planned                = "Planned"
+	// RemovalThresholdExceeded is the reason of the RemovalBlocked condition
+	RemovalThresholdExceeded = "RemovalThresholdExceeded"
 
 	// MaxLDAPQueryDepth is the maximum nesting depth allowed for ldap_query filters.
 	MaxLDAPQueryDepth = 4
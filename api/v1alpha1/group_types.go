@@ -17,11 +17,16 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"slices"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	GroupReadyCondition = "GroupReadyCondition"
+	// RemovalBlockedCondition is set while the removal guard holds back membership removals
+	RemovalBlockedCondition = "RemovalBlocked"
 )
 
 type BackendStatus struct {
@@ -29,13 +34,56 @@ type BackendStatus struct {
 	Type    string `json:"type"`
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
+	// PendingChanges is the membership change that is not applied because the backend is suspended,
+	// or the removals that are blocked by the removal guard
+	// +optional
+	PendingChanges *MembershipDiff `json:"pendingChanges,omitempty"`
+}
+
+// MembershipDiff describes the changes needed to bring a backend team in sync with the group
+type MembershipDiff struct {
+	// UsersToAdd are the uids of the group members that are missing from the team
+	UsersToAdd []string `json:"usersToAdd,omitempty"`
+	// UsersToRemove are the team members that are not members of the group, by email when the
+	// backend reports it or by backend user ID otherwise
+	UsersToRemove []string `json:"usersToRemove,omitempty"`
+	// UsersToUpdate are the uids of the team members whose role differs from the desired one
+	UsersToUpdate []string `json:"usersToUpdate,omitempty"`
+	// ApprovalToken approves exactly these removals when it is listed in the
+	// operator.dataverse.redhat.com/approve-removals annotation, set for blocked removals only
+	// +optional
+	ApprovalToken string `json:"approvalToken,omitempty"`
 }
 
 type Backend struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// Suspend stops the changes to the team of this backend, see spec.suspend
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// DeletionPolicy overrides spec.deletion_policy for this backend
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// TeamName is the name of the team in this backend, it takes precedence over the name
+	// derived from group_name by the configured patterns and allows adopting an existing team
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	TeamName string `json:"teamName,omitempty"`
 }
 
+// DeletionPolicy defines what happens to the backend team when the Group CR is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the team from the backend
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the team and its members untouched in the backend
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan keeps the team in the backend but removes all of its members
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
 type LDAPFilter struct {
 	// +optional
 	// +kubebuilder:validation:Enum=givenName;displayName;rhatJobTitle;title;employeeType;manager;rhatCostCenter;rhatCostCenterDesc;rhatGeo;co;st;rhatLocation;rhatOfficeLocation;rhatOfficeFloor;roomNumber
@@ -71,16 +119,216 @@ type GroupSpec struct {
 	Members     Members      `json:"members"`
 	GroupParams []GroupParam `json:"group_params,omitempty"`
 	Backends    []Backend    `json:"backends"`
+	// DeletionPolicy defines what happens to the backend teams when the Group CR is deleted
+	// or a backend is removed from Backends, it can be overridden per backend
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// Suspend freezes the membership of the group in all its backends: teams and users are neither
+	// created, renamed, updated nor removed, the pending membership changes are only reported in status
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Owners are the uids of the owners of the group. They are members of the group with the owner
+	// level role of each backend: owner on rover and gitlab, Team Manager on fivetran.
+	// +optional
+	Owners []string `json:"owners,omitempty"`
+	// Contact is the contact email address of the group on the backends that have one, e.g. the
+	// contact list of rover groups
+	// +optional
+	Contact string `json:"contact,omitempty"`
+	// ResyncInterval is how often the group is reconciled again to pick up membership changes
+	// in LDAP, it defaults to controllerConfig.resyncInterval of the operator configuration
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+	// RemovalGuard overrides the removal guard of the operator configuration for this group
+	// +optional
+	RemovalGuard *RemovalGuard `json:"removal_guard,omitempty"`
+	// Nesting selects how the groups listed in members.groups are mapped to the backends: Flatten
+	// adds their members to the team one by one, Native nests their teams in the team on the
+	// backends that support it (rover group inclusions, gitlab group sharing, snowflake role grants)
+	// +kubebuilder:default=Flatten
+	// +optional
+	Nesting NestingMode `json:"nesting,omitempty"`
+	// Mode selects whether the membership changes are applied to the backends. Plan resolves the
+	// members and computes the changes of every backend like a suspended group, reporting them in
+	// status.backends[].pendingChanges, without changing the backends nor the cache.
+	// +kubebuilder:default=Apply
+	// +optional
+	Mode GroupMode `json:"mode,omitempty"`
 }
 
-// Members defines how group membership is resolved. When LDAPQuery is set, Users is optional
-// (members can come only from LDAP). When LDAPQuery is omitted, Users must be a non-empty list.
-//
-// +kubebuilder:validation:XValidation:rule="has(self.ldap_query) || (has(self.users) && size(self.users) > 0)",message="users must be a non-empty list when ldap_query is omitted"
+// GroupMode defines whether the reconciliation of a group applies the membership changes
+// +kubebuilder:validation:Enum=Apply;Plan
+type GroupMode string
+
+const (
+	// GroupModeApply applies the membership changes to the backends
+	GroupModeApply GroupMode = "Apply"
+	// GroupModePlan only computes and reports the membership changes
+	GroupModePlan GroupMode = "Plan"
+)
+
+// NestingMode defines how nested groups are mapped to the backend teams
+// +kubebuilder:validation:Enum=Flatten;Native
+type NestingMode string
+
+const (
+	// NestingFlatten adds the members of the nested groups to the team individually
+	NestingFlatten NestingMode = "Flatten"
+	// NestingNative nests the teams of the nested groups in the team on the backends that support it
+	NestingNative NestingMode = "Native"
+)
+
+// RemovalGuard limits how many members a single reconciliation may remove from a backend team.
+// Removals above either limit are not applied until they are approved with the
+// operator.dataverse.redhat.com/approve-removals annotation. A limit of 0 disables it.
+type RemovalGuard struct {
+	// MaxRemovals is the maximum number of members removed from a team at once
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRemovals *int32 `json:"max_removals,omitempty"`
+	// MaxRemovalPercent is the maximum percentage of the current team members removed at once
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxRemovalPercent *int32 `json:"max_removal_percent,omitempty"`
+}
+
+// UsesNativeNesting reports whether the nested groups are mapped to backend-native nesting
+func (s *GroupSpec) UsesNativeNesting() bool {
+	return s.Nesting == NestingNative
+}
+
+// IsPlan reports whether the group only plans the membership changes, see spec.mode
+func (s *GroupSpec) IsPlan() bool {
+	return s.Mode == GroupModePlan
+}
+
+// AppliesChanges reports whether the membership changes of the given backend are applied, they are
+// only computed when the group is in plan mode or the backend is suspended
+func (s *GroupSpec) AppliesChanges(backend Backend) bool {
+	return !s.IsPlan() && !s.IsSuspended(backend)
+}
+
+// IsSuspended reports whether the changes to the team of the given backend of the group are suspended
+func (s *GroupSpec) IsSuspended(backend Backend) bool {
+	return s.Suspend || backend.Suspend
+}
+
+// HasSuspendedBackends reports whether the changes to the team of any backend of the group are suspended
+func (s *GroupSpec) HasSuspendedBackends() bool {
+	return s.Suspend || slices.ContainsFunc(s.Backends, func(backend Backend) bool { return backend.Suspend })
+}
+
+// DeletionPolicyFor returns the deletion policy that applies to the given backend of the group
+func (s *GroupSpec) DeletionPolicyFor(backend Backend) DeletionPolicy {
+	if backend.DeletionPolicy != "" {
+		return backend.DeletionPolicy
+	}
+	if s.DeletionPolicy != "" {
+		return s.DeletionPolicy
+	}
+	return DeletionPolicyDelete
+}
+
+// Members defines how group membership is resolved. When LDAPQuery, LDAPGroups or ServiceAccounts is set,
+// Users is optional (members can come only from LDAP or be service accounts). Otherwise Users or
+// TimeBoundUsers must be a non-empty list.
+// +kubebuilder:validation:XValidation:rule="has(self.ldap_query) || (has(self.ldap_groups) && size(self.ldap_groups) > 0) || (has(self.users) && size(self.users) > 0) || (has(self.time_bound_users) && size(self.time_bound_users) > 0) || (has(self.service_accounts) && size(self.service_accounts) > 0)",message="users or time_bound_users must be a non-empty list when ldap_query, ldap_groups and service_accounts are omitted"
 type Members struct {
 	Groups    []string   `json:"groups,omitempty"`
 	Users     []string   `json:"users,omitempty"`
 	LDAPQuery *LDAPQuery `json:"ldap_query,omitempty"`
+	// LDAPGroups are DNs of existing LDAP groups (groupOfNames, groupOfUniqueNames or posixGroup)
+	// whose members, including the ones of nested LDAP groups, are members of the group.
+	// +optional
+	LDAPGroups []string `json:"ldap_groups,omitempty"`
+	// TimeBoundUsers are users that are members of the group only within their time window.
+	// +optional
+	TimeBoundUsers []TimeBoundUser `json:"time_bound_users,omitempty"`
+	// Roles assigns backend specific roles to members of the group. Members without a role
+	// get the default role of the backend. Users listed here must also be members of the group.
+	// +optional
+	Roles []MemberRole `json:"roles,omitempty"`
+	// Exclude removes users from the resolved members of the group, regardless of whether they
+	// come from users, time_bound_users, nested groups or the ldap_query.
+	// +optional
+	Exclude *MemberExclusion `json:"exclude,omitempty"`
+	// ServiceAccounts are non-human members that don't exist in LDAP. They are created on the
+	// backends as service users, get the default role and are never offboarded.
+	// +optional
+	ServiceAccounts []ServiceAccount `json:"service_accounts,omitempty"`
+}
+
+// ServiceAccount is a non-human member of the group, created as a Snowflake user of
+// TYPE=SERVICE, a GitLab service account user or a Rover serviceaccount member.
+type ServiceAccount struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Email is optional and only used by backends that accept one for service users
+	// +optional
+	Email string `json:"email,omitempty"`
+}
+
+// MemberExclusion lists the users that must never be members of the group, either explicitly
+// by uid or as the result of an LDAP query.
+type MemberExclusion struct {
+	// +optional
+	Users []string `json:"users,omitempty"`
+	// +optional
+	LDAPQuery *LDAPQuery `json:"ldap_query,omitempty"`
+}
+
+// MemberRole assigns a role on all backends of the given type to a set of group members,
+// e.g. maintainer on gitlab, Team Manager on fivetran or owner on rover.
+type MemberRole struct {
+	// +kubebuilder:validation:Enum=fivetran;gitlab;rover
+	Backend string `json:"backend"`
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
+	// +kubebuilder:validation:MinItems=1
+	Users []string `json:"users"`
+}
+
+// TimeBoundUser is a user that is a member of the group from NotBefore until ExpiresAt.
+// Either bound may be omitted to leave that side of the window open.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.notBefore) || !has(self.expiresAt) || timestamp(self.notBefore) < timestamp(self.expiresAt)",message="notBefore must be before expiresAt"
+type TimeBoundUser struct {
+	// +kubebuilder:validation:MinLength=1
+	UID string `json:"uid"`
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// IsActive reports whether the user is a member of the group at the given time
+func (u *TimeBoundUser) IsActive(now time.Time) bool {
+	if u.NotBefore != nil && now.Before(u.NotBefore.Time) {
+		return false
+	}
+	return u.ExpiresAt == nil || now.Before(u.ExpiresAt.Time)
+}
+
+// NextBoundary returns the next time after now at which the membership of the user changes,
+// or the zero time if it doesn't change anymore
+func (u *TimeBoundUser) NextBoundary(now time.Time) time.Time {
+	if u.NotBefore != nil && now.Before(u.NotBefore.Time) {
+		return u.NotBefore.Time
+	}
+	if u.ExpiresAt != nil && now.Before(u.ExpiresAt.Time) {
+		return u.ExpiresAt.Time
+	}
+	return time.Time{}
+}
+
+// MemberExpiry reports when a time bound user of the group loses its membership
+type MemberExpiry struct {
+	User string `json:"user"`
+	// Group is the Group CR that declares the time bound user
+	Group     string      `json:"group"`
+	ExpiresAt metav1.Time `json:"expiresAt"`
 }
 
 type GroupParam struct {
@@ -97,12 +345,21 @@ type GroupStatus struct {
 	Conditions            []metav1.Condition `json:"conditions,omitempty"`
 	LastAppliedGeneration int64              `json:"lastAppliedGeneration,omitempty"`
 	BackendsStatus        []BackendStatus    `json:"backends,omitempty"`
+	// AppliedGroupName is the group name the backend teams and cache records belong to,
+	// it differs from spec.group_name while a rename is in progress
+	AppliedGroupName string `json:"appliedGroupName,omitempty"`
+	// ExcludedUsers lists the resolved members of the group that were removed by spec.members.exclude
+	ExcludedUsers []string `json:"excludedUsers,omitempty"`
+	// UpcomingExpiries lists the time bound users of the group that are going to expire, soonest first
+	UpcomingExpiries []MemberExpiry `json:"upcomingExpiries,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="GroupReadyCondition")].status`
 // +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="GroupReadyCondition")].message`
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
 
 // Group is the Schema for the groups API
 type Group struct {
@@ -143,6 +400,60 @@ func (c *Group) SetWaiting() {
 	c.Status.Conditions = append(c.Status.Conditions, condition)
 }
 
+// SetSuspended marks the group as suspended, the last applied generation is left untouched
+// because the spec is not applied to the backends
+func (c *Group) SetSuspended() {
+	c.setCondition(metav1.Condition{
+		Type:               GroupReadyCondition,
+		LastTransitionTime: metav1.Now(),
+		Status:             metav1.ConditionFalse,
+		Message:            "Group is suspended, membership changes are not applied",
+		Reason:             Suspended,
+	})
+}
+
+// SetPlanned marks the group as planned, the last applied generation is left untouched
+// because the spec is not applied to the backends
+func (c *Group) SetPlanned() {
+	c.setCondition(metav1.Condition{
+		Type:               GroupReadyCondition,
+		LastTransitionTime: metav1.Now(),
+		Status:             metav1.ConditionFalse,
+		Message:            "Group is in plan mode, membership changes are not applied",
+		Reason:             Planned,
+	})
+}
+
+// SetRemovalBlocked sets the RemovalBlocked condition, message tells which removals are blocked
+// and how to approve them
+func (c *Group) SetRemovalBlocked(message string) {
+	c.setCondition(metav1.Condition{
+		Type:               RemovalBlockedCondition,
+		LastTransitionTime: metav1.Now(),
+		Status:             metav1.ConditionTrue,
+		Message:            message,
+		Reason:             RemovalThresholdExceeded,
+	})
+}
+
+// ClearRemovalBlocked drops the RemovalBlocked condition once no removals are blocked anymore
+func (c *Group) ClearRemovalBlocked() {
+	c.Status.Conditions = slices.DeleteFunc(c.Status.Conditions, func(condition metav1.Condition) bool {
+		return condition.Type == RemovalBlockedCondition
+	})
+}
+
+// setCondition replaces the condition of the same type or appends it
+func (c *Group) setCondition(condition metav1.Condition) {
+	for i, currentCondition := range c.Status.Conditions {
+		if currentCondition.Type == condition.Type {
+			c.Status.Conditions[i] = condition
+			return
+		}
+	}
+	c.Status.Conditions = append(c.Status.Conditions, condition)
+}
+
 func (c *Group) UpdateStatus(isError bool) {
 	condition := metav1.Condition{
 		Type:               GroupReadyCondition,
@@ -43,6 +43,11 @@ func (in *Backend) DeepCopy() *Backend {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendStatus) DeepCopyInto(out *BackendStatus) {
 	*out = *in
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = new(MembershipDiff)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendStatus.
@@ -150,6 +155,21 @@ func (in *GroupSpec) DeepCopyInto(out *GroupSpec) {
 		*out = make([]Backend, len(*in))
 		copy(*out, *in)
 	}
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RemovalGuard != nil {
+		in, out := &in.RemovalGuard, &out.RemovalGuard
+		*out = new(RemovalGuard)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSpec.
@@ -180,8 +200,22 @@ func (in *GroupStatus) DeepCopyInto(out *GroupStatus) {
 	if in.BackendsStatus != nil {
 		in, out := &in.BackendsStatus, &out.BackendsStatus
 		*out = make([]BackendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedUsers != nil {
+		in, out := &in.ExcludedUsers, &out.ExcludedUsers
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UpcomingExpiries != nil {
+		in, out := &in.UpcomingExpiries, &out.UpcomingExpiries
+		*out = make([]MemberExpiry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupStatus.
@@ -256,6 +290,67 @@ func (in *LDAPQuery) DeepCopy() *LDAPQuery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberExclusion) DeepCopyInto(out *MemberExclusion) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LDAPQuery != nil {
+		in, out := &in.LDAPQuery, &out.LDAPQuery
+		*out = new(LDAPQuery)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberExclusion.
+func (in *MemberExclusion) DeepCopy() *MemberExclusion {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberExclusion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberExpiry) DeepCopyInto(out *MemberExpiry) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberExpiry.
+func (in *MemberExpiry) DeepCopy() *MemberExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberRole) DeepCopyInto(out *MemberRole) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberRole.
+func (in *MemberRole) DeepCopy() *MemberRole {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Members) DeepCopyInto(out *Members) {
 	*out = *in
@@ -274,6 +369,35 @@ func (in *Members) DeepCopyInto(out *Members) {
 		*out = new(LDAPQuery)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LDAPGroups != nil {
+		in, out := &in.LDAPGroups, &out.LDAPGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeBoundUsers != nil {
+		in, out := &in.TimeBoundUsers, &out.TimeBoundUsers
+		*out = make([]TimeBoundUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]MemberRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = new(MemberExclusion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ServiceAccount, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Members.
@@ -285,3 +409,96 @@ func (in *Members) DeepCopy() *Members {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MembershipDiff) DeepCopyInto(out *MembershipDiff) {
+	*out = *in
+	if in.UsersToAdd != nil {
+		in, out := &in.UsersToAdd, &out.UsersToAdd
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsersToRemove != nil {
+		in, out := &in.UsersToRemove, &out.UsersToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsersToUpdate != nil {
+		in, out := &in.UsersToUpdate, &out.UsersToUpdate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MembershipDiff.
+func (in *MembershipDiff) DeepCopy() *MembershipDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(MembershipDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemovalGuard) DeepCopyInto(out *RemovalGuard) {
+	*out = *in
+	if in.MaxRemovals != nil {
+		in, out := &in.MaxRemovals, &out.MaxRemovals
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRemovalPercent != nil {
+		in, out := &in.MaxRemovalPercent, &out.MaxRemovalPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemovalGuard.
+func (in *RemovalGuard) DeepCopy() *RemovalGuard {
+	if in == nil {
+		return nil
+	}
+	out := new(RemovalGuard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccount) DeepCopyInto(out *ServiceAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccount.
+func (in *ServiceAccount) DeepCopy() *ServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeBoundUser) DeepCopyInto(out *TimeBoundUser) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeBoundUser.
+func (in *TimeBoundUser) DeepCopy() *TimeBoundUser {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeBoundUser)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -44,14 +45,17 @@ import (
 
 	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
 	"github.com/redhat-data-and-ai/usernaut/internal/controller"
+	webhookv1alpha1 "github.com/redhat-data-and-ai/usernaut/internal/webhook/v1alpha1"
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/snowflake"
 	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
 	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
 	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+	"github.com/redhat-data-and-ai/usernaut/pkg/utils"
 	"github.com/sirupsen/logrus"
 
 	// +kubebuilder:scaffold:imports
@@ -75,11 +79,16 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableWebhooks bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -91,6 +100,8 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the Group admission webhooks are served. Requires serving certificates for the webhook server.")
 	opts := zap.Options{
 		Development: false,
 	}
@@ -194,29 +205,43 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create shared cache mutex to prevent race conditions between GroupReconciler and UserOffboardingJob
-	sharedCacheMutex := &sync.RWMutex{}
+	// Create the locker shared by the GroupReconciler, the UserOffboardingJob and the preload, the locks are
+	// held in Redis with the redis cache driver so that they are shared with the other replicas
+	sharedLocker := locker.New(cache)
 
 	// Create store layer that wraps cache with prefixed keys and encapsulated operations
 	dataStore := store.New(cache)
 
-	if err = preloadCache(*appConf, dataStore, sharedCacheMutex); err != nil {
+	if err = migrateStore(context.Background(), dataStore, sharedLocker, ldapConn); err != nil {
+		setupLog.Error(err, "failed to migrate cache records")
+		os.Exit(1)
+	}
+
+	if err = preloadCache(*appConf, dataStore, sharedLocker); err != nil {
 		setupLog.Error(err, "failed to preload cache")
 		os.Exit(1)
 	}
 
 	if err = (&controller.GroupReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		AppConfig:  appConf,
-		Store:      dataStore,
-		LdapConn:   ldapConn,
-		CacheMutex: sharedCacheMutex,
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		AppConfig: appConf,
+		Store:     dataStore,
+		LdapConn:  ldapConn,
+		Locker:    sharedLocker,
+		Recorder:  mgr.GetEventRecorderFor("group-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Group")
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		if err = webhookv1alpha1.SetupGroupWebhookWithManager(mgr, appConf); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Group")
+			os.Exit(1)
+		}
+	}
+
 	// Initialize backend clients for the periodic tasks
 	backendClients := make(map[string]clients.Client)
 	for _, backend := range appConf.Backends {
@@ -232,7 +257,7 @@ func main() {
 	}
 
 	ptr, err := controller.NewPeriodicTasksReconciler(
-		mgr.GetClient(), sharedCacheMutex, cache, dataStore, ldapConn, backendClients)
+		mgr.GetClient(), sharedLocker, cache, dataStore, ldapConn, backendClients)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PeriodicTasks")
 		os.Exit(1)
@@ -268,11 +293,9 @@ func main() {
 
 // storeUsersInCache stores users in the cache and returns an error if any user fails to be stored
 func storeUsersInCache(ctx context.Context, users map[string]*structs.User, dataStore *store.Store,
-	cacheMutex *sync.RWMutex, backendKey string, log *logrus.Entry) error {
+	userLocker locker.Locker, backendKey string, log *logrus.Entry) error {
 	for _, user := range users {
-		cacheMutex.Lock()
-		err := dataStore.User.SetBackend(ctx, user.GetEmail(), backendKey, user.ID)
-		cacheMutex.Unlock()
+		err := storeUserInCache(ctx, dataStore, userLocker, user, backendKey)
 		if err != nil {
 			log.WithError(err).Error("failed to store user in cache")
 			return err
@@ -281,6 +304,143 @@ func storeUsersInCache(ctx context.Context, users map[string]*structs.User, data
 	return nil
 }
 
+// storeUserInCache stores the ID of the user in the backend while holding the lock of the user,
+// whose record is shared with the group reconciliations. The backend users are found by email, the
+// ID is stored under the uid linked to the email or under the email until a group links it.
+func storeUserInCache(ctx context.Context, dataStore *store.Store, userLocker locker.Locker,
+	user *structs.User, backendKey string) error {
+	email := user.GetEmail()
+	for {
+		uid, err := dataStore.User.GetUIDByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+		userKey := email
+		if uid != "" {
+			userKey = uid
+		}
+
+		stored := false
+		err = locker.Do(ctx, userLocker, []string{locker.UserKey(email), locker.UserKey(userKey)},
+			func(ctx context.Context) error {
+				// the email is linked while holding its lock, it is looked up again in case a group
+				// linked it in the meantime
+				current, err := dataStore.User.GetUIDByEmail(ctx, email)
+				if err != nil || current != uid {
+					return err
+				}
+				stored = true
+				return dataStore.User.SetBackend(ctx, userKey, backendKey, user.ID)
+			})
+		if err != nil || stored {
+			return err
+		}
+	}
+}
+
+// migrateStore applies the pending migrations of the store, see store.Migrations. It runs before the
+// preload and the controllers use the store, while holding the migration lock so that replicas
+// starting together don't migrate the records concurrently.
+func migrateStore(ctx context.Context, dataStore *store.Store, storeLocker locker.Locker,
+	ldapConn ldap.LDAPClient) error {
+	return locker.Do(ctx, storeLocker, []string{locker.MigrationKey}, func(ctx context.Context) error {
+		applied, err := dataStore.Migrate(ctx, store.Migrations(ldapUIDResolver(ldapConn)))
+		for _, m := range applied {
+			setupLog.Info("applied store migration",
+				"version", m.Version, "description", m.Description, "records", m.Records)
+		}
+		return err
+	})
+}
+
+// ldapUIDResolver returns a store.UIDResolver looking the users up in LDAP by email. The users that
+// can't be looked up are kept under their email, a group they are a member of moves them to their uid.
+func ldapUIDResolver(ldapConn ldap.LDAPClient) store.UIDResolver {
+	return func(ctx context.Context, email string) (string, error) {
+		userData, err := ldapConn.GetUserLDAPDataByEmail(ctx, email)
+		if errors.Is(err, ldap.ErrNoUserFound) {
+			return "", nil
+		}
+		if err != nil {
+			setupLog.Error(err, "failed to look up user in LDAP, keeping it cached by email",
+				"email", logger.MaskEmail(email))
+			return "", nil
+		}
+		ldapUser := &structs.LDAPUser{}
+		if err := utils.MapToStruct(userData, ldapUser); err != nil {
+			setupLog.Error(err, "failed to read the uid of user, keeping it cached by email",
+				"email", logger.MaskEmail(email))
+			return "", nil
+		}
+		return ldapUser.GetUID(), nil
+	}
+}
+
+// runMigrate implements the migrate command, which applies the pending migrations of the store
+// without starting the controllers, e.g. from a Job run before rolling out a version that changes the
+// layout of the records. With --dry-run it only lists them.
+func runMigrate(args []string) int {
+	var dryRun bool
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.BoolVar(&dryRun, "dry-run", false, "If set, the pending migrations are listed but not applied.")
+	opts := zap.Options{
+		Development: false,
+	}
+	opts.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctx := context.Background()
+
+	appConf, err := config.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to create config")
+		return 1
+	}
+	if appConf.Cache.Driver != cache.DriverRedis {
+		setupLog.Info("the records of the cache driver don't outlive the process, nothing to migrate",
+			"driver", appConf.Cache.Driver)
+		return 0
+	}
+
+	storeCache, err := cache.New(&appConf.Cache)
+	if err != nil {
+		setupLog.Error(err, "failed to initialize cache")
+		return 1
+	}
+	dataStore := store.New(storeCache)
+
+	version, err := dataStore.SchemaVersion(ctx)
+	if err != nil {
+		setupLog.Error(err, "failed to get the schema version of the store")
+		return 1
+	}
+	setupLog.Info("store schema version", "version", version)
+
+	if dryRun {
+		pending, err := dataStore.PendingMigrations(ctx, store.Migrations(nil))
+		if err != nil {
+			setupLog.Error(err, "failed to list the pending store migrations")
+			return 1
+		}
+		for _, m := range pending {
+			setupLog.Info("pending store migration", "version", m.Version, "description", m.Description)
+		}
+		return 0
+	}
+
+	ldapConn, err := ldap.InitLdap(appConf.LDAP)
+	if err != nil {
+		setupLog.Error(err, "failed to initialize LDAP connection")
+		return 1
+	}
+	if err := migrateStore(ctx, dataStore, locker.New(storeCache), ldapConn); err != nil {
+		setupLog.Error(err, "failed to migrate cache records")
+		return 1
+	}
+	return 0
+}
+
 // snowflakeAsyncState holds state needed for Snowflake async continuation after preload
 type snowflakeAsyncState struct {
 	client     *snowflake.SnowflakeClient
@@ -294,7 +454,7 @@ type snowflakeAsyncState struct {
 // This is done only once at the start of the application
 // and the cache is flushed when the application is restarted
 // Optimized to use goroutines for parallel processing of backends
-func preloadCache(appConfig config.AppConfig, dataStore *store.Store, cacheMutex *sync.RWMutex) error {
+func preloadCache(appConfig config.AppConfig, dataStore *store.Store, storeLocker locker.Locker) error {
 	ctx := context.Background()
 
 	// Add request ID for tracking this cache preload operation in logs
@@ -354,7 +514,7 @@ func preloadCache(appConfig config.AppConfig, dataStore *store.Store, cacheMutex
 					return err
 				}
 
-				if err := storeUsersInCache(ctx, users, dataStore, cacheMutex, backendKey, log); err != nil {
+				if err := storeUsersInCache(ctx, users, dataStore, storeLocker, backendKey, log); err != nil {
 					return err
 				}
 
@@ -379,7 +539,7 @@ func preloadCache(appConfig config.AppConfig, dataStore *store.Store, cacheMutex
 					return err
 				}
 
-				if err := storeUsersInCache(ctx, users, dataStore, cacheMutex, backendKey, log); err != nil {
+				if err := storeUsersInCache(ctx, users, dataStore, storeLocker, backendKey, log); err != nil {
 					return err
 				}
 
@@ -390,25 +550,26 @@ func preloadCache(appConfig config.AppConfig, dataStore *store.Store, cacheMutex
 			// Teams are stored by their transformed name (as returned by the backend)
 			// During reconciliation, if a team is not found in GroupStore, it will
 			// fallback to TeamStore and migrate the data to GroupStore
-			teams, err := backendClient.FetchAllTeams(ctx)
-			if err != nil {
-				log.WithError(err).Error("failed to fetch teams from backend")
-				return err
-			}
-
-			for _, team := range teams {
-				cacheMutex.Lock()
-				err := dataStore.Team.SetBackend(ctx, team.GetName(), backendKey, team.ID)
-				cacheMutex.Unlock()
+			// The reconciliations of the other replicas may rename or release teams meanwhile, the teams
+			// lock of the backend is held from the fetch to the last write so that none of them is undone
+			return locker.Do(ctx, storeLocker, []string{locker.TeamsKey(backendKey)}, func(ctx context.Context) error {
+				teams, err := backendClient.FetchAllTeams(ctx)
 				if err != nil {
-					log.WithError(err).Error("failed to store team in cache")
+					log.WithError(err).Error("failed to fetch teams from backend")
 					return err
 				}
-			}
 
-			log.WithField("teams", len(teams)).Info("successfully preloaded teams from backend")
+				for _, team := range teams {
+					err := dataStore.Team.SetBackend(ctx, team.GetName(), backendKey, team.ID)
+					if err != nil {
+						log.WithError(err).Error("failed to store team in cache")
+						return err
+					}
+				}
 
-			return nil
+				log.WithField("teams", len(teams)).Info("successfully preloaded teams from backend")
+				return nil
+			})
 		})
 	}
 
@@ -422,7 +583,7 @@ func preloadCache(appConfig config.AppConfig, dataStore *store.Store, cacheMutex
 	// Start async continuation for all Snowflake backends (after all preloads done)
 	for _, state := range snowflakeStates {
 		if state.lastUser != "" {
-			startSnowflakeAsyncContinuation(ctx, state, dataStore, cacheMutex)
+			startSnowflakeAsyncContinuation(ctx, state, dataStore, storeLocker)
 		}
 	}
 
@@ -435,7 +596,7 @@ func startSnowflakeAsyncContinuation(
 	originalCtx context.Context,
 	state *snowflakeAsyncState,
 	dataStore *store.Store,
-	cacheMutex *sync.RWMutex,
+	userLocker locker.Locker,
 ) {
 	// Create a fresh context since the errgroup context is canceled after g.Wait() returns
 	// Transfer the logger (with request ID) from original context for traceability
@@ -455,9 +616,7 @@ func startSnowflakeAsyncContinuation(
 		failedCount := 0
 
 		for user := range userChan {
-			cacheMutex.Lock()
-			err := dataStore.User.SetBackend(asyncCtx, user.GetEmail(), state.backendKey, user.ID)
-			cacheMutex.Unlock()
+			err := storeUserInCache(asyncCtx, dataStore, userLocker, user, state.backendKey)
 
 			if err != nil {
 				failedCount++
@@ -0,0 +1,45 @@
+package controllerutils
+
+import (
+	"context"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/constants"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+func ApproveRemovalsPredicate() predicate.Predicate {
+	return CustomAnnotationChangedPredicate{AnnotationKey: constants.ApproveRemovalsAnnotation}
+}
+
+// Custom Predicate to filter by the value of a specific annotation
+type CustomAnnotationChangedPredicate struct {
+	AnnotationKey string
+	predicate.Funcs
+}
+
+// Custom Predicate annotation to reconcile when the annotation is set or its value changes
+func (p CustomAnnotationChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	oldValue := e.ObjectOld.GetAnnotations()[p.AnnotationKey]
+	newValue := e.ObjectNew.GetAnnotations()[p.AnnotationKey]
+
+	return newValue != "" && newValue != oldValue
+}
+
+// RemoveApproveRemovalsAnnotation drops the removal approval once it is not needed anymore,
+// obj is refreshed from the API server so it must be called after its status is updated
+func RemoveApproveRemovalsAnnotation(ctx context.Context, c client.Client, obj client.Object) error {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[constants.ApproveRemovalsAnnotation]; !ok {
+		return nil
+	}
+
+	patch := []byte(`{"metadata":{"annotations":{"` + constants.ApproveRemovalsAnnotation + `":null}}}`)
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
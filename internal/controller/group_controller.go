@@ -18,16 +18,22 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
+	"math/rand/v2"
 	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,20 +51,44 @@ import (
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/gitlab"
 
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/constants"
 	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
 	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 	"github.com/redhat-data-and-ai/usernaut/pkg/store"
 	"github.com/redhat-data-and-ai/usernaut/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	groupFinalizer = "operator.dataverse.redhat.com/finalizer"
 
-	// requeueAfter is the duration after which the group controller will requeue the group for reconciliation
+	// defaultResyncInterval is the duration after which the group controller will requeue the group for reconciliation
+	// when neither the group nor the controller configuration set one,
 	// this takes care of updating users in ldap query based groups
-	requeueAfter = 8 * time.Hour
+	defaultResyncInterval = 8 * time.Hour
+
+	// resyncJitterFactor spreads the resyncs of groups that were reconciled at the same time
+	// by delaying each of them by up to this fraction of the resync interval
+	resyncJitterFactor = 0.1
+
+	// removalApprovalTokenLength is the number of hex characters of a removal approval token
+	removalApprovalTokenLength = 12
+
+	// indirectReportsBatchSize is the number of managers whose reports are fetched with a single
+	// LDAP search while expanding the indirect reports of a query
+	indirectReportsBatchSize = 50
+
+	// maxConcurrentLDAPSearches is the number of LDAP searches run at the same time while expanding
+	// the indirect reports of a query
+	maxConcurrentLDAPSearches = 4
+
+	// defaultMaxConcurrentBackends is the number of backends of a group processed at the same time
+	// when the controller configuration doesn't set one
+	defaultMaxConcurrentBackends = 4
 )
 
 // GroupReconciler reconciles a Group object
@@ -71,20 +101,34 @@ type GroupReconciler struct {
 	backendLogger   *logrus.Entry
 	LdapConn        ldap.LDAPClient
 	allLdapUserData map[string]*structs.LDAPUser
+	// Recorder records the team creations, membership changes, LDAP misses and backend failures
+	// as events on the Group CRs
+	Recorder record.EventRecorder
 
-	// CacheMutex prevents concurrent access to the cache during group reconciliation.
-	// This shared mutex ensures that the group controller and user offboarding job don't interfere
-	// with each other when reading or modifying user/team data in Redis.
-	// This mutex is shared across components and passed from main.go.
-	CacheMutex *sync.RWMutex
+	// Locker serializes the updates of the cache records of the groups and users, it is shared
+	// with the offboarding job and, with the redis cache driver, with the other replicas
+	Locker locker.Locker
 }
 
 //nolint:lll
 // +kubebuilder:rbac:groups=operator.dataverse.redhat.com,namespace=usernaut,resources=groups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.dataverse.redhat.com,namespace=usernaut,resources=groups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operator.dataverse.redhat.com,namespace=usernaut,resources=groups/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",namespace=usernaut,resources=events,verbs=create;patch
 
 func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// the reconciler keeps the loggers and LDAP data of a reconciliation in its fields, each
+	// reconciliation works on its own copy so that groups are reconciled concurrently
+	worker := *r
+	return worker.reconcile(ctx, req)
+}
+
+func (r *GroupReconciler) reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveReconcile(req.Name, time.Since(start), err)
+	}()
+
 	ctx = logger.WithRequestId(ctx, controller.ReconcileIDFromContext(ctx))
 	r.log = logger.Logger(ctx).WithFields(logrus.Fields{
 		"request": req.NamespacedName.String(),
@@ -116,6 +160,7 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 
 	// set the group status as waiting
+	seedAppliedGroupName(groupCR)
 	groupCR.SetWaiting()
 	if err := r.Status().Update(ctx, groupCR); err != nil {
 		r.log.WithError(err).Error("error updating the status")
@@ -126,59 +171,111 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		"request":        req.NamespacedName.String(),
 		"group":          groupCR.Spec.GroupName,
 		"has_ldap_query": groupCR.Spec.Members.LDAPQuery != nil,
+		"ldap_groups":    groupCR.Spec.Members.LDAPGroups,
 		"members":        len(groupCR.Spec.Members.Users),
 		"groups":         groupCR.Spec.Members.Groups,
 	})
 
-	var err error
 	queryMembers := []string{}
 	if groupCR.Spec.Members.LDAPQuery != nil {
-		includeIndirectReports := groupCR.Spec.Members.LDAPQuery.Options != nil && groupCR.Spec.Members.LDAPQuery.Options.IncludeIndirectReports
-		includeManager := groupCR.Spec.Members.LDAPQuery.Options != nil && groupCR.Spec.Members.LDAPQuery.Options.IncludeManager
-		queryMembers, err = r.fetchQueryMembers(ctx, groupCR.Spec.Members.LDAPQuery, includeIndirectReports, nil)
+		queryMembers, err = r.resolveLDAPQueryMembers(ctx, groupCR.Spec.Members.LDAPQuery)
 		if err != nil {
 			r.log.WithError(err).Error("error fetching query members")
 			return ctrl.Result{}, err
 		}
-		if includeManager {
-			queryMembers = append(queryMembers, extractManagerUIDsFromQuery(groupCR.Spec.Members.LDAPQuery)...)
-		}
 		r.log.WithField("query_members_count", len(queryMembers)).Info("query members fetched successfully")
 	}
 
-	visitedGroups := make(map[string]struct{})
-	allDeclaredMembers, err := r.fetchUniqueGroupMembers(ctx, req.Name, groupCR.Namespace, visitedGroups)
+	if len(groupCR.Spec.Members.LDAPGroups) > 0 {
+		ldapGroupMembers, err := r.fetchLDAPGroupMembers(ctx, groupCR.Spec.Members.LDAPGroups)
+		if err != nil {
+			r.log.WithError(err).Error("error fetching ldap group members")
+			return ctrl.Result{}, err
+		}
+		r.log.WithField("ldap_group_members_count", len(ldapGroupMembers)).Info("ldap group members fetched successfully")
+		queryMembers = append(queryMembers, ldapGroupMembers...)
+	}
+
+	timeBound := &timeBoundMembers{now: time.Now()}
+	directMembers, nestedGroups, err := r.fetchNestedGroups(ctx, groupCR, timeBound)
 	if err != nil {
 		r.log.WithError(err).Error("error fetching unique group members")
 		return ctrl.Result{}, err
 	}
+	directMembers = append(directMembers, queryMembers...)
+
+	allDeclaredMembers := slices.Clone(directMembers)
+	for _, nested := range nestedGroups {
+		allDeclaredMembers = append(allDeclaredMembers, nested.members...)
+	}
+	uniqueMembers := r.deduplicateMembers(allDeclaredMembers)
+
+	excludedMembers, err := r.fetchExcludedMembers(ctx, groupCR.Spec.Members.Exclude)
+	if err != nil {
+		r.log.WithError(err).Error("error fetching excluded members")
+		return ctrl.Result{}, err
+	}
+	uniqueMembers, excludedUsers := excludeMembers(uniqueMembers, excludedMembers)
+	if len(excludedUsers) > 0 {
+		r.log.WithField("excluded_users", excludedUsers).Info("excluded users removed from the group members")
+	}
 
-	uniqueMembers := r.deduplicateMembers(append(allDeclaredMembers, queryMembers...))
+	var nesting *groupNesting
+	if groupCR.Spec.UsesNativeNesting() {
+		nesting = r.newGroupNesting(directMembers, nestedGroups, excludedMembers)
+	}
 
 	r.log.WithField("unique_members", len(uniqueMembers)).Info("unique members to be reconciled")
 	groupCR.Status.ReconciledUsers = uniqueMembers
+	groupCR.Status.ExcludedUsers = excludedUsers
+	groupCR.Status.UpcomingExpiries = timeBound.sortedExpiries()
 
 	r.log.Info("fetching LDAP data for the users in the group")
 
-	// Lock cache for all read/write operations during reconciliation
-	// This prevents race conditions when multiple Group CRs reference the same users/teams
-	// and their reconciliations run concurrently
-	r.CacheMutex.Lock()
-	defer r.CacheMutex.Unlock()
+	// Lock the group for the rest of the reconciliation, the Group CRs sharing its group_name and
+	// the other replicas wait for it. The users are only locked while their records are updated.
+	lease, err := r.lockGroup(ctx, groupCR)
+	if err != nil {
+		r.log.WithError(err).Error("error locking group")
+		return ctrl.Result{}, err
+	}
+	defer r.unlock(lease)
+	ctx = lease.Context()
+
+	r.log.Info("acquired group lock")
 
-	r.log.Info("Acquired cache lock for entire reconciliation (LDAP + backends)")
+	// Step 0: Move the backend teams and cache records of a renamed group to the new name
+	if err := r.applyGroupName(ctx, groupCR); err != nil {
+		r.log.WithError(err).Error("error renaming group")
+		return ctrl.Result{}, err
+	}
 
-	// Step 1: Fetch LDAP data (does NOT update cache indexes)
-	ldapResult, err := r.fetchLDAPData(ctx, uniqueMembers)
+	// Step 1: Fetch LDAP data (does NOT update cache indexes), suspended groups and groups in plan
+	// mode leave the identity records of the users untouched
+	linkIdentities := !groupCR.Spec.Suspend && !groupCR.Spec.IsPlan()
+	ldapResult, err := r.fetchLDAPData(ctx, uniqueMembers, linkIdentities)
 	if err != nil {
 		r.log.WithError(err).Error("LDAP bulk fetch failed; skipping backends until retry")
 		return ctrl.Result{}, err
 	}
+	if len(ldapResult.MissingUsers) > 0 {
+		r.recordEvent(groupCR, corev1.EventTypeWarning, eventReasonLDAPUsersNotFound,
+			"%d members not found in LDAP: %s", len(ldapResult.MissingUsers), summarizeUsers(ldapResult.MissingUsers))
+	}
 
-	// Step 2: Process all backends (cache operations protected by lock)
-	backendErrors := r.processAllBackends(ctx, groupCR, uniqueMembers)
+	// Step 2: Process all backends, suspended backends
+	// and groups in plan mode only report the changes they would apply
+	backendErrors, pendingChanges := r.processAllBackends(ctx, groupCR, uniqueMembers, nesting)
+
+	// Release the teams of backends that were dropped from the spec since the last reconciliation
+	removedBackends, err := r.cleanupRemovedBackends(ctx, groupCR)
+	if err != nil {
+		r.log.WithError(err).Error("error cleaning up backends removed from the spec")
+		return ctrl.Result{}, err
+	}
 
-	// Step 3: Only update cache indexes if ALL backends succeeded (all-or-nothing)
+	// Step 3: Only update cache indexes if ALL backends succeeded (all-or-nothing) and no removal is
+	// blocked by the removal guard
 	hasErrors := false
 	for _, m := range backendErrors {
 		if len(m) > 0 {
@@ -187,9 +284,15 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 	}
 
-	if !hasErrors {
+	if groupCR.Spec.Suspend || groupCR.Spec.IsPlan() {
+		r.log.Info("group is suspended or in plan mode, skipping cache index updates")
+	} else if blockedRemovalsMessage(groupCR, pendingChanges) != "" {
+		// the members whose removal is blocked are still in the backend teams, dropping them from the
+		// indexes would hide them from the next reconciliations and the removal could never be approved
+		r.log.Warn("removals blocked by the removal guard, skipping cache index updates")
+	} else if !hasErrors {
 		r.log.Info("All backends succeeded, updating cache indexes")
-		if err := r.updateCacheIndexes(ctx, groupCR.Spec.GroupName, ldapResult); err != nil {
+		if err := r.updateCacheIndexes(ctx, appliedGroupName(groupCR), ldapResult); err != nil {
 			r.log.WithError(err).Error("error updating cache indexes")
 			// Continue to update status - cache index errors are logged but not fatal
 		}
@@ -204,29 +307,200 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 
 	// Step 5: Update status and handle errors
-	if err := r.updateStatusAndHandleErrors(ctx, groupCR, backendErrors); err != nil {
+	if err := r.updateStatusAndHandleErrors(ctx, groupCR, backendErrors, removedBackends, pendingChanges); err != nil {
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+
+	// Step 6: Drop the removal approval once no removals are blocked anymore, plan mode doesn't
+	// apply the removals so the approval is kept for when the group is applied
+	if !groupCR.Spec.IsPlan() && blockedRemovalsMessage(groupCR, pendingChanges) == "" {
+		if err := controllerutils.RemoveApproveRemovalsAnnotation(ctx, r.Client, groupCR); err != nil {
+			r.log.WithError(err).Error("failed to remove approve removals annotation")
+			return ctrl.Result{}, err
+		}
+	}
+
+	nextRequeue := timeBound.requeueAfter(withJitter(r.resyncInterval(groupCR)))
+	r.log.WithField("requeue_after", nextRequeue.String()).Info("group reconciled, scheduling next reconciliation")
+	return ctrl.Result{RequeueAfter: nextRequeue}, nil
+}
+
+// timeBoundMembers collects the time window information of the time bound users found while
+// resolving the members of a group, including the ones declared by nested groups
+type timeBoundMembers struct {
+	now              time.Time
+	nextBoundary     time.Time
+	upcomingExpiries []usernautdevv1alpha1.MemberExpiry
+}
+
+// activeUsers returns the time bound users of the group CR that are members at tb.now and records
+// their next membership boundary and upcoming expiry
+func (tb *timeBoundMembers) activeUsers(groupCR *usernautdevv1alpha1.Group) []string {
+	active := make([]string, 0, len(groupCR.Spec.Members.TimeBoundUsers))
+	for _, user := range groupCR.Spec.Members.TimeBoundUsers {
+		if user.IsActive(tb.now) {
+			active = append(active, user.UID)
+			if user.ExpiresAt != nil {
+				tb.upcomingExpiries = append(tb.upcomingExpiries, usernautdevv1alpha1.MemberExpiry{
+					User:      user.UID,
+					Group:     groupCR.Name,
+					ExpiresAt: *user.ExpiresAt,
+				})
+			}
+		}
+		next := user.NextBoundary(tb.now)
+		if !next.IsZero() && (tb.nextBoundary.IsZero() || next.Before(tb.nextBoundary)) {
+			tb.nextBoundary = next
+		}
+	}
+	return active
+}
+
+// sortedExpiries returns the upcoming expiries soonest first, without duplicates
+// coming from nested groups that are referenced more than once
+func (tb *timeBoundMembers) sortedExpiries() []usernautdevv1alpha1.MemberExpiry {
+	expiries := slices.Clone(tb.upcomingExpiries)
+	compare := func(a, b usernautdevv1alpha1.MemberExpiry) int {
+		if c := a.ExpiresAt.Compare(b.ExpiresAt.Time); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Group, b.Group); c != 0 {
+			return c
+		}
+		return strings.Compare(a.User, b.User)
+	}
+	slices.SortFunc(expiries, compare)
+	return slices.CompactFunc(expiries, func(a, b usernautdevv1alpha1.MemberExpiry) bool {
+		return compare(a, b) == 0
+	})
+}
+
+// requeueAfter returns the resync interval, or the time until the next time bound
+// membership change if that comes earlier
+func (tb *timeBoundMembers) requeueAfter(resyncInterval time.Duration) time.Duration {
+	if tb.nextBoundary.IsZero() {
+		return resyncInterval
+	}
+	// requeue slightly after the boundary so that the membership change is observed
+	untilBoundary := tb.nextBoundary.Sub(tb.now) + time.Second
+	if untilBoundary < resyncInterval {
+		return untilBoundary
+	}
+	return resyncInterval
+}
+
+// resyncInterval returns the interval after which the group is reconciled again, spec.resyncInterval
+// takes precedence over the controller configuration
+func (r *GroupReconciler) resyncInterval(groupCR *usernautdevv1alpha1.Group) time.Duration {
+	if groupCR.Spec.ResyncInterval != nil && groupCR.Spec.ResyncInterval.Duration > 0 {
+		return groupCR.Spec.ResyncInterval.Duration
+	}
+	configured := r.AppConfig.ControllerConfig.ResyncInterval
+	if configured == "" {
+		return defaultResyncInterval
+	}
+	interval, err := time.ParseDuration(configured)
+	if err != nil || interval <= 0 {
+		r.log.WithField("value", configured).WithError(err).
+			Warn("invalid format for controller resync interval, falling back to default")
+		return defaultResyncInterval
+	}
+	return interval
+}
+
+// withJitter delays the interval by a random duration of up to resyncJitterFactor of it
+func withJitter(interval time.Duration) time.Duration {
+	maxJitter := int64(float64(interval) * resyncJitterFactor)
+	if maxJitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int64N(maxJitter))
+}
+
+// resolveLDAPQueryMembers returns the uids matching the LDAP query, expanded with the indirect
+// reports and managers when the query options ask for it
+func (r *GroupReconciler) resolveLDAPQueryMembers(ctx context.Context,
+	query *usernautdevv1alpha1.LDAPQuery) ([]string, error) {
+	includeIndirectReports := query.Options != nil && query.Options.IncludeIndirectReports
+	includeManager := query.Options != nil && query.Options.IncludeManager
+	members, err := r.fetchQueryMembers(ctx, query, includeIndirectReports)
+	if err != nil {
+		return nil, err
+	}
+	if includeManager {
+		members = append(members, extractManagerUIDsFromQuery(query)...)
+	}
+	return members, nil
+}
+
+// fetchLDAPGroupMembers returns the uids of the members of all the given LDAP groups
+func (r *GroupReconciler) fetchLDAPGroupMembers(ctx context.Context, groupDNs []string) ([]string, error) {
+	members := make([]string, 0)
+	for _, groupDN := range groupDNs {
+		groupMembers, err := r.LdapConn.GetGroupMembers(ctx, groupDN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch members of ldap group %q: %w", groupDN, err)
+		}
+		members = append(members, groupMembers...)
+	}
+	return members, nil
+}
+
+// fetchExcludedMembers returns the set of uids that must be removed from the group members
+func (r *GroupReconciler) fetchExcludedMembers(ctx context.Context,
+	exclude *usernautdevv1alpha1.MemberExclusion) (map[string]struct{}, error) {
+	excluded := make(map[string]struct{})
+	if exclude == nil {
+		return excluded, nil
+	}
+	for _, uid := range exclude.Users {
+		excluded[uid] = struct{}{}
+	}
+	if exclude.LDAPQuery != nil {
+		queryMembers, err := r.resolveLDAPQueryMembers(ctx, exclude.LDAPQuery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve exclude ldap query: %w", err)
+		}
+		for _, uid := range queryMembers {
+			excluded[uid] = struct{}{}
+		}
+	}
+	return excluded, nil
+}
+
+// excludeMembers splits members into the ones that are kept and the sorted list of the ones
+// that are excluded
+func excludeMembers(members []string, excluded map[string]struct{}) ([]string, []string) {
+	if len(excluded) == 0 {
+		return members, nil
+	}
+	kept := make([]string, 0, len(members))
+	var removed []string
+	for _, member := range members {
+		if _, ok := excluded[member]; ok {
+			removed = append(removed, member)
+			continue
+		}
+		kept = append(kept, member)
+	}
+	slices.Sort(removed)
+	return kept, removed
 }
 
 // LDAPFetchResult contains the results of LDAP data fetching
 type LDAPFetchResult struct {
-	CurrentMembers []string // emails of users with valid LDAP data
+	CurrentMembers []string // uids of users with valid LDAP data
 	ActiveUserList []string // UIDs of active users
+	MissingUsers   []string // members not found in LDAP
 }
 
 // fetchQueryMembers runs the LDAP query and, when the query has a manager filter and
-// includeIndirectReports is true, recursively expands each member's reports (people who
-// report to them) and returns the combined set. visited tracks UIDs already expanded to
-// avoid cycles; pass nil for the top-level call (a new map is allocated).
-func (r *GroupReconciler) fetchQueryMembers(ctx context.Context, query *usernautdevv1alpha1.LDAPQuery, includeIndirectReports bool, visited map[string]struct{}) ([]string, error) {
+// includeIndirectReports is true, expands the reports (people who report to them) of each
+// member level by level and returns the combined set.
+func (r *GroupReconciler) fetchQueryMembers(ctx context.Context, query *usernautdevv1alpha1.LDAPQuery,
+	includeIndirectReports bool) ([]string, error) {
 	log := logger.Logger(ctx).WithField("fetching query members", query)
 
-	if visited == nil {
-		visited = make(map[string]struct{})
-	}
-
 	log.WithField("ldap_query", query).Info("building query string from YAML")
 
 	queryString, err := r.LdapConn.BuildLDAPQueryFromSpec(ctx, query)
@@ -236,22 +510,7 @@ func (r *GroupReconciler) fetchQueryMembers(ctx context.Context, query *usernaut
 	}
 
 	log.WithField("query_string", queryString).Info("query string built successfully")
-	var queryMembers []string
-	// Retry LDAP query up to 3 times for transient failures.
-	for attempt := 1; attempt <= 3; attempt++ {
-		queryMembers, err = r.LdapConn.GetQueryMembers(ctx, queryString)
-		if err == nil {
-			break
-		}
-		log.WithError(err).WithField("attempt", attempt).Warn("error fetching users from LDAP using the query")
-		if attempt < 3 {
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(200 * time.Millisecond):
-			}
-		}
-	}
+	queryMembers, err := r.getQueryMembersWithRetry(ctx, queryString)
 	if err != nil {
 		log.WithError(err).Error("failed to fetch users from LDAP using the query after retries")
 		return nil, err
@@ -264,56 +523,116 @@ func (r *GroupReconciler) fetchQueryMembers(ctx context.Context, query *usernaut
 
 	log.WithField("query_members_count", len(queryMembers)).Info("query members fetched successfully")
 
-	hasManagerFilter := queryHasManagerFilter(query)
-
-	// Manager filter present but indirect reports disabled: return only direct reports of the manager in the query (no recursion).
-	if hasManagerFilter && !includeIndirectReports {
+	// Without a manager filter, or with indirect reports disabled, only the direct reports of the
+	// manager in the query are members (no expansion)
+	if !queryHasManagerFilter(query) || !includeIndirectReports {
 		return r.deduplicateMembers(queryMembers), nil
 	}
 
-	if hasManagerFilter && includeIndirectReports {
-		log.Info("has manager filter, fetching indirect reports")
-
-		queue := make([]string, 0, len(queryMembers))
-		queue = append(queue, queryMembers...)
+	log.Info("has manager filter, fetching indirect reports")
+	indirectReports, err := r.fetchIndirectReports(ctx, query, queryMembers)
+	if err != nil {
+		return nil, err
+	}
+	return r.deduplicateMembers(append(queryMembers, indirectReports...)), nil
+}
 
-		// Using DFS search based on a queue.
-		// We can use this queue later for parallelization using goroutine and semaphore.
-		for len(queue) > 0 {
-			// Check if the context is done
-			if err := ctx.Err(); err != nil {
-				return nil, err
+// getQueryMembersWithRetry runs the LDAP search filter, retrying up to 3 times for transient failures
+func (r *GroupReconciler) getQueryMembersWithRetry(ctx context.Context, queryString string) ([]string, error) {
+	var queryMembers []string
+	var err error
+	for attempt := 1; attempt <= 3; attempt++ {
+		queryMembers, err = r.LdapConn.GetQueryMembers(ctx, queryString)
+		if err == nil {
+			return queryMembers, nil
+		}
+		logger.Logger(ctx).WithError(err).WithField("attempt", attempt).Warn("error fetching users from LDAP using the query")
+		if attempt < 3 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
 			}
+		}
+	}
+	return nil, err
+}
 
-			// Use DFS
-			member := queue[len(queue)-1]
-			queue = queue[:len(queue)-1]
+// fetchIndirectReports expands the reports of the direct reports of the query level by level: the
+// reports of all the managers of a level are fetched with one OR filter per batch of
+// indirectReportsBatchSize managers, the filter of each manager being the query with its manager
+// filters pointing to that manager. Up to maxConcurrentLDAPSearches batches run at the same time.
+// Managers are expanded once so that reporting cycles terminate. A batch that fails fails the whole
+// expansion, so that the reconciliation is retried rather than dropping the subtrees of its managers.
+func (r *GroupReconciler) fetchIndirectReports(ctx context.Context, query *usernautdevv1alpha1.LDAPQuery,
+	directReports []string) ([]string, error) {
+	log := logger.Logger(ctx)
+
+	visited := make(map[string]struct{})
+	var reports []string
+	level := directReports
+	for depth := 1; len(level) > 0; depth++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
+		managers := make([]string, 0, len(level))
+		for _, member := range level {
 			if _, seen := visited[member]; seen {
 				log.WithField("member", member).Debug("skipping already-expanded member to avoid cycle")
 				continue
 			}
 			visited[member] = struct{}{}
+			managers = append(managers, member)
+		}
 
-			nestedQuery := usernautdevv1alpha1.LDAPQuery{
-				Operator: query.Operator,
-				Filters:  replaceManagerInFilters(query.Filters, member),
-				Options:  query.Options,
-			}
-			nestedQueryMembers, err := r.fetchQueryMembers(ctx, &nestedQuery, includeIndirectReports, visited)
-			if err != nil {
-				log.WithError(err).WithField("manager", member).Error("error fetching indirect reports")
-				continue
-			}
-			if len(nestedQueryMembers) > 0 {
-				log.WithField("manager", member).WithField("reports", nestedQueryMembers).Info("reports found")
-				queue = append(queue, nestedQueryMembers...)
-				queryMembers = append(queryMembers, nestedQueryMembers...)
-			}
+		batches := slices.Collect(slices.Chunk(managers, indirectReportsBatchSize))
+		batchReports := make([][]string, len(batches))
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(maxConcurrentLDAPSearches)
+		for i, batch := range batches {
+			g.Go(func() error {
+				var err error
+				batchReports[i], err = r.fetchReportsOf(gctx, query, batch)
+				if err != nil {
+					log.WithError(err).WithField("managers", batch).Error("error fetching indirect reports")
+					return fmt.Errorf("failed to fetch the reports of %d managers: %w", len(batch), err)
+				}
+				return nil
+			})
 		}
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
+
+		level = slices.Concat(batchReports...)
+		log.WithField("depth", depth).WithField("managers", len(managers)).WithField("reports", len(level)).
+			Info("reports found")
+		reports = append(reports, level...)
 	}
+	return reports, nil
+}
 
-	return r.deduplicateMembers(queryMembers), nil
+// fetchReportsOf returns the members matching the query for any of the managers
+func (r *GroupReconciler) fetchReportsOf(ctx context.Context, query *usernautdevv1alpha1.LDAPQuery,
+	managers []string) ([]string, error) {
+	var filters strings.Builder
+	for _, manager := range managers {
+		managerQuery := usernautdevv1alpha1.LDAPQuery{
+			Operator: query.Operator,
+			Filters:  replaceManagerInFilters(query.Filters, manager),
+			Options:  query.Options,
+		}
+		filter, err := r.LdapConn.BuildLDAPQueryFromSpec(ctx, &managerQuery)
+		if err != nil {
+			return nil, err
+		}
+		filters.WriteString(filter)
+	}
+	if len(managers) == 1 {
+		return r.getQueryMembersWithRetry(ctx, filters.String())
+	}
+	return r.getQueryMembersWithRetry(ctx, "(|"+filters.String()+")")
 }
 
 // replaceManagerInFilters returns a copy of filters where every manager filter value
@@ -416,13 +735,14 @@ func collectManagerUIDsFromFilters(filters []usernautdevv1alpha1.LDAPFilter, see
 }
 
 // fetchLDAPData fetches LDAP data for all unique members and populates allLdapUserData.
-// This function does NOT update any cache indexes - it only fetches data.
+// This function does NOT update any cache indexes, it only re-links the identity records of the
+// users whose email changed in LDAP when linkIdentities is set, see linkUserIdentity.
 // If the bulk LDAP client returns an error (e.g. server timeout), the entire reconcile
 // should fail so members are not misclassified as missing from LDAP.
-// NOTE: This function assumes CacheMutex is already held by the caller.
 func (r *GroupReconciler) fetchLDAPData(
 	ctx context.Context,
 	uniqueMembers []string,
+	linkIdentities bool,
 ) (*LDAPFetchResult, error) {
 	// Initialize LDAP user data map
 	r.allLdapUserData = make(map[string]*structs.LDAPUser, len(uniqueMembers))
@@ -432,6 +752,7 @@ func (r *GroupReconciler) fetchLDAPData(
 
 	// Track current valid members (users with valid LDAP data)
 	currentMembers := make([]string, 0, len(uniqueMembers))
+	var missingUsers []string
 
 	r.log.WithField("member_count", len(uniqueMembers)).Info("fetching LDAP data in bulk")
 
@@ -448,6 +769,7 @@ func (r *GroupReconciler) fetchLDAPData(
 		userData, ok := bulkData[user]
 		if !ok {
 			r.log.WithField("user", user).Warn("user not found in LDAP, skipping")
+			missingUsers = append(missingUsers, user)
 			continue
 		}
 
@@ -459,11 +781,18 @@ func (r *GroupReconciler) fetchLDAPData(
 
 		r.allLdapUserData[user] = ldapUser
 
+		if linkIdentities {
+			if err := r.linkUserIdentity(ctx, ldapUser); err != nil {
+				r.log.WithField("user", user).WithError(err).Error("error linking user identity")
+				return nil, fmt.Errorf("link identity of user %s: %w", user, err)
+			}
+		}
+
 		if !uniqueUIDs[ldapUser.GetUID()] {
 			uniqueUIDs[ldapUser.GetUID()] = true
 		}
 
-		currentMembers = append(currentMembers, ldapUser.GetEmail())
+		currentMembers = append(currentMembers, ldapUser.GetUID())
 	}
 
 	activeUserList := make([]string, 0, len(uniqueUIDs))
@@ -476,12 +805,13 @@ func (r *GroupReconciler) fetchLDAPData(
 	return &LDAPFetchResult{
 		CurrentMembers: currentMembers,
 		ActiveUserList: activeUserList,
+		MissingUsers:   missingUsers,
 	}, nil
 }
 
 // updateCacheIndexes updates all cache indexes after successful backend reconciliation
 // This includes: user:groups reverse index, group members, and user list
-// NOTE: This function assumes CacheMutex is already held by the caller
+// NOTE: Caller must hold the group lock, the users are locked while their index is updated
 // Returns an error if critical cache updates fail
 func (r *GroupReconciler) updateCacheIndexes(
 	ctx context.Context,
@@ -496,35 +826,37 @@ func (r *GroupReconciler) updateCacheIndexes(
 		r.log.WithError(err).Warn("error fetching previous group members, assuming empty")
 		previousMembers = []string{}
 	}
-	previousMembersSet := make(map[string]struct{}, len(previousMembers))
-	for _, email := range previousMembers {
-		previousMembersSet[email] = struct{}{}
-	}
-
 	// Build current members set for comparison
 	currentMembersSet := make(map[string]struct{}, len(ldapResult.CurrentMembers))
-	for _, email := range ldapResult.CurrentMembers {
-		currentMembersSet[email] = struct{}{}
-	}
-
-	// Update user:groups reverse index - add this group to each current member's group list
-	for _, email := range ldapResult.CurrentMembers {
-		if err := r.Store.UserGroups.AddGroup(ctx, email, groupName); err != nil {
-			r.log.WithError(err).WithField("user", logger.MaskEmail(email)).Error("error updating user groups index")
-			errors = append(errors, fmt.Errorf("failed to add group %s to user %s: %w", groupName, email, err))
-		}
-	}
+	for _, uid := range ldapResult.CurrentMembers {
+		currentMembersSet[uid] = struct{}{}
+	}
+
+	// The reverse index of the users is shared with the other groups, the current and previous members
+	// are locked in batches while it is updated
+	allMembers := slices.Concat(ldapResult.CurrentMembers, previousMembers)
+	err = r.withUserBatchesLocked(ctx, allMembers, func(ctx context.Context, batch []string) error {
+		for _, uid := range batch {
+			// Update user:groups reverse index - add this group to each current member's group list
+			if _, isMember := currentMembersSet[uid]; isMember {
+				if err := r.Store.UserGroups.AddGroup(ctx, uid, groupName); err != nil {
+					r.log.WithError(err).WithField("user", uid).Error("error updating user groups index")
+					errors = append(errors, fmt.Errorf("failed to add group %s to user %s: %w", groupName, uid, err))
+				}
+				continue
+			}
 
-	// Find users who were removed from the group (previous - current)
-	for email := range previousMembersSet {
-		if _, stillMember := currentMembersSet[email]; !stillMember {
-			// User was removed from the group - update their user:groups index
-			r.log.WithField("user", logger.MaskEmail(email)).WithField("group", groupName).Info("removing group from user's group list")
-			if err := r.Store.UserGroups.RemoveGroup(ctx, email, groupName); err != nil {
-				r.log.WithError(err).WithField("user", logger.MaskEmail(email)).Error("error removing group from user's groups index")
-				errors = append(errors, fmt.Errorf("failed to remove group %s from user %s: %w", groupName, email, err))
+			// User was removed from the group (previous - current) - update their user:groups index
+			r.log.WithField("user", uid).WithField("group", groupName).Info("removing group from user's group list")
+			if err := r.Store.UserGroups.RemoveGroup(ctx, uid, groupName); err != nil {
+				r.log.WithError(err).WithField("user", uid).Error("error removing group from user's groups index")
+				errors = append(errors, fmt.Errorf("failed to remove group %s from user %s: %w", groupName, uid, err))
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lock the members of group %s: %w", groupName, err)
 	}
 
 	// Update group members in consolidated store - this is critical
@@ -541,13 +873,19 @@ func (r *GroupReconciler) updateCacheIndexes(
 	return nil
 }
 
-// processAllBackends handles processing of all backends in the group CR
+// processAllBackends handles processing of all backends in the group CR. Up to maxConcurrentBackends
+// backends are processed at the same time, a backend that depends on another backend of the group is
+// processed once that one is done. Suspended backends and all the backends of a group in plan mode
+// are left untouched, the membership changes they would get are returned by backend key instead.
+// nesting is nil unless the nested groups are mapped to backend-native nesting.
 func (r *GroupReconciler) processAllBackends(
 	ctx context.Context,
 	groupCR *usernautdevv1alpha1.Group,
 	uniqueMembers []string,
-) map[string]map[string]string {
+	nesting *groupNesting,
+) (map[string]map[string]string, map[string]*usernautdevv1alpha1.MembershipDiff) {
 	backendErrors := make(map[string]map[string]string, 0)
+	pendingChanges := make(map[string]*usernautdevv1alpha1.MembershipDiff)
 
 	// Create a map of valid backends for validation
 	validBackends := make(map[string]bool)
@@ -584,46 +922,160 @@ func (r *GroupReconciler) processAllBackends(
 		}
 	}
 
-	for _, backend := range groupCR.Spec.Backends {
-		r.backendLogger = r.log.WithFields(logrus.Fields{
-			"backend":      backend.Name,
-			"backend_type": backend.Type,
-		})
+	// The backends are processed concurrently, a backend waits for the backend it depends on
+	// before taking one of the worker slots so that waiting backends never starve their dependency
+	backends := groupCR.Spec.Backends
+	dependencies := r.backendDependencies(backends)
+	done := make(map[string]chan struct{}, len(backends))
+	for _, backend := range backends {
+		done[backend.Name+"_"+backend.Type] = make(chan struct{})
+	}
+	results := make([]backendResult, len(backends))
+	workers := make(chan struct{}, r.maxConcurrentBackends())
+
+	var wg sync.WaitGroup
+	for i, backend := range backends {
 		backendKey := backend.Name + "_" + backend.Type
-		backendGroupParams := groupParamsByBackend[backendKey]
-		if err := r.processSingleBackend(ctx, groupCR, backend, uniqueMembers, backendGroupParams); err != nil {
-			r.backendLogger.WithError(err).Error("error processing backend")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[backendKey])
+			if dependency, ok := dependencies[backendKey]; ok {
+				<-done[dependency]
+			}
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			results[i] = r.processBackend(ctx, groupCR, backend, uniqueMembers, nesting,
+				groupParamsByBackend[backendKey])
+		}()
+	}
+	wg.Wait()
+
+	for i, backend := range backends {
+		result := results[i]
+		if result.pendingChanges != nil {
+			pendingChanges[backend.Name+"_"+backend.Type] = result.pendingChanges
+		}
+		if result.err != nil {
 			if _, ok := backendErrors[backend.Type]; !ok {
 				backendErrors[backend.Type] = make(map[string]string)
 			}
-			backendErrors[backend.Type][backend.Name] = err.Error()
+			backendErrors[backend.Type][backend.Name] = result.err.Error()
+		}
+	}
+
+	return backendErrors, pendingChanges
+}
+
+// backendResult is the outcome of the processing of a backend, pendingChanges holds the membership
+// changes that were not applied
+type backendResult struct {
+	pendingChanges *usernautdevv1alpha1.MembershipDiff
+	err            error
+}
+
+// processBackend applies the membership of the group to a backend, or only computes the membership
+// changes when the backend changes are not applied. It runs concurrently with the other backends of
+// the group, so it works on a copy of the reconciler with its own backend logger.
+func (r *GroupReconciler) processBackend(
+	ctx context.Context,
+	groupCR *usernautdevv1alpha1.Group,
+	backend usernautdevv1alpha1.Backend,
+	uniqueMembers []string,
+	nesting *groupNesting,
+	backendGroupParams structs.TeamParams,
+) backendResult {
+	worker := *r
+	worker.backendLogger = r.log.WithFields(logrus.Fields{
+		"backend":      backend.Name,
+		"backend_type": backend.Type,
+	})
+
+	var result backendResult
+	if !groupCR.Spec.AppliesChanges(backend) {
+		worker.backendLogger.WithField("plan", groupCR.Spec.IsPlan()).
+			Info("backend changes are not applied, computing pending membership changes only")
+		result.pendingChanges, result.err = worker.planSingleBackend(ctx, groupCR, backend, uniqueMembers, nesting)
+	} else {
+		result.pendingChanges, result.err = worker.processSingleBackend(ctx, groupCR, backend, uniqueMembers, nesting,
+			backendGroupParams)
+	}
+	if result.err != nil {
+		worker.backendLogger.WithError(result.err).Error("error processing backend")
+		worker.recordEvent(groupCR, corev1.EventTypeWarning, eventReasonBackendFailed,
+			"failed to reconcile %s/%s: %v", backend.Type, backend.Name, result.err)
+	}
+	return result
+}
+
+// backendDependencies returns the key of the backend each backend of the group has to be processed
+// after, e.g. the Rover group a GitLab group syncs its members from. Dependencies on backends the
+// group doesn't use are ignored, and so is the dependency closing a cycle.
+func (r *GroupReconciler) backendDependencies(backends []usernautdevv1alpha1.Backend) map[string]string {
+	keys := make(map[string]struct{}, len(backends))
+	for _, backend := range backends {
+		keys[backend.Name+"_"+backend.Type] = struct{}{}
+	}
+
+	dependencies := make(map[string]string)
+	for _, backend := range backends {
+		backendKey := backend.Name + "_" + backend.Type
+		dependsOn := r.AppConfig.BackendMap[backend.Type][backend.Name].DependsOn
+		dependencyKey := dependsOn.Name + "_" + dependsOn.Type
+		if _, ok := keys[dependencyKey]; !ok || dependencyKey == backendKey {
+			continue
+		}
+		dependencies[backendKey] = dependencyKey
+	}
+
+	for _, backend := range backends {
+		backendKey := backend.Name + "_" + backend.Type
+		visited := map[string]struct{}{backendKey: {}}
+		for next, ok := dependencies[backendKey]; ok; next, ok = dependencies[next] {
+			if next == backendKey {
+				delete(dependencies, backendKey)
+				break
+			}
+			if _, seen := visited[next]; seen {
+				break
+			}
+			visited[next] = struct{}{}
 		}
 	}
+	return dependencies
+}
 
-	return backendErrors
+// maxConcurrentBackends returns the number of backends of a group processed at the same time
+func (r *GroupReconciler) maxConcurrentBackends() int {
+	if maxConcurrentBackends := r.AppConfig.ControllerConfig.MaxConcurrentBackends; maxConcurrentBackends > 0 {
+		return maxConcurrentBackends
+	}
+	return defaultMaxConcurrentBackends
 }
 
-// processSingleBackend handles processing of a single backend
+// processSingleBackend handles processing of a single backend, it returns the membership removals
+// that were not applied because they exceed the removal guard
 func (r *GroupReconciler) processSingleBackend(ctx context.Context,
 	groupCR *usernautdevv1alpha1.Group,
 	backend usernautdevv1alpha1.Backend,
 	uniqueMembers []string,
+	nesting *groupNesting,
 	backendGroupParams structs.TeamParams,
-) error {
+) (*usernautdevv1alpha1.MembershipDiff, error) {
 	// Create backend client
 	backendClient, err := clients.New(backend.Name, backend.Type, r.AppConfig.BackendMap)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error creating backend client")
-		return err
+		return nil, err
 	}
 	r.backendLogger.Debug("created backend client successfully")
 
 	isLdapSync, err := r.setupLdapSync(
-		backend.Type, backend.Name, backendClient, groupCR.Spec.GroupName, groupCR.Spec.Backends,
+		backend.Type, backend.Name, backendClient, appliedGroupName(groupCR), groupCR.Spec.Backends,
 	)
 	if err != nil {
 		r.backendLogger.Errorf("failed to setup ldap sync for %s: %v", backend.Type, err)
-		return err
+		return nil, err
 	}
 	if !isLdapSync {
 		r.backendLogger.Infof("ldap sync is not setup for %s backend", backend.Type)
@@ -631,13 +1083,18 @@ func (r *GroupReconciler) processSingleBackend(ctx context.Context,
 
 	// Fetch or create team
 	backendParams := &structs.BackendParams{
-		Name: backend.Name,
-		Type: backend.Type,
+		Name:     backend.Name,
+		Type:     backend.Type,
+		TeamName: backend.TeamName,
 	}
-	teamID, err := r.fetchOrCreateTeam(ctx, groupCR.Spec.GroupName, backendClient, backendParams)
+	teamID, created, err := r.fetchOrCreateTeam(ctx, appliedGroupName(groupCR), backendClient, backendParams)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error fetching or creating team")
-		return err
+		return nil, err
+	}
+	if created {
+		r.recordEvent(groupCR, corev1.EventTypeNormal, eventReasonTeamCreated,
+			"created team %s on %s/%s", teamID, backend.Type, backend.Name)
 	}
 	r.backendLogger.WithField("team_id", teamID).Info("fetched or created team successfully")
 
@@ -646,64 +1103,260 @@ func (r *GroupReconciler) processSingleBackend(ctx context.Context,
 		err = backendClient.ReconcileGroupParams(ctx, teamID, backendGroupParams)
 		if err != nil {
 			r.backendLogger.WithError(err).Error("error reconciling group params")
-			return err
+			return nil, err
 		}
 		r.backendLogger.Info("successfully reconciled group params")
+		// group params are reconciled on every resync, only the spec changes are worth an event
+		if groupCR.Generation != groupCR.Status.LastAppliedGeneration {
+			r.recordEvent(groupCR, corev1.EventTypeNormal, eventReasonGroupParamsReconciled,
+				"reconciled group param %s on %s/%s", backendGroupParams.Property, backend.Type, backend.Name)
+		}
+	}
+
+	// Keep the team contact in sync on the backends whose teams have one
+	if contactSetter, ok := backendClient.(clients.TeamContactSetter); ok && groupCR.Spec.Contact != "" {
+		if err := contactSetter.SetTeamContact(ctx, teamID, groupCR.Spec.Contact); err != nil {
+			r.backendLogger.WithError(err).Error("error setting team contact")
+			return nil, err
+		}
+		r.backendLogger.Debug("team contact is up to date")
+	}
+
+	// Members of the nested groups that are nested natively in the team don't have to be added one by one
+	nester, canNest := backendClient.(clients.TeamNester)
+	canNest = canNest && !isLdapSync
+	var nestedTeamIDs []string
+	if canNest && nesting != nil {
+		uniqueMembers, nestedTeamIDs, err = r.resolveNesting(ctx, groupCR.Namespace, backend, nesting)
+		if err != nil {
+			r.backendLogger.WithError(err).Error("error resolving nested teams")
+			return nil, err
+		}
 	}
 
 	// Create users in backend and cache
 	if err := r.createUsersInBackendAndCache(ctx, uniqueMembers, backend.Name, backend.Type, backendClient); err != nil {
 		r.backendLogger.WithError(err).Error("error creating users in backend and cache")
-		return err
+		return nil, err
 	}
 	r.backendLogger.Info("created users in backend and cache successfully")
 
+	// Service accounts bypass LDAP, they are created from the spec alone
+	serviceAccountIDs, err := r.ensureServiceAccounts(ctx, groupCR.Spec.Members.ServiceAccounts,
+		backend.Name, backend.Type, backendClient)
+	if err != nil {
+		r.backendLogger.WithError(err).Error("error creating service accounts in backend and cache")
+		return nil, err
+	}
+
 	// Fetch existing team members
 	members, err := backendClient.FetchTeamMembersByTeamID(ctx, teamID)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error fetching team members")
-		return err
+		return nil, err
 	}
 	r.backendLogger.WithField("team_members_count", len(members)).Info("fetched team members successfully")
 
-	// Process users (determine who to add/remove)
-	usersToAdd, usersToRemove, err := r.processUsers(ctx, uniqueMembers, members, backend.Name, backend.Type)
+	// Process users (determine who to add/remove and whose role has drifted)
+	memberRoles := memberRolesForGroup(&groupCR.Spec, backend.Type)
+	changes, err := r.processUsers(ctx, uniqueMembers, serviceAccountIDs, members, backend.Name, backend.Type, memberRoles)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error processing users")
-		return err
+		return nil, err
+	}
+	// the users were created above, one still missing from the cache can't be added to the team
+	if len(changes.usersToCreate) > 0 {
+		r.backendLogger.WithField("users", changes.usersToCreate).Error("user IDs not found in cache")
+		return nil, fmt.Errorf("user ID not found in cache for %s", strings.Join(changes.usersToCreate, ", "))
+	}
+
+	// Nest the teams before removing their members from the team so that they never lose access
+	var nestedChanges nestedTeamChanges
+	if canNest {
+		nestedChanges, err = r.planNestedTeams(ctx, nester, appliedGroupName(groupCR), backend, teamID, nestedTeamIDs)
+		if err != nil {
+			r.backendLogger.WithError(err).Error("error fetching nested teams")
+			return nil, err
+		}
+		if len(nestedChanges.toNest) > 0 {
+			r.backendLogger.WithField("nested_teams", nestedChanges.toNest).Info("nesting teams in the team")
+			if err := nester.NestTeams(ctx, teamID, nestedChanges.toNest); err != nil {
+				r.backendLogger.WithError(err).Error("error while nesting teams in the team")
+				return nil, err
+			}
+			r.recordBackendEvent(groupCR, backend, eventReasonTeamsNested, "teams nested", len(nestedChanges.toNest))
+		}
 	}
 
 	// Add users to team if needed
+	var blockedRemovals *usernautdevv1alpha1.MembershipDiff
 	if !isLdapSync {
-		if len(usersToAdd) > 0 {
-			r.backendLogger.WithField("user_count", len(usersToAdd)).Info("Adding users to the team")
-			if err := backendClient.AddUserToTeam(ctx, teamID, usersToAdd); err != nil {
+		blockedRemovals = r.guardRemovals(groupCR, backend, changes.usersToRemove, members)
+		if blockedRemovals != nil {
+			changes.usersToRemove = nil
+		}
+
+		for _, role := range slices.Sorted(maps.Keys(changes.usersToAdd)) {
+			usersToAdd := changes.usersToAdd[role]
+			r.backendLogger.WithField("user_count", len(usersToAdd)).WithField("role", role).Info("Adding users to the team")
+			if err := backendClient.AddUserToTeam(ctx, teamID, role, usersToAdd); err != nil {
 				r.backendLogger.WithError(err).Error("error while adding users to the team")
-				return err
+				return nil, err
 			}
 			r.backendLogger.WithField("num_users_to_add", len(usersToAdd)).Info("added users to team successfully")
+			r.recordBackendEvent(groupCR, backend, eventReasonMembersAdded, withRole("members added", role),
+				len(usersToAdd))
+			metrics.AddBackendUsers(backend.Type, metrics.UsersAdded, len(usersToAdd))
 		}
 
 		// Remove users from team if needed
-		if len(usersToRemove) > 0 {
-			r.backendLogger.WithField("user_count", len(usersToRemove)).Info("removing users from a team")
-			if err := backendClient.RemoveUserFromTeam(ctx, teamID, usersToRemove); err != nil {
+		if len(changes.usersToRemove) > 0 {
+			r.backendLogger.WithField("user_count", len(changes.usersToRemove)).Info("removing users from a team")
+			if err := backendClient.RemoveUserFromTeam(ctx, teamID, changes.usersToRemove); err != nil {
 				r.backendLogger.WithError(err).Error("error while removing users from the team")
-				return err
+				return nil, err
 			}
-			r.backendLogger.WithField("num_users_to_remove", len(usersToRemove)).Info("removed users from team successfully")
+			r.backendLogger.WithField("num_users_to_remove", len(changes.usersToRemove)).Info("removed users from team successfully")
+			r.recordBackendEvent(groupCR, backend, eventReasonMembersRemoved, "members removed",
+				len(changes.usersToRemove))
+			metrics.AddBackendUsers(backend.Type, metrics.UsersRemoved, len(changes.usersToRemove))
 		}
-	}
-
-	r.backendLogger.Info("successfully processed backend")
 
-	return nil
+		// Correct the role of existing members whose role has drifted
+		for _, role := range slices.Sorted(maps.Keys(changes.usersToUpdate)) {
+			usersToUpdate := changes.usersToUpdate[role]
+			r.backendLogger.WithField("user_count", len(usersToUpdate)).WithField("role", role).Info("updating role of team members")
+			if err := backendClient.UpdateUserRoleInTeam(ctx, teamID, role, usersToUpdate); err != nil {
+				r.backendLogger.WithError(err).Error("error while updating role of team members")
+				return nil, err
+			}
+			r.backendLogger.WithField("num_users_to_update", len(usersToUpdate)).Info("updated role of team members successfully")
+			r.recordBackendEvent(groupCR, backend, eventReasonMemberRolesUpdated, withRole("member roles updated", role),
+				len(usersToUpdate))
+		}
+	}
+
+	if canNest {
+		if len(nestedChanges.toUnnest) > 0 {
+			r.backendLogger.WithField("nested_teams", nestedChanges.toUnnest).Info("unnesting teams from the team")
+			if err := nester.UnnestTeams(ctx, teamID, nestedChanges.toUnnest); err != nil {
+				r.backendLogger.WithError(err).Error("error while unnesting teams from the team")
+				return nil, err
+			}
+			r.recordBackendEvent(groupCR, backend, eventReasonTeamsUnnested, "teams unnested", len(nestedChanges.toUnnest))
+		}
+		if nestedChanges.changed() {
+			if err := r.Store.Group.SetNestedTeams(ctx, appliedGroupName(groupCR), backend.Name, backend.Type,
+				nestedTeamIDs); err != nil {
+				r.backendLogger.WithError(err).Error("error storing nested teams")
+				return nil, err
+			}
+		}
+	}
+
+	r.backendLogger.Info("successfully processed backend")
+
+	return blockedRemovals, nil
+}
+
+// planSingleBackend computes the membership changes that processing the backend would apply,
+// without creating, updating or removing anything in the backend or the cache. The changes are
+// computed by processUsers like when they are applied, including the removals the removal guard
+// would block.
+func (r *GroupReconciler) planSingleBackend(ctx context.Context,
+	groupCR *usernautdevv1alpha1.Group,
+	backend usernautdevv1alpha1.Backend,
+	uniqueMembers []string,
+	nesting *groupNesting,
+) (*usernautdevv1alpha1.MembershipDiff, error) {
+	backendClient, err := clients.New(backend.Name, backend.Type, r.AppConfig.BackendMap)
+	if err != nil {
+		r.backendLogger.WithError(err).Error("error creating backend client")
+		return nil, err
+	}
+
+	// the members of a team synced from LDAP are never changed by the operator
+	isLdapSync, err := r.setupLdapSync(
+		backend.Type, backend.Name, backendClient, appliedGroupName(groupCR), groupCR.Spec.Backends,
+	)
+	if err != nil {
+		r.backendLogger.Errorf("failed to setup ldap sync for %s: %v", backend.Type, err)
+		return nil, err
+	}
+	if isLdapSync {
+		r.backendLogger.Info("team members are synced from LDAP, no pending membership changes")
+		return &usernautdevv1alpha1.MembershipDiff{}, nil
+	}
+
+	if _, canNest := backendClient.(clients.TeamNester); canNest && nesting != nil {
+		uniqueMembers, _, err = r.resolveNesting(ctx, groupCR.Namespace, backend, nesting)
+		if err != nil {
+			r.backendLogger.WithError(err).Error("error resolving nested teams")
+			return nil, err
+		}
+	}
+
+	teamID, err := r.lookupTeamID(ctx, appliedGroupName(groupCR), &structs.BackendParams{
+		Name:     backend.Name,
+		Type:     backend.Type,
+		TeamName: backend.TeamName,
+	})
+	if err != nil {
+		r.backendLogger.WithError(err).Error("error looking up team")
+		return nil, err
+	}
+
+	// a team that doesn't exist yet would be created empty
+	members := map[string]*structs.User{}
+	if teamID != "" {
+		members, err = backendClient.FetchTeamMembersByTeamID(ctx, teamID)
+		if err != nil {
+			r.backendLogger.WithError(err).Error("error fetching team members")
+			return nil, err
+		}
+	}
+
+	serviceAccountIDs, err := r.lookupServiceAccountIDs(ctx, groupCR.Spec.Members.ServiceAccounts,
+		backend.Name+"_"+backend.Type)
+	if err != nil {
+		r.backendLogger.WithError(err).Error("error looking up service accounts")
+		return nil, err
+	}
+
+	memberRoles := memberRolesForGroup(&groupCR.Spec, backend.Type)
+	changes, err := r.processUsers(ctx, uniqueMembers, serviceAccountIDs, members,
+		backend.Name, backend.Type, memberRoles)
+	if err != nil {
+		r.backendLogger.WithError(err).Error("error computing pending membership changes")
+		return nil, err
+	}
+	diff := membershipDiff(changes, members)
+	if blocked := r.guardRemovals(groupCR, backend, changes.usersToRemove, members); blocked != nil {
+		diff.ApprovalToken = blocked.ApprovalToken
+	}
+
+	pendingLog := r.backendLogger.WithFields(logrus.Fields{
+		"num_users_to_add":    len(diff.UsersToAdd),
+		"num_users_to_remove": len(diff.UsersToRemove),
+		"num_users_to_update": len(diff.UsersToUpdate),
+	})
+	if groupCR.Spec.IsPlan() {
+		pendingLog.Info("computed pending membership changes of backend in plan mode")
+	} else {
+		pendingLog.Info("computed pending membership changes of suspended backend")
+	}
+	return diff, nil
 }
 
-// updateStatusAndHandleErrors updates the CR status and handles any backend errors
+// updateStatusAndHandleErrors updates the CR status and handles any backend errors,
+// removedBackends reports the cleanup of backends that are no longer in the spec and
+// pendingChanges the membership changes of the suspended backends and the removals blocked by the
+// removal guard
 func (r *GroupReconciler) updateStatusAndHandleErrors(ctx context.Context,
 	groupCR *usernautdevv1alpha1.Group,
-	backendErrors map[string]map[string]string) error {
+	backendErrors map[string]map[string]string,
+	removedBackends []usernautdevv1alpha1.BackendStatus,
+	pendingChanges map[string]*usernautdevv1alpha1.MembershipDiff) error {
 	backendStatus := make([]usernautdevv1alpha1.BackendStatus, 0, len(groupCR.Spec.Backends))
 
 	// Build status for each backend
@@ -724,11 +1377,21 @@ func (r *GroupReconciler) updateStatusAndHandleErrors(ctx context.Context,
 			status.Status = true
 			status.Message = "Successful"
 		}
+		if diff, ok := pendingChanges[backend.Name+"_"+backend.Type]; ok && status.Status {
+			status.Message = "Suspended"
+			if groupCR.Spec.IsPlan() {
+				status.Message = "Planned"
+			}
+			if diff.ApprovalToken != "" {
+				status.Message = "Removals blocked, waiting for approval"
+			}
+			status.PendingChanges = diff
+		}
 		backendStatus = append(backendStatus, status)
 	}
 
 	// Update CR status
-	groupCR.Status.BackendsStatus = backendStatus
+	groupCR.Status.BackendsStatus = append(backendStatus, removedBackends...)
 	groupCR.UpdateStatus(false)
 	hasErrors := false
 	for _, m := range backendErrors {
@@ -737,8 +1400,23 @@ func (r *GroupReconciler) updateStatusAndHandleErrors(ctx context.Context,
 			break
 		}
 	}
+	for _, status := range removedBackends {
+		if !status.Status {
+			hasErrors = true
+			break
+		}
+	}
 	if hasErrors {
 		groupCR.UpdateStatus(true)
+	} else if groupCR.Spec.IsPlan() {
+		groupCR.SetPlanned()
+	} else if groupCR.Spec.Suspend {
+		groupCR.SetSuspended()
+	}
+	if message := blockedRemovalsMessage(groupCR, pendingChanges); message != "" {
+		groupCR.SetRemovalBlocked(message)
+	} else {
+		groupCR.ClearRemovalBlocked()
 	}
 	if updateStatusErr := r.Status().Update(ctx, groupCR); updateStatusErr != nil {
 		r.log.WithError(updateStatusErr).Error("error while updating final status")
@@ -756,16 +1434,25 @@ func (r *GroupReconciler) updateStatusAndHandleErrors(ctx context.Context,
 // handleDeletion processes the deletion of a Group CR and its finalizer
 func (r *GroupReconciler) handleDeletion(ctx context.Context, groupCR *usernautdevv1alpha1.Group) error {
 	if controllerutil.ContainsFinalizer(groupCR, groupFinalizer) {
-		// Lock cache for deletion operations
 		// Multiple Group CRs might reference the same team and delete concurrently
-		r.CacheMutex.Lock()
-		defer r.CacheMutex.Unlock()
+		lease, err := r.lockGroup(ctx, groupCR)
+		if err != nil {
+			return err
+		}
+		defer r.unlock(lease)
+		ctx = lease.Context()
 
-		// Clean up user:groups reverse index for all members of this group
-		r.cleanupUserGroupsIndex(ctx, groupCR.Spec.GroupName)
+		// A group in plan mode never changes the backends nor the cache, so its deletion doesn't
+		// either: another Group with the same group_name may be managing the teams
+		if groupCR.Spec.IsPlan() {
+			r.log.Info("group is in plan mode, leaving the backend teams and cache untouched")
+		} else {
+			// Clean up user:groups reverse index for all members of this group
+			r.cleanupUserGroupsIndex(ctx, appliedGroupName(groupCR))
 
-		if err := r.deleteBackendsTeam(ctx, groupCR); err != nil {
-			return err
+			if err := r.deleteBackendsTeam(ctx, groupCR); err != nil {
+				return err
+			}
 		}
 
 		controllerutil.RemoveFinalizer(groupCR, groupFinalizer)
@@ -778,7 +1465,7 @@ func (r *GroupReconciler) handleDeletion(ctx context.Context, groupCR *usernautd
 }
 
 // cleanupUserGroupsIndex removes the group from all members' user:groups index
-// NOTE: Caller must hold CacheMutex lock
+// NOTE: Caller must hold the group lock, the members are locked while their index is updated
 // NOTE: This does NOT delete the group entry - that happens in deleteBackendsTeam
 func (r *GroupReconciler) cleanupUserGroupsIndex(ctx context.Context, groupName string) {
 	// Get all members of the group
@@ -789,15 +1476,22 @@ func (r *GroupReconciler) cleanupUserGroupsIndex(ctx context.Context, groupName
 	}
 
 	// Remove the group from each member's user:groups index
-	for _, email := range members {
-		r.log.WithFields(logrus.Fields{
-			"user":  logger.MaskEmail(email),
-			"group": groupName,
-		}).Info("removing group from user's group list during deletion")
-		if err := r.Store.UserGroups.RemoveGroup(ctx, email, groupName); err != nil {
-			r.log.WithError(err).WithField("user", logger.MaskEmail(email)).Error("error removing group from user's groups index during deletion")
-			// Continue processing other members
+	err = r.withUsersLocked(ctx, members, func(ctx context.Context) error {
+		for _, uid := range members {
+			r.log.WithFields(logrus.Fields{
+				"user":  uid,
+				"group": groupName,
+			}).Info("removing group from user's group list during deletion")
+			if err := r.Store.UserGroups.RemoveGroup(ctx, uid, groupName); err != nil {
+				r.log.WithError(err).WithField("user", uid).Error("error removing group from user's groups index during deletion")
+				// Continue processing other members
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		r.log.WithError(err).Error("error locking group members for cleanup")
+		return
 	}
 
 	r.log.WithField("group", groupName).Info("cleaned up user groups index successfully")
@@ -805,71 +1499,465 @@ func (r *GroupReconciler) cleanupUserGroupsIndex(ctx context.Context, groupName
 
 func (r *GroupReconciler) deleteBackendsTeam(ctx context.Context, groupCR *usernautdevv1alpha1.Group) error {
 	r.log.Info("Finalizer: starting Backends team deletion cleanup")
-	groupName := groupCR.Spec.GroupName
+	groupName := appliedGroupName(groupCR)
+	finalizerLog := r.log.WithField("cleanup", "finalizer")
 
 	for _, backend := range groupCR.Spec.Backends {
-		transformedGroupName, err := utils.GetTransformedGroupName(r.AppConfig, backend.Type, groupName)
-		backendLoggerInfo := r.log.WithFields(logrus.Fields{
-			"group_name":            groupName,
-			"transformed_team_name": transformedGroupName,
-			"backend":               backend.Name,
-			"backend_type":          backend.Type,
-		})
-		backendLoggerInfo.Info("Finalizer: Deleting team from backend")
-		if err != nil {
-			backendLoggerInfo.WithError(err).Error("Finalizer: Error in transforming group name")
+		if err := r.releaseBackendTeam(ctx, finalizerLog, groupName, backend,
+			groupCR.Spec.DeletionPolicyFor(backend)); err != nil {
 			return err
 		}
+	}
 
-		backendClient, err := clients.New(backend.Name, backend.Type, r.AppConfig.BackendMap)
+	// Backends that were removed from the spec but could not be cleaned up yet
+	removedBackends, err := r.removedBackends(ctx, groupCR)
+	if err != nil {
+		r.log.WithError(err).Error("Finalizer: error fetching removed backends from cache")
+		return err
+	}
+	for _, backend := range removedBackends {
+		err := r.releaseBackendTeam(ctx, finalizerLog, groupName, backend, groupCR.Spec.DeletionPolicyFor(backend))
+		if errors.Is(err, clients.ErrInvalidBackend) {
+			finalizerLog.WithField("backend", backend.Name).WithField("backend_type", backend.Type).
+				Warn("Finalizer: removed backend is not configured anymore, leaving its team untouched")
+			continue
+		}
 		if err != nil {
-			backendLoggerInfo.WithError(err).Errorf("Finalizer: error creating client for backend %s", backend.Name)
 			return err
 		}
+	}
 
-		// Get team ID from consolidated group store (using original group name)
-		// NOTE: CacheMutex is already held by caller (handleDeletion)
-		teamID, err := r.Store.Group.GetBackendID(ctx, groupName, backend.Name, backend.Type)
-		if err != nil {
-			backendLoggerInfo.WithError(err).Error("Finalizer: error fetching team details from cache")
-			return err
+	// Delete the entire group entry from cache (includes all backends and members)
+	if err := r.Store.Group.Delete(ctx, groupName); err != nil {
+		r.log.WithError(err).Error("Finalizer: failed to delete group from cache")
+		return err
+	}
+	r.log.WithField("group", groupName).Info("Finalizer: Successfully deleted group from cache")
+
+	return nil
+}
+
+// releaseBackendTeam applies the deletion policy to the team of the group in the given backend
+// and updates the TeamStore accordingly. The GroupStore entry is left to the caller.
+// NOTE: Caller must hold the group lock, the teams lock of the backend is held while the team is released
+func (r *GroupReconciler) releaseBackendTeam(ctx context.Context, log *logrus.Entry, groupName string,
+	backend usernautdevv1alpha1.Backend, deletionPolicy usernautdevv1alpha1.DeletionPolicy) error {
+	return r.withTeamsLocked(ctx, backend, func(ctx context.Context) error {
+		return r.releaseBackendTeamLocked(ctx, log, groupName, backend, deletionPolicy)
+	})
+}
+
+// releaseBackendTeamLocked is releaseBackendTeam once the teams lock of the backend is held
+func (r *GroupReconciler) releaseBackendTeamLocked(ctx context.Context, log *logrus.Entry, groupName string,
+	backend usernautdevv1alpha1.Backend, deletionPolicy usernautdevv1alpha1.DeletionPolicy) error {
+	transformedGroupName, err := utils.GetBackendTeamName(r.AppConfig, backend.Type, backend.TeamName, groupName)
+	backendLoggerInfo := log.WithFields(logrus.Fields{
+		"group_name":            groupName,
+		"transformed_team_name": transformedGroupName,
+		"backend":               backend.Name,
+		"backend_type":          backend.Type,
+		"deletion_policy":       deletionPolicy,
+	})
+	backendLoggerInfo.Info("releasing team in backend")
+	if err != nil {
+		backendLoggerInfo.WithError(err).Error("error in transforming group name")
+		return err
+	}
+
+	backendClient, err := clients.New(backend.Name, backend.Type, r.AppConfig.BackendMap)
+	if err != nil {
+		backendLoggerInfo.WithError(err).Errorf("error creating client for backend %s", backend.Name)
+		return err
+	}
+
+	// Get team ID from consolidated group store (using original group name)
+	teamID, err := r.Store.Group.GetBackendID(ctx, groupName, backend.Name, backend.Type)
+	if err != nil {
+		backendLoggerInfo.WithError(err).Error("error fetching team details from cache")
+		return err
+	}
+
+	backendKey := backend.Name + "_" + backend.Type
+
+	if teamID == "" {
+		// Nothing was created in the backend, only drop the preload entry
+		if err := r.Store.Team.DeleteBackend(ctx, transformedGroupName, backendKey); err != nil {
+			backendLoggerInfo.WithError(err).Warn("failed to delete team from TeamStore cache")
 		}
+		return nil
+	}
 
-		if teamID != "" {
-			backendLoggerInfo.Infof("Finalizer: Deleting team with (ID: %s) from Backend %s", teamID, backend.Type)
+	switch deletionPolicy {
+	case usernautdevv1alpha1.DeletionPolicyRetain:
+		backendLoggerInfo.Infof("Retaining team with (ID: %s) in Backend %s", teamID, backend.Type)
+	case usernautdevv1alpha1.DeletionPolicyOrphan:
+		backendLoggerInfo.Infof("Removing all members of team with (ID: %s) in Backend %s", teamID, backend.Type)
+		if err := r.removeAllTeamMembers(ctx, backendClient, teamID); err != nil {
+			backendLoggerInfo.WithError(err).Error("failed to remove members of the team from the backend")
+			return err
+		}
+	default:
+		backendLoggerInfo.Infof("Deleting team with (ID: %s) from Backend %s", teamID, backend.Type)
 
-			if err := backendClient.DeleteTeamByID(ctx, teamID); err != nil {
-				backendLoggerInfo.WithError(err).Error("Finalizer: failed to delete team from the backend")
-				return err
-			}
-			backendLoggerInfo.Infof("Finalizer: Successfully deleted team with id '%s' from Backend %s", teamID, backend.Type)
+		if err := backendClient.DeleteTeamByID(ctx, teamID); err != nil {
+			backendLoggerInfo.WithError(err).Error("failed to delete team from the backend")
+			return err
 		}
+		backendLoggerInfo.Infof("Successfully deleted team with id '%s' from Backend %s", teamID, backend.Type)
 
 		// Delete team entry from TeamStore (used for preload lookups)
-		if err := r.Store.Team.Delete(ctx, transformedGroupName); err != nil {
-			backendLoggerInfo.WithError(err).Warn("Finalizer: failed to delete team from TeamStore cache")
+		if err := r.Store.Team.DeleteBackend(ctx, transformedGroupName, backendKey); err != nil {
+			backendLoggerInfo.WithError(err).Warn("failed to delete team from TeamStore cache")
 			// Continue processing - TeamStore is secondary cache
 		}
+		return nil
 	}
 
-	// Delete the entire group entry from cache (includes all backends and members)
-	if err := r.Store.Group.Delete(ctx, groupName); err != nil {
-		r.log.WithError(err).Error("Finalizer: failed to delete group from cache")
+	// The team still exists in the backend, keep it in the TeamStore so that a Group CR
+	// created later with the same name adopts it instead of creating a new team
+	if err := r.Store.Team.SetBackend(ctx, transformedGroupName, backendKey, teamID); err != nil {
+		backendLoggerInfo.WithError(err).Warn("failed to keep retained team in TeamStore cache")
+	}
+	return nil
+}
+
+// renameGroup moves the backend teams and cache records of the group from the previously applied
+// group name to spec.group_name. Teams are renamed in place on backends that support it, on the other
+// backends the old team is released according to the deletion policy and the regular reconciliation
+// creates a team with the new name and migrates the membership to it.
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) renameGroup(ctx context.Context, groupCR *usernautdevv1alpha1.Group) error {
+	oldName := groupCR.Status.AppliedGroupName
+	newName := groupCR.Spec.GroupName
+	if oldName == "" || oldName == newName {
+		return nil
+	}
+	renameLog := r.log.WithFields(logrus.Fields{
+		"old_group_name": oldName,
+		"new_group_name": newName,
+	})
+	renameLog.Info("group name changed, moving backend teams to the new name")
+
+	// a group_name already used by another group is rejected before any backend team is renamed
+	oldExists, err := r.Store.Group.Exists(ctx, oldName)
+	if err != nil {
 		return err
 	}
-	r.log.WithField("group", groupName).Info("Finalizer: Successfully deleted group from cache")
+	taken, err := r.Store.Group.Exists(ctx, newName)
+	if err != nil {
+		return err
+	}
+	if oldExists && taken {
+		return fmt.Errorf("failed to rename group %s to %s: %w", oldName, newName, store.ErrGroupExists)
+	}
+
+	storedBackends, err := r.Store.Group.GetBackends(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(storedBackends)) {
+		info := storedBackends[key]
+		backend := usernautdevv1alpha1.Backend{Name: info.Name, Type: info.Type}
+		for _, specBackend := range groupCR.Spec.Backends {
+			if specBackend.Name == info.Name && specBackend.Type == info.Type {
+				backend = specBackend
+				break
+			}
+		}
+		if err := r.renameBackendTeam(ctx, renameLog, groupCR, oldName, backend, info.ID); err != nil {
+			return fmt.Errorf("failed to rename team in backend %s/%s: %w", backend.Type, backend.Name, err)
+		}
+	}
+
+	// the members are locked while their user:groups index is pointed to the new name
+	members, err := r.Store.Group.GetMembers(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	err = r.withUsersLocked(ctx, members, func(ctx context.Context) error {
+		return r.Store.RenameGroup(ctx, oldName, newName)
+	})
+	if err != nil {
+		return err
+	}
+	renameLog.Info("group renamed successfully")
+	return nil
+}
+
+// renameBackendTeam renames the team of the group in a single backend, or releases it when the
+// backend can't rename teams in place
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) renameBackendTeam(ctx context.Context, log *logrus.Entry,
+	groupCR *usernautdevv1alpha1.Group, oldName string, backend usernautdevv1alpha1.Backend, teamID string) error {
+	// a teamName override doesn't depend on group_name, so such teams keep their name
+	oldTeamName, err := utils.GetBackendTeamName(r.AppConfig, backend.Type, backend.TeamName, oldName)
+	if err != nil {
+		return err
+	}
+	newTeamName, err := utils.GetBackendTeamName(r.AppConfig, backend.Type, backend.TeamName, groupCR.Spec.GroupName)
+	if err != nil {
+		return err
+	}
+	backendLog := log.WithFields(logrus.Fields{
+		"backend":       backend.Name,
+		"backend_type":  backend.Type,
+		"old_team_name": oldTeamName,
+		"new_team_name": newTeamName,
+	})
+	if oldTeamName == newTeamName {
+		backendLog.Info("team name is unchanged in backend")
+		return nil
+	}
+
+	backendClient, err := clients.New(backend.Name, backend.Type, r.AppConfig.BackendMap)
+	if errors.Is(err, clients.ErrInvalidBackend) {
+		backendLog.Warn("backend is not configured anymore, leaving its team untouched")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// GitLab groups synced from LDAP are linked to the team name of their dependant backend,
+	// which changes as well, so they are recreated with the new link instead
+	dependsOn := r.AppConfig.BackendMap[backend.Type][backend.Name].DependsOn
+	hasLdapDependant := dependsOn.Type != "" || dependsOn.Name != ""
+
+	if renamer, ok := backendClient.(clients.TeamRenamer); ok && !hasLdapDependant && teamID != "" {
+		return r.withTeamsLocked(ctx, backend, func(ctx context.Context) error {
+			team, err := renamer.RenameTeam(ctx, teamID, newTeamName)
+			if err != nil {
+				return err
+			}
+			// The GroupStore record is moved to the new group name by the caller
+			if err := r.Store.Group.SetBackend(ctx, oldName, backend.Name, backend.Type, team.ID); err != nil {
+				return err
+			}
+			if err := r.Store.Team.DeleteBackend(ctx, oldTeamName, backend.Name+"_"+backend.Type); err != nil {
+				backendLog.WithError(err).Warn("failed to delete old team from TeamStore cache")
+			}
+			backendLog.WithField("team_id", team.ID).Info("renamed team in backend")
+			return nil
+		})
+	}
+
+	backendLog.Info("backend can't rename the team, releasing it so that a team with the new name is created")
+	if err := r.releaseBackendTeam(ctx, backendLog, oldName, backend, groupCR.Spec.DeletionPolicyFor(backend)); err != nil {
+		return err
+	}
+	return r.Store.Group.DeleteBackend(ctx, oldName, backend.Name, backend.Type)
+}
+
+// seedAppliedGroupName records the group_name of a group that has never been reconciled as the one its
+// backend teams are created under, so that a rename deferred by a suspended backend or plan mode can
+// still find the teams of the old name
+func seedAppliedGroupName(groupCR *usernautdevv1alpha1.Group) {
+	if groupCR.Status.AppliedGroupName == "" {
+		groupCR.Status.AppliedGroupName = groupCR.Spec.GroupName
+	}
+}
+
+// applyGroupName moves the backend teams and cache records of a renamed group to the new name. The
+// rename touches the teams of every backend, so it waits until none of them is suspended and the group
+// is out of plan mode.
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) applyGroupName(ctx context.Context, groupCR *usernautdevv1alpha1.Group) error {
+	if appliedGroupName(groupCR) == groupCR.Spec.GroupName {
+		return nil
+	}
+	renameLog := r.log.WithField("applied_group_name", appliedGroupName(groupCR))
+	if groupCR.Spec.IsPlan() {
+		renameLog.Info("group is in plan mode, deferring the rename")
+		return nil
+	}
+	if groupCR.Spec.HasSuspendedBackends() {
+		renameLog.Info("group has suspended backends, deferring the rename")
+		return nil
+	}
 
+	if err := r.renameGroup(ctx, groupCR); err != nil {
+		return err
+	}
+	groupCR.Status.AppliedGroupName = groupCR.Spec.GroupName
 	return nil
 }
 
+// appliedGroupName returns the group name the backend teams and cache records of the group
+// belong to, which lags behind spec.group_name until a rename has been reconciled
+func appliedGroupName(groupCR *usernautdevv1alpha1.Group) string {
+	if groupCR.Status.AppliedGroupName != "" {
+		return groupCR.Status.AppliedGroupName
+	}
+	return groupCR.Spec.GroupName
+}
+
+// removedBackends returns the backends recorded in the GroupStore for the group that are no longer
+// listed in its spec, sorted by type and name
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) removedBackends(ctx context.Context,
+	groupCR *usernautdevv1alpha1.Group) ([]usernautdevv1alpha1.Backend, error) {
+	storedBackends, err := r.Store.Group.GetBackends(ctx, appliedGroupName(groupCR))
+	if err != nil {
+		return nil, err
+	}
+
+	specBackends := make(map[string]struct{}, len(groupCR.Spec.Backends))
+	for _, backend := range groupCR.Spec.Backends {
+		specBackends[backend.Name+"_"+backend.Type] = struct{}{}
+	}
+
+	removed := make([]usernautdevv1alpha1.Backend, 0)
+	for key, info := range storedBackends {
+		if _, ok := specBackends[key]; ok {
+			continue
+		}
+		removed = append(removed, usernautdevv1alpha1.Backend{Name: info.Name, Type: info.Type})
+	}
+	slices.SortFunc(removed, func(a, b usernautdevv1alpha1.Backend) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return removed, nil
+}
+
+// cleanupRemovedBackends releases the teams of the backends that were removed from the spec
+// according to spec.deletion_policy and reports the outcome for each of them. Backends that
+// failed to clean up are kept in the GroupStore so that they are retried on the next reconciliation.
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) cleanupRemovedBackends(ctx context.Context,
+	groupCR *usernautdevv1alpha1.Group) ([]usernautdevv1alpha1.BackendStatus, error) {
+	groupName := appliedGroupName(groupCR)
+	removedBackends, err := r.removedBackends(ctx, groupCR)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanupLog := r.log.WithField("cleanup", "removed_backend")
+	statuses := make([]usernautdevv1alpha1.BackendStatus, 0, len(removedBackends))
+	for _, backend := range removedBackends {
+		deletionPolicy := groupCR.Spec.DeletionPolicyFor(backend)
+		status := usernautdevv1alpha1.BackendStatus{
+			Name:    backend.Name,
+			Type:    backend.Type,
+			Status:  true,
+			Message: removedBackendMessage(deletionPolicy),
+		}
+		// keep the GroupStore entry so that the team is released once the group is resumed
+		if groupCR.Spec.Suspend {
+			status.Message = "Removed from spec, cleanup is suspended"
+			statuses = append(statuses, status)
+			continue
+		}
+		if groupCR.Spec.IsPlan() {
+			status.Message = "Removed from spec, cleanup is planned: " + strings.TrimPrefix(status.Message, "Removed from spec, ")
+			statuses = append(statuses, status)
+			continue
+		}
+
+		err := r.releaseBackendTeam(ctx, cleanupLog, groupName, backend, deletionPolicy)
+		if errors.Is(err, clients.ErrInvalidBackend) {
+			status.Message = "Removed from spec, backend is not configured anymore so its team was left untouched"
+			err = nil
+		}
+		if err != nil {
+			status.Status = false
+			status.Message = "Removed from spec, cleanup failed: " + err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+
+		if err := r.Store.Group.DeleteBackend(ctx, groupName, backend.Name, backend.Type); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// removedBackendMessage describes what happened to the team of a backend removed from the spec
+func removedBackendMessage(deletionPolicy usernautdevv1alpha1.DeletionPolicy) string {
+	switch deletionPolicy {
+	case usernautdevv1alpha1.DeletionPolicyRetain:
+		return "Removed from spec, team retained"
+	case usernautdevv1alpha1.DeletionPolicyOrphan:
+		return "Removed from spec, team members removed"
+	default:
+		return "Removed from spec, team deleted"
+	}
+}
+
+// removeAllTeamMembers removes every member from the backend team, leaving an empty team behind
+func (r *GroupReconciler) removeAllTeamMembers(ctx context.Context, backendClient clients.Client, teamID string) error {
+	members, err := backendClient.FetchTeamMembersByTeamID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return backendClient.RemoveUserFromTeam(ctx, teamID, slices.Sorted(maps.Keys(members)))
+}
+
+// membershipChanges holds the backend user IDs that need to be changed to bring a team in sync with the group
+type membershipChanges struct {
+	// usersToAdd maps a role to the users that should be added to the team with that role
+	usersToAdd map[string][]string
+	// usersToRemove are the users that should be removed from the team
+	usersToRemove []string
+	// usersToUpdate maps a role to the existing team members whose role has drifted
+	usersToUpdate map[string][]string
+	// usersToCreate are the group members, by uid, and service accounts, by name, without a user in
+	// the backend yet. They are created before the membership is applied, so only a plan reports them.
+	usersToCreate []string
+	// names maps the backend user IDs of the group members to their uid, or name for service accounts
+	names map[string]string
+}
+
+// memberRolesForGroup returns the role of each group member (by uid) for the given backend type,
+// owners get the owner role of the backend regardless of spec.members.roles
+func memberRolesForGroup(spec *usernautdevv1alpha1.GroupSpec, backendType string) map[string]string {
+	memberRoles := memberRolesForBackend(spec.Members.Roles, backendType)
+	if ownerRole := clients.OwnerRole(backendType); ownerRole != "" {
+		for _, owner := range spec.Owners {
+			memberRoles[owner] = ownerRole
+		}
+	}
+	return memberRoles
+}
+
+// memberRolesForBackend returns the role of each group member (by uid) for the given backend type
+func memberRolesForBackend(roles []usernautdevv1alpha1.MemberRole, backendType string) map[string]string {
+	memberRoles := make(map[string]string)
+	for _, memberRole := range roles {
+		if !strings.EqualFold(memberRole.Backend, backendType) {
+			continue
+		}
+		for _, user := range memberRole.Users {
+			memberRoles[user] = memberRole.Role
+		}
+	}
+	return memberRoles
+}
+
+// processUsers determines the membership changes of the team, serviceAccountIDs maps the name
+// of each service account of the group to its backend user ID and gets the default role.
+// It only reads the cache, so it computes both the changes applied to a backend and the pending
+// changes of a backend that is suspended or in plan mode.
 func (r *GroupReconciler) processUsers(ctx context.Context,
 	groupUsers []string,
+	serviceAccountIDs map[string]string,
 	existingTeamMembers map[string]*structs.User,
-	backendName, backendType string) ([]string, []string, error) {
+	backendName, backendType string,
+	memberRoles map[string]string) (*membershipChanges, error) {
 
 	userIDsToSync := make([]string, 0)
-	usersToAdd := make([]string, 0)
+	userRoles := make(map[string]string)
 	usersToRemove := make([]string, 0)
+	var usersToCreate []string
+	names := make(map[string]string)
+	defaultRole := clients.DefaultRole(backendType)
 
 	for _, user := range groupUsers {
 		userDetails := r.allLdapUserData[user]
@@ -884,39 +1972,218 @@ func (r *GroupReconciler) processUsers(ctx context.Context,
 			continue
 		}
 
-		// NOTE: CacheMutex is already held by caller (Reconcile)
 		// Get user backends from cache
-		userBackends, err := r.Store.User.GetBackends(ctx, userDetails.GetEmail())
+		userBackends, err := r.Store.User.GetBackends(ctx, userDetails.GetUID())
 		if err != nil {
 			r.backendLogger.WithError(err).Error("error fetching user details from cache")
-			return nil, nil, err
+			return nil, err
 		}
 
 		backendKey := backendName + "_" + backendType
 		userID := userBackends[backendKey]
 		if userID == "" {
 			r.backendLogger.WithField("user", user).Warn("user ID not found in cache, will create user in backend")
-			return nil, nil, errors.New("user ID not found in cache")
+			usersToCreate = append(usersToCreate, user)
+			continue
+		}
+		userIDsToSync = append(userIDsToSync, userID)
+		names[userID] = user
+
+		role := memberRoles[user]
+		if role == "" {
+			role = defaultRole
+		}
+		userRoles[userID] = role
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(serviceAccountIDs)) {
+		userID := serviceAccountIDs[name]
+		if userID == "" {
+			r.backendLogger.WithField("service_account", name).Warn("service account ID not found in cache")
+			usersToCreate = append(usersToCreate, name)
+			continue
 		}
 		userIDsToSync = append(userIDsToSync, userID)
+		userRoles[userID] = defaultRole
+		names[userID] = name
+	}
+
+	// service accounts that usernaut didn't create are left alone in the team
+	managedServiceAccounts, err := r.managedServiceAccounts(ctx, existingTeamMembers, backendName+"_"+backendType)
+	if err != nil {
+		return nil, err
 	}
 
 	// process existing team members to find users to remove
-	for userID := range existingTeamMembers {
+	for userID, member := range existingTeamMembers {
+		if member.IsServiceAccount() && !managedServiceAccounts[userID] {
+			continue
+		}
 		if !slices.Contains(userIDsToSync, userID) {
 			usersToRemove = append(usersToRemove, userID)
 		}
 	}
 
 	// process group users to find users to add
-	// if user is not present in existing team members, then add the user to the team
+	// if user is not present in existing team members, then add the user to the team,
+	// otherwise correct the role of the member if it differs from the desired one
+	changes := &membershipChanges{
+		usersToAdd:    make(map[string][]string),
+		usersToRemove: usersToRemove,
+		usersToUpdate: make(map[string][]string),
+		usersToCreate: usersToCreate,
+		names:         names,
+	}
 	for _, userID := range userIDsToSync {
-		if _, exists := existingTeamMembers[userID]; !exists {
-			usersToAdd = append(usersToAdd, userID)
+		role := userRoles[userID]
+		existing, exists := existingTeamMembers[userID]
+		if !exists {
+			changes.usersToAdd[role] = append(changes.usersToAdd[role], userID)
+			continue
+		}
+		// backends that don't report member roles leave Role empty
+		if existing.GetRole() != "" && role != "" && !strings.EqualFold(existing.GetRole(), role) {
+			changes.usersToUpdate[role] = append(changes.usersToUpdate[role], userID)
+		}
+	}
+
+	return changes, nil
+}
+
+// membershipDiff reports the membership changes of a team by uid, or name for service accounts,
+// rather than by backend user ID: the users still to be created are reported as users to add and
+// the members to remove by email when the backend reports it
+func membershipDiff(changes *membershipChanges,
+	existingTeamMembers map[string]*structs.User) *usernautdevv1alpha1.MembershipDiff {
+	diff := &usernautdevv1alpha1.MembershipDiff{
+		UsersToAdd:    slices.Clone(changes.usersToCreate),
+		UsersToRemove: removalNames(changes.usersToRemove, existingTeamMembers),
+	}
+	for _, userIDs := range changes.usersToAdd {
+		for _, userID := range userIDs {
+			diff.UsersToAdd = append(diff.UsersToAdd, changes.names[userID])
+		}
+	}
+	for _, userIDs := range changes.usersToUpdate {
+		for _, userID := range userIDs {
+			diff.UsersToUpdate = append(diff.UsersToUpdate, changes.names[userID])
+		}
+	}
+
+	slices.Sort(diff.UsersToAdd)
+	slices.Sort(diff.UsersToUpdate)
+	return diff
+}
+
+// removalNames returns the sorted emails of the team members to remove, or their backend user ID
+// when the backend doesn't report the email
+func removalNames(usersToRemove []string, existingTeamMembers map[string]*structs.User) []string {
+	var names []string
+	for _, userID := range usersToRemove {
+		if member := existingTeamMembers[userID]; member != nil && member.Email != "" {
+			names = append(names, member.Email)
+		} else {
+			names = append(names, userID)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// guardRemovals returns the removals from the backend team as a diff when they exceed the removal guard
+// of the group and were not approved with the approve-removals annotation, nil when they can be applied
+func (r *GroupReconciler) guardRemovals(groupCR *usernautdevv1alpha1.Group,
+	backend usernautdevv1alpha1.Backend,
+	usersToRemove []string,
+	existingTeamMembers map[string]*structs.User) *usernautdevv1alpha1.MembershipDiff {
+
+	maxRemovals, maxRemovalPercent := r.removalGuardLimits(groupCR)
+	if !removalsExceedLimits(len(usersToRemove), len(existingTeamMembers), maxRemovals, maxRemovalPercent) {
+		return nil
+	}
+
+	token := removalApprovalToken(backend.Name+"_"+backend.Type, usersToRemove)
+	log := r.backendLogger.WithFields(logrus.Fields{
+		"num_users_to_remove": len(usersToRemove),
+		"team_members_count":  len(existingTeamMembers),
+		"approval_token":      token,
+	})
+	if removalsApproved(groupCR, token) {
+		log.Info("removals exceed the removal guard but were approved")
+		return nil
+	}
+	log.Warn("removals exceed the removal guard, waiting for approval")
+
+	return &usernautdevv1alpha1.MembershipDiff{
+		ApprovalToken: token,
+		UsersToRemove: removalNames(usersToRemove, existingTeamMembers),
+	}
+}
+
+// removalGuardLimits returns the removal limits of the group, each limit of spec.removal_guard
+// overrides the one of the operator configuration
+func (r *GroupReconciler) removalGuardLimits(groupCR *usernautdevv1alpha1.Group) (int, int) {
+	maxRemovals := r.AppConfig.ControllerConfig.RemovalGuard.MaxRemovals
+	maxRemovalPercent := r.AppConfig.ControllerConfig.RemovalGuard.MaxRemovalPercent
+	if guard := groupCR.Spec.RemovalGuard; guard != nil {
+		if guard.MaxRemovals != nil {
+			maxRemovals = int(*guard.MaxRemovals)
+		}
+		if guard.MaxRemovalPercent != nil {
+			maxRemovalPercent = int(*guard.MaxRemovalPercent)
+		}
+	}
+	return maxRemovals, maxRemovalPercent
+}
+
+// removalsExceedLimits reports whether removing the given number of members from a team of teamSize
+// members exceeds either limit, a limit of 0 is disabled
+func removalsExceedLimits(removals, teamSize, maxRemovals, maxRemovalPercent int) bool {
+	if removals == 0 {
+		return false
+	}
+	if maxRemovals > 0 && removals > maxRemovals {
+		return true
+	}
+	return maxRemovalPercent > 0 && removals*100 > teamSize*maxRemovalPercent
+}
+
+// removalApprovalToken identifies a set of removals from a backend team, so that an approval
+// doesn't extend to removals computed later
+func removalApprovalToken(backendKey string, userIDs []string) string {
+	sum := sha256.Sum256([]byte(backendKey + "=" + strings.Join(slices.Sorted(slices.Values(userIDs)), ",")))
+	return hex.EncodeToString(sum[:])[:removalApprovalTokenLength]
+}
+
+// removalsApproved reports whether the approve-removals annotation of the group lists the token
+func removalsApproved(groupCR *usernautdevv1alpha1.Group, token string) bool {
+	approved := groupCR.GetAnnotations()[constants.ApproveRemovalsAnnotation]
+	for _, approvedToken := range strings.Split(approved, ",") {
+		if strings.TrimSpace(approvedToken) == token {
+			return true
 		}
 	}
+	return false
+}
 
-	return usersToAdd, usersToRemove, nil
+// blockedRemovalsMessage describes the removals held back by the removal guard and how to approve them,
+// or returns an empty string when no removals are blocked
+func blockedRemovalsMessage(groupCR *usernautdevv1alpha1.Group,
+	pendingChanges map[string]*usernautdevv1alpha1.MembershipDiff) string {
+	var blocked, tokens []string
+	for _, backend := range groupCR.Spec.Backends {
+		diff, ok := pendingChanges[backend.Name+"_"+backend.Type]
+		if !ok || diff.ApprovalToken == "" {
+			continue
+		}
+		blocked = append(blocked, fmt.Sprintf("%d from %s/%s", len(diff.UsersToRemove), backend.Type, backend.Name))
+		tokens = append(tokens, diff.ApprovalToken)
+	}
+	if len(blocked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("removal of %s exceeds the removal guard, approve with annotation %s=%s",
+		strings.Join(blocked, ", "), constants.ApproveRemovalsAnnotation, strings.Join(tokens, ","))
 }
 
 func (r *GroupReconciler) createUsersInBackendAndCache(ctx context.Context,
@@ -924,7 +2191,6 @@ func (r *GroupReconciler) createUsersInBackendAndCache(ctx context.Context,
 	backendName, backendType string,
 	backendClient clients.Client) error {
 
-	// NOTE: CacheMutex is already held by caller (Reconcile)
 	backendKey := backendName + "_" + backendType
 
 	var errs []error
@@ -935,59 +2201,227 @@ func (r *GroupReconciler) createUsersInBackendAndCache(ctx context.Context,
 			continue
 		}
 
-		// Get user backends from cache
-		userBackends, err := r.Store.User.GetBackends(ctx, userDetails.GetEmail())
+		// Check if user already has ID for this backend, which is the case for most of the
+		// members, before locking the user
+		exists, err := r.userExistsInBackend(ctx, userDetails.GetUID(), backendKey)
 		if err != nil {
 			r.backendLogger.WithField("user", user).WithError(err).Error("error fetching user details from cache")
 			errs = append(errs, err)
 			continue
 		}
-
-		// Check if user already has ID for this backend
-		if userID, exists := userBackends[backendKey]; exists && userID != "" {
+		if exists {
 			r.backendLogger.WithField("user", user).Debug("user already exists in cache")
 			continue
 		}
 
-		// if user details are not found in cache, create a new user in backend
-		// Standardize first/last names for backends (e.g. Fivetran) that do not support ., (, ), or , in names
-		newUser, err := backendClient.CreateUser(ctx, &structs.User{
-			Email:     userDetails.GetEmail(),
-			UserName:  user,
-			Role:      fivetran.AccountReviewerRole,
-			FirstName: utils.StandardizeNameForBackend(userDetails.GetDisplayName()),
-			LastName:  utils.StandardizeNameForBackend(userDetails.GetSN()),
+		// The user is locked while it's created so that the groups it is a member of don't create it twice
+		err = r.withUsersLocked(ctx, []string{userDetails.GetUID()}, func(ctx context.Context) error {
+			return r.createUser(ctx, user, userDetails, backendKey, backendType, backendClient)
 		})
 		if err != nil {
-			r.backendLogger.WithField("user", user).WithError(err).Error("error creating user in backend")
 			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// userExistsInBackend reports whether the cache has the ID of the user in the backend
+func (r *GroupReconciler) userExistsInBackend(ctx context.Context, uid, backendKey string) (bool, error) {
+	userBackends, err := r.Store.User.GetBackends(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return userBackends[backendKey] != "", nil
+}
+
+// createUser creates the user in the backend and stores its ID in the cache, unless another group
+// created it since it was looked up
+// NOTE: Caller must hold the user lock
+func (r *GroupReconciler) createUser(ctx context.Context, user string, userDetails *structs.LDAPUser,
+	backendKey, backendType string, backendClient clients.Client) error {
+	exists, err := r.userExistsInBackend(ctx, userDetails.GetUID(), backendKey)
+	if err != nil {
+		r.backendLogger.WithField("user", user).WithError(err).Error("error fetching user details from cache")
+		return err
+	}
+	if exists {
+		r.backendLogger.WithField("user", user).Debug("user created by another group")
+		return nil
+	}
+
+	// if user details are not found in cache, create a new user in backend
+	// Standardize first/last names for backends (e.g. Fivetran) that do not support ., (, ), or , in names
+	newUser, err := backendClient.CreateUser(ctx, &structs.User{
+		Email:     userDetails.GetEmail(),
+		UserName:  user,
+		Role:      fivetran.AccountReviewerRole,
+		FirstName: utils.StandardizeNameForBackend(userDetails.GetDisplayName()),
+		LastName:  utils.StandardizeNameForBackend(userDetails.GetSN()),
+	})
+	if err != nil {
+		r.backendLogger.WithField("user", user).WithError(err).Error("error creating user in backend")
+		return err
+	}
+	r.backendLogger.WithField("user", user).Debug("created user in backend successfully")
+	metrics.AddBackendUsers(backendType, metrics.UsersCreated, 1)
+
+	// Update cache with new user ID
+	if err := r.Store.User.SetBackend(ctx, userDetails.GetUID(), backendKey, newUser.ID); err != nil {
+		r.backendLogger.Error(err, "error updating user details in cache")
+		return err
+	}
+	r.backendLogger.WithField("user", user).Debug("updated user details in cache successfully")
+	return nil
+}
+
+// managedServiceAccounts returns the IDs of the service account members of the team that were created
+// by usernaut, any service account of the group is recorded in the cache once it has been created
+func (r *GroupReconciler) managedServiceAccounts(ctx context.Context, existingTeamMembers map[string]*structs.User,
+	backendKey string) (map[string]bool, error) {
+	if !slices.ContainsFunc(slices.Collect(maps.Values(existingTeamMembers)), (*structs.User).IsServiceAccount) {
+		return nil, nil
+	}
+
+	serviceAccounts, err := r.Store.ServiceAccount.GetBackendServiceAccounts(ctx, backendKey)
+	if err != nil {
+		r.backendLogger.WithError(err).Error("error fetching service accounts from cache")
+		return nil, err
+	}
+	managed := make(map[string]bool, len(serviceAccounts))
+	for userID := range serviceAccounts {
+		managed[userID] = true
+	}
+	return managed, nil
+}
+
+// ensureServiceAccounts creates the service accounts of the group that don't have a user in the
+// backend yet and returns the backend user ID of every service account by name
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) ensureServiceAccounts(ctx context.Context,
+	serviceAccounts []usernautdevv1alpha1.ServiceAccount,
+	backendName, backendType string,
+	backendClient clients.Client) (map[string]string, error) {
+
+	backendKey := backendName + "_" + backendType
+	serviceAccountIDs, err := r.lookupServiceAccountIDs(ctx, serviceAccounts, backendKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, serviceAccount := range serviceAccounts {
+		if serviceAccountIDs[serviceAccount.Name] != "" {
 			continue
 		}
-		r.backendLogger.WithField("user", user).Debug("created user in backend successfully")
 
-		// Update cache with new user ID
-		if err := r.Store.User.SetBackend(ctx, userDetails.GetEmail(), backendKey, newUser.ID); err != nil {
-			r.backendLogger.Error(err, "error updating user details in cache")
+		// The service account is locked while it's created so that the groups sharing it don't create it twice
+		err := locker.Do(ctx, r.Locker, []string{locker.ServiceAccountKey(serviceAccount.Name)},
+			func(ctx context.Context) error {
+				userID, err := r.createServiceAccount(ctx, serviceAccount, backendKey, backendType, backendClient)
+				serviceAccountIDs[serviceAccount.Name] = userID
+				return err
+			})
+		if err != nil {
 			errs = append(errs, err)
-			continue
 		}
-		r.backendLogger.WithField("user", user).Debug("updated user details in cache successfully")
 	}
-	return errors.Join(errs...)
+	return serviceAccountIDs, errors.Join(errs...)
+}
+
+// createServiceAccount creates the service account in the backend and stores its ID in the cache,
+// unless another group created it since it was looked up, and returns its backend user ID
+// NOTE: Caller must hold the service account lock
+func (r *GroupReconciler) createServiceAccount(ctx context.Context, serviceAccount usernautdevv1alpha1.ServiceAccount,
+	backendKey, backendType string, backendClient clients.Client) (string, error) {
+	backends, err := r.Store.ServiceAccount.GetBackends(ctx, serviceAccount.Name)
+	if err != nil {
+		return "", err
+	}
+	if backends[backendKey] != "" {
+		return backends[backendKey], nil
+	}
+
+	newUser, err := backendClient.CreateUser(ctx, &structs.User{
+		Email:          serviceAccount.Email,
+		UserName:       serviceAccount.Name,
+		Role:           fivetran.AccountReviewerRole,
+		ServiceAccount: true,
+	})
+	if err != nil {
+		r.backendLogger.WithField("service_account", serviceAccount.Name).WithError(err).
+			Error("error creating service account in backend")
+		return "", err
+	}
+	r.backendLogger.WithField("service_account", serviceAccount.Name).Debug("created service account in backend successfully")
+	metrics.AddBackendUsers(backendType, metrics.UsersCreated, 1)
+
+	if err := r.Store.ServiceAccount.SetBackend(ctx, serviceAccount.Name, backendKey, newUser.ID); err != nil {
+		r.backendLogger.WithError(err).Error("error updating service account details in cache")
+		return "", err
+	}
+	return newUser.ID, nil
 }
 
+// lookupServiceAccountIDs returns the backend user ID of every service account by name from the cache,
+// the ID is empty for the service accounts that don't have a user in the backend yet
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) lookupServiceAccountIDs(ctx context.Context,
+	serviceAccounts []usernautdevv1alpha1.ServiceAccount,
+	backendKey string) (map[string]string, error) {
+
+	serviceAccountIDs := make(map[string]string, len(serviceAccounts))
+	for _, serviceAccount := range serviceAccounts {
+		backends, err := r.Store.ServiceAccount.GetBackends(ctx, serviceAccount.Name)
+		if err != nil {
+			return nil, err
+		}
+		serviceAccountIDs[serviceAccount.Name] = backends[backendKey]
+	}
+	return serviceAccountIDs, nil
+}
+
+// lookupTeamID returns the ID of the existing team of the group in the backend from the GroupStore,
+// falling back to the TeamStore, or an empty ID when the team doesn't exist yet. A team adopted
+// through a teamName override takes precedence over the team in the GroupStore, see fetchOrCreateTeam.
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) lookupTeamID(ctx context.Context, groupName string,
+	backendParams *structs.BackendParams) (string, error) {
+	backendName := backendParams.GetName()
+	backendType := backendParams.GetType()
+
+	teamID, err := r.Store.Group.GetBackendID(ctx, groupName, backendName, backendType)
+	if err != nil || (teamID != "" && backendParams.GetTeamName() == "") {
+		return teamID, err
+	}
+
+	transformedGroupName, err := utils.GetBackendTeamName(r.AppConfig, backendType, backendParams.GetTeamName(), groupName)
+	if err != nil {
+		return "", err
+	}
+	teamBackends, err := r.Store.Team.GetBackends(ctx, transformedGroupName)
+	if err != nil {
+		return "", err
+	}
+	if id := teamBackends[backendName+"_"+backendType]; id != "" {
+		return id, nil
+	}
+	return teamID, nil
+}
+
+// fetchOrCreateTeam returns the ID of the team of the group in the backend, creating the team when
+// it doesn't exist yet, and whether the team was created
 func (r *GroupReconciler) fetchOrCreateTeam(ctx context.Context,
 	groupName string, backendClient clients.Client,
-	backendParams *structs.BackendParams) (string, error) {
+	backendParams *structs.BackendParams) (string, bool, error) {
 
 	backendName := backendParams.GetName()
 	backendType := backendParams.GetType()
 
 	// Get transformed group name for backend API calls (team name in backend system)
-	transformedGroupName, err := utils.GetTransformedGroupName(r.AppConfig, backendType, groupName)
+	transformedGroupName, err := utils.GetBackendTeamName(r.AppConfig, backendType, backendParams.GetTeamName(), groupName)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error transforming the group Name")
-		return "", err
+		return "", false, err
 	}
 
 	backendKey := backendName + "_" + backendType
@@ -996,32 +2430,40 @@ func (r *GroupReconciler) fetchOrCreateTeam(ctx context.Context,
 	teamID, err := r.Store.Group.GetBackendID(ctx, groupName, backendName, backendType)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error fetching team details from GroupStore")
-		return "", err
+		return "", false, err
 	}
 
-	if teamID != "" {
+	// A teamName override may point to an existing team other than the one in the GroupStore,
+	// which is then adopted through the TeamStore below
+	if teamID != "" && backendParams.GetTeamName() == "" {
 		r.backendLogger.WithField("teamID", teamID).Info("team details found in GroupStore")
-		return teamID, nil
+		return teamID, false, nil
 	}
 
 	// Step 2: Fallback to TeamStore (using transformed name, populated during preload)
+	// Adopting the team is not a store migration: which group a team found in the backend belongs to
+	// depends on the Group CRs and the team name transformations, not only on the cache records
 	teamBackends, err := r.Store.Team.GetBackends(ctx, transformedGroupName)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error fetching team details from TeamStore")
-		return "", err
+		return "", false, err
 	}
 
-	if id, exists := teamBackends[backendKey]; exists && id != "" {
-		r.backendLogger.WithField("teamID", id).Info("team details found in TeamStore, migrating to GroupStore")
+	if id, exists := teamBackends[backendKey]; exists && id != "" && id != teamID {
+		r.backendLogger.WithField("teamID", id).Info("team details found in TeamStore, adopting it in GroupStore")
 
-		// Migrate data from TeamStore to GroupStore
 		if err := r.Store.Group.SetBackend(ctx, groupName, backendName, backendType, id); err != nil {
-			r.backendLogger.WithError(err).Error("error migrating team details to GroupStore")
-			return "", err
+			r.backendLogger.WithError(err).Error("error adopting team details in GroupStore")
+			return "", false, err
 		}
 
-		r.backendLogger.Info("successfully migrated team details from TeamStore to GroupStore")
-		return id, nil
+		r.backendLogger.Info("successfully adopted team details from TeamStore in GroupStore")
+		return id, false, nil
+	}
+
+	if teamID != "" {
+		r.backendLogger.WithField("teamID", teamID).Info("team details found in GroupStore")
+		return teamID, false, nil
 	}
 
 	// Step 3: Team not found in either store, create a new team
@@ -1034,7 +2476,7 @@ func (r *GroupReconciler) fetchOrCreateTeam(ctx context.Context,
 	})
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error creating team in backend")
-		return "", err
+		return "", false, err
 	}
 
 	r.backendLogger.Info("created team in backend successfully")
@@ -1042,12 +2484,12 @@ func (r *GroupReconciler) fetchOrCreateTeam(ctx context.Context,
 	// Store in GroupStore only - TeamStore is populated by preloadCache and used as read-only fallback
 	if err := r.Store.Group.SetBackend(ctx, groupName, backendName, backendType, newTeam.ID); err != nil {
 		r.backendLogger.WithError(err).Error("error updating team details in GroupStore")
-		return "", err
+		return "", false, err
 	}
 
 	r.backendLogger.Info("updated team details in GroupStore successfully")
 
-	return newTeam.ID, nil
+	return newTeam.ID, true, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -1091,6 +2533,8 @@ func (r *GroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	// force reconcile flag
 	labelPredicate := controllerutils.ForceReconcilePredicate()
+	// approval of the removals blocked by the removal guard
+	approveRemovalsPredicate := controllerutils.ApproveRemovalsPredicate()
 
 	maxConcurrentReconciles := r.AppConfig.ControllerConfig.MaxConcurrentReconciles
 	if maxConcurrentReconciles <= 0 {
@@ -1104,7 +2548,7 @@ func (r *GroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&usernautdevv1alpha1.Group{}).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, labelPredicate)).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, labelPredicate, approveRemovalsPredicate)).
 		Watches(
 			client.Object(&usernautdevv1alpha1.Group{}),
 			handler.EnqueueRequestsFromMapFunc(mapFunc),
@@ -1116,7 +2560,7 @@ func (r *GroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func (r *GroupReconciler) fetchUniqueGroupMembers(ctx context.Context, groupName,
-	namespace string, visitedOnPath map[string]struct{}) ([]string, error) {
+	namespace string, visitedOnPath map[string]struct{}, timeBound *timeBoundMembers) ([]string, error) {
 
 	r.log.WithField("group", groupName).Info("fetching group members")
 
@@ -1134,11 +2578,9 @@ func (r *GroupReconciler) fetchUniqueGroupMembers(ctx context.Context, groupName
 		return nil, err
 	}
 
-	members := make([]string, 0)
-	members = append(members, groupCR.Spec.Members.Users...)
-
+	members := declaredMembers(groupCR, timeBound)
 	for _, subGroup := range groupCR.Spec.Members.Groups {
-		subMembers, err := r.fetchUniqueGroupMembers(ctx, subGroup, namespace, visitedOnPath)
+		subMembers, err := r.fetchUniqueGroupMembers(ctx, subGroup, namespace, visitedOnPath, timeBound)
 		if err != nil {
 			return nil, err
 		}
@@ -1148,6 +2590,160 @@ func (r *GroupReconciler) fetchUniqueGroupMembers(ctx context.Context, groupName
 	return members, nil
 }
 
+// declaredMembers returns the members listed by the group CR itself, without the ones of its nested groups
+func declaredMembers(groupCR *usernautdevv1alpha1.Group, timeBound *timeBoundMembers) []string {
+	members := make([]string, 0)
+	members = append(members, groupCR.Spec.Members.Users...)
+	members = append(members, groupCR.Spec.Owners...)
+	members = append(members, timeBound.activeUsers(groupCR)...)
+	return members
+}
+
+// nestedGroup is a group listed in members.groups of a Group CR, with all of its members
+type nestedGroup struct {
+	name    string
+	members []string
+}
+
+// fetchNestedGroups returns the members listed by the group CR itself and its nested groups, the members
+// of each nested group include the ones of the groups nested in it
+func (r *GroupReconciler) fetchNestedGroups(ctx context.Context, groupCR *usernautdevv1alpha1.Group,
+	timeBound *timeBoundMembers) ([]string, []nestedGroup, error) {
+	r.log.WithField("group", groupCR.Name).Info("fetching group members")
+
+	visitedOnPath := map[string]struct{}{groupCR.Name: {}}
+	nestedGroups := make([]nestedGroup, 0, len(groupCR.Spec.Members.Groups))
+	for _, subGroup := range groupCR.Spec.Members.Groups {
+		members, err := r.fetchUniqueGroupMembers(ctx, subGroup, groupCR.Namespace, visitedOnPath, timeBound)
+		if err != nil {
+			return nil, nil, err
+		}
+		nestedGroups = append(nestedGroups, nestedGroup{name: subGroup, members: members})
+	}
+	return declaredMembers(groupCR, timeBound), nestedGroups, nil
+}
+
+// groupNesting describes the members of a group whose nested groups are mapped to backend-native nesting
+type groupNesting struct {
+	// directMembers are the members that don't come from the nested groups
+	directMembers []string
+	// nestedGroups are the nested groups whose teams can be nested in the team of the group
+	nestedGroups []nestedGroup
+}
+
+// newGroupNesting returns the nesting of a group from its direct members, including the LDAP ones, and
+// its nested groups. Backend nesting can't exclude members, so the nested groups with an excluded member
+// are flattened into the direct members instead.
+func (r *GroupReconciler) newGroupNesting(directMembers []string, nestedGroups []nestedGroup,
+	excluded map[string]struct{}) *groupNesting {
+	nesting := &groupNesting{directMembers: slices.Clone(directMembers)}
+	for _, nested := range nestedGroups {
+		if slices.ContainsFunc(nested.members, func(member string) bool {
+			_, ok := excluded[member]
+			return ok
+		}) {
+			r.log.WithField("nested_group", nested.name).
+				Info("nested group has excluded members, adding its members individually")
+			nesting.directMembers = append(nesting.directMembers, nested.members...)
+			continue
+		}
+		nesting.nestedGroups = append(nesting.nestedGroups, nested)
+	}
+	nesting.directMembers, _ = excludeMembers(r.deduplicateMembers(nesting.directMembers), excluded)
+	return nesting
+}
+
+// resolveNesting returns the members to add to the team of the backend individually and the IDs of the
+// teams to nest in it. The nested groups that don't have a team on the backend yet are flattened.
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) resolveNesting(ctx context.Context, namespace string,
+	backend usernautdevv1alpha1.Backend, nesting *groupNesting) ([]string, []string, error) {
+	members := slices.Clone(nesting.directMembers)
+	nestedTeamIDs := make([]string, 0, len(nesting.nestedGroups))
+	for _, nested := range nesting.nestedGroups {
+		teamID, err := r.nestedTeamID(ctx, namespace, nested.name, backend)
+		if err != nil {
+			return nil, nil, err
+		}
+		if teamID == "" {
+			r.backendLogger.WithField("nested_group", nested.name).
+				Info("nested group has no team on the backend, adding its members individually")
+			members = append(members, nested.members...)
+			continue
+		}
+		if !slices.Contains(nestedTeamIDs, teamID) {
+			nestedTeamIDs = append(nestedTeamIDs, teamID)
+		}
+	}
+	return r.deduplicateMembers(members), nestedTeamIDs, nil
+}
+
+// nestedTeamID returns the ID of the team of the nested group CR on the backend, or an empty string when
+// the nested group doesn't list the backend or its team hasn't been created yet
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) nestedTeamID(ctx context.Context, namespace, name string,
+	backend usernautdevv1alpha1.Backend) (string, error) {
+	nestedCR := &usernautdevv1alpha1.Group{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, nestedCR); err != nil {
+		return "", err
+	}
+	for _, nestedBackend := range nestedCR.Spec.Backends {
+		if nestedBackend.Name == backend.Name && nestedBackend.Type == backend.Type {
+			return r.lookupTeamID(ctx, appliedGroupName(nestedCR), &structs.BackendParams{
+				Name:     nestedBackend.Name,
+				Type:     nestedBackend.Type,
+				TeamName: nestedBackend.TeamName,
+			})
+		}
+	}
+	return "", nil
+}
+
+// nestedTeamChanges are the teams to nest in a team and to unnest from it
+type nestedTeamChanges struct {
+	toNest   []string
+	toUnnest []string
+	// stale is true when the nested teams recorded in the GroupStore differ from the desired ones
+	stale bool
+}
+
+func (c nestedTeamChanges) changed() bool {
+	return c.stale || len(c.toNest) > 0 || len(c.toUnnest) > 0
+}
+
+// planNestedTeams compares the teams nested in the team with the desired ones. Only the teams nested by
+// the operator, as recorded in the GroupStore, are unnested so that nesting done outside of it is kept.
+// NOTE: Caller must hold the group lock
+func (r *GroupReconciler) planNestedTeams(ctx context.Context, nester clients.TeamNester, groupName string,
+	backend usernautdevv1alpha1.Backend, teamID string, desired []string) (nestedTeamChanges, error) {
+	managed, err := r.Store.Group.GetNestedTeams(ctx, groupName, backend.Name, backend.Type)
+	if err != nil {
+		return nestedTeamChanges{}, err
+	}
+	// nothing is nested nor has to be, which is the case of every group that doesn't use native nesting
+	if len(managed) == 0 && len(desired) == 0 {
+		return nestedTeamChanges{}, nil
+	}
+
+	current, err := nester.FetchNestedTeams(ctx, teamID)
+	if err != nil {
+		return nestedTeamChanges{}, err
+	}
+	changes := nestedTeamChanges{stale: !slices.Equal(slices.Sorted(slices.Values(managed)),
+		slices.Sorted(slices.Values(desired)))}
+	for _, id := range desired {
+		if !slices.Contains(current, id) {
+			changes.toNest = append(changes.toNest, id)
+		}
+	}
+	for _, id := range managed {
+		if slices.Contains(current, id) && !slices.Contains(desired, id) {
+			changes.toUnnest = append(changes.toUnnest, id)
+		}
+	}
+	return changes, nil
+}
+
 func (r *GroupReconciler) deduplicateMembers(members []string) []string {
 	// Deduplicate groupMembers before setting status
 	uniqueMembersMap := make(map[string]struct{})
@@ -1240,28 +2836,35 @@ func (r *GroupReconciler) setupLdapSync(backendType string,
 			return false, nil
 		}
 
+		dependant, ok := findDependant(backends, dependsOn)
+		if !ok {
+			return false, fmt.Errorf("ldap dependants for %s backend doesn't exist in group CR", backendType)
+		}
+
 		// Check if the dependent backend exists in cache (using original group name)
-		err := r.ldapDependantChecks(dependsOn, groupName)
+		err := r.ldapDependantChecks(dependsOn, groupName, dependant.TeamName)
 		if err != nil {
 			return false, err
 		}
 
-		if !isGroupCRHasDependants(backends, dependsOn) {
-			return false, fmt.Errorf("ldap dependants for %s backend doesn't exist in group CR", backendType)
+		// the LDAP group is the team of the dependant backend, named after the group unless overridden
+		ldapGroupName := groupName
+		if dependant.TeamName != "" {
+			ldapGroupName = dependant.TeamName
 		}
 
 		gitlabClient, ok := backendClient.(*gitlab.GitlabClient)
 		if !ok {
 			return false, errors.New("backend client is not a GitlabClient")
 		}
-		gitlabClient.SetLdapSync(true, groupName)
+		gitlabClient.SetLdapSync(true, ldapGroupName)
 		r.backendLogger.Infof("ldap sync setup successfully for %s", backendType)
 		return true, nil
 	}
 	return false, nil
 }
 
-func (r *GroupReconciler) ldapDependantChecks(dependsOn config.Dependant, groupName string) error {
+func (r *GroupReconciler) ldapDependantChecks(dependsOn config.Dependant, groupName, teamName string) error {
 	dependantType, ok := r.AppConfig.BackendMap[dependsOn.Type]
 	if !ok {
 		return fmt.Errorf("ldap dependant type %s not found in BackendMap", dependsOn.Type)
@@ -1275,7 +2878,7 @@ func (r *GroupReconciler) ldapDependantChecks(dependsOn config.Dependant, groupN
 	}
 
 	// Check if the group exists in cache with the dependent backend configured
-	// NOTE: This is called without holding CacheMutex (called from ldap sync)
+	// NOTE: This is called without holding the group lock (called from ldap sync)
 
 	// First check GroupStore (using original group name)
 	exists, err := r.Store.Group.BackendExists(context.Background(), groupName, dependsOn.Name, dependsOn.Type)
@@ -1284,7 +2887,7 @@ func (r *GroupReconciler) ldapDependantChecks(dependsOn config.Dependant, groupN
 	}
 
 	// Fallback to TeamStore (using transformed name)
-	transformedGroupName, err := utils.GetTransformedGroupName(r.AppConfig, dependsOn.Type, groupName)
+	transformedGroupName, err := utils.GetBackendTeamName(r.AppConfig, dependsOn.Type, teamName, groupName)
 	if err != nil {
 		r.backendLogger.WithError(err).Error("error transforming group name for ldap dependant check")
 		return err
@@ -1305,11 +2908,13 @@ func (r *GroupReconciler) ldapDependantChecks(dependsOn config.Dependant, groupN
 	return fmt.Errorf("dependent backend %s not found in cache for group %s", backendKey, groupName)
 }
 
-func isGroupCRHasDependants(backends []usernautdevv1alpha1.Backend, dependsOn config.Dependant) bool {
+// findDependant returns the backend of the group CR that the ldap sync depends on
+func findDependant(backends []usernautdevv1alpha1.Backend,
+	dependsOn config.Dependant) (usernautdevv1alpha1.Backend, bool) {
 	for _, backend := range backends {
 		if backend.Type == dependsOn.Type && backend.Name == dependsOn.Name {
-			return true
+			return backend, true
 		}
 	}
-	return false
+	return usernautdevv1alpha1.Backend{}, false
 }
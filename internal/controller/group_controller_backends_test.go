@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+)
+
+func TestBackendDependencies(t *testing.T) {
+	t.Parallel()
+
+	r := &GroupReconciler{AppConfig: &config.AppConfig{
+		BackendMap: map[string]map[string]config.Backend{
+			"gitlab": {
+				"gitlab":   {DependsOn: config.Dependant{Name: "rover", Type: "rover"}},
+				"gitlab-2": {DependsOn: config.Dependant{Name: "rover-2", Type: "rover"}},
+			},
+			"rover": {"rover": {}},
+			"fivetran": {
+				"a": {DependsOn: config.Dependant{Name: "b", Type: "fivetran"}},
+				"b": {DependsOn: config.Dependant{Name: "a", Type: "fivetran"}},
+			},
+		},
+	}}
+
+	backends := []usernautdevv1alpha1.Backend{
+		{Name: "gitlab", Type: "gitlab"},
+		{Name: "gitlab-2", Type: "gitlab"},
+		{Name: "rover", Type: "rover"},
+		{Name: "a", Type: "fivetran"},
+		{Name: "b", Type: "fivetran"},
+	}
+
+	// gitlab-2 depends on a backend the group doesn't use and the cycle between a and b is broken
+	assert.Equal(t, map[string]string{
+		"gitlab_gitlab": "rover_rover",
+		"b_fivetran":    "a_fivetran",
+	}, r.backendDependencies(backends))
+}
+
+func TestMaxConcurrentBackends(t *testing.T) {
+	t.Parallel()
+
+	r := &GroupReconciler{AppConfig: &config.AppConfig{}}
+	assert.Equal(t, defaultMaxConcurrentBackends, r.maxConcurrentBackends())
+
+	r.AppConfig.ControllerConfig.MaxConcurrentBackends = 2
+	assert.Equal(t, 2, r.maxConcurrentBackends())
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	clientmocks "github.com/redhat-data-and-ai/usernaut/internal/controller/periodicjobs/mocks"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+func TestDeletionPolicyFor(t *testing.T) {
+	t.Parallel()
+
+	gitlab := usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab", DeletionPolicy: usernautdevv1alpha1.DeletionPolicyOrphan}
+	fivetran := usernautdevv1alpha1.Backend{Name: "fivetran", Type: "fivetran"}
+
+	spec := usernautdevv1alpha1.GroupSpec{}
+	assert.Equal(t, usernautdevv1alpha1.DeletionPolicyDelete, spec.DeletionPolicyFor(fivetran))
+	assert.Equal(t, usernautdevv1alpha1.DeletionPolicyOrphan, spec.DeletionPolicyFor(gitlab))
+
+	spec.DeletionPolicy = usernautdevv1alpha1.DeletionPolicyRetain
+	assert.Equal(t, usernautdevv1alpha1.DeletionPolicyRetain, spec.DeletionPolicyFor(fivetran))
+	assert.Equal(t, usernautdevv1alpha1.DeletionPolicyOrphan, spec.DeletionPolicyFor(gitlab))
+}
+
+func TestRemoveAllTeamMembers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	backendClient := clientmocks.NewMockClient(ctrl)
+	r := &GroupReconciler{}
+
+	backendClient.EXPECT().FetchTeamMembersByTeamID(gomock.Any(), "team-1").Return(map[string]*structs.User{
+		"2": {ID: "2"},
+		"1": {ID: "1"},
+	}, nil)
+	backendClient.EXPECT().RemoveUserFromTeam(gomock.Any(), "team-1", []string{"1", "2"}).Return(nil)
+	require.NoError(t, r.removeAllTeamMembers(ctx, backendClient, "team-1"))
+
+	backendClient.EXPECT().FetchTeamMembersByTeamID(gomock.Any(), "team-2").Return(map[string]*structs.User{}, nil)
+	require.NoError(t, r.removeAllTeamMembers(ctx, backendClient, "team-2"))
+}
+
+func TestCleanupRemovedBackends(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store: store.New(inMemCache),
+		log:   logrus.NewEntry(logrus.New()),
+		AppConfig: &config.AppConfig{
+			Pattern: map[string][]config.PatternEntry{"default": {{Input: "^(.*)$", Output: "$1"}}},
+		},
+	}
+
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{
+			GroupName: "team-a",
+			Backends:  []usernautdevv1alpha1.Backend{{Name: "fivetran", Type: "fivetran"}},
+		},
+	}
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "fivetran", "fivetran", "f-1"))
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "snowflake", "snowflake", "s-1"))
+
+	removed, err := r.removedBackends(ctx, groupCR)
+	require.NoError(t, err)
+	assert.Equal(t, []usernautdevv1alpha1.Backend{{Name: "snowflake", Type: "snowflake"}}, removed)
+
+	// snowflake is not configured anymore, so its team can only be left untouched
+	statuses, err := r.cleanupRemovedBackends(ctx, groupCR)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Status)
+	assert.Equal(t, "snowflake", statuses[0].Name)
+	assert.Contains(t, statuses[0].Message, "not configured anymore")
+
+	backends, err := r.Store.Group.GetBackends(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Len(t, backends, 1)
+	assert.Contains(t, backends, "fivetran_fivetran")
+}
+
+func TestRemovedBackendMessage(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Removed from spec, team deleted", removedBackendMessage(usernautdevv1alpha1.DeletionPolicyDelete))
+	assert.Equal(t, "Removed from spec, team retained", removedBackendMessage(usernautdevv1alpha1.DeletionPolicyRetain))
+	assert.Equal(t, "Removed from spec, team members removed", removedBackendMessage(usernautdevv1alpha1.DeletionPolicyOrphan))
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/internal/controller/mocks"
+)
+
+func TestExcludeMembers(t *testing.T) {
+	t.Parallel()
+
+	members := []string{"carol", "alice", "svc-bot", "bob"}
+
+	kept, removed := excludeMembers(members, map[string]struct{}{"svc-bot": {}, "bob": {}, "unknown": {}})
+	assert.Equal(t, []string{"carol", "alice"}, kept)
+	assert.Equal(t, []string{"bob", "svc-bot"}, removed)
+
+	kept, removed = excludeMembers(members, map[string]struct{}{})
+	assert.Equal(t, members, kept)
+	assert.Nil(t, removed)
+}
+
+func TestFetchExcludedMembers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{LdapConn: ldapClient}
+
+	query := &usernautdevv1alpha1.LDAPQuery{
+		Operator: "and",
+		Filters:  []usernautdevv1alpha1.LDAPFilter{{Key: "employeeType", Criteria: "equals", Value: "Contractor"}},
+	}
+	ldapClient.EXPECT().BuildLDAPQueryFromSpec(gomock.Any(), query).Return("(employeeType=Contractor)", nil)
+	ldapClient.EXPECT().GetQueryMembers(gomock.Any(), "(employeeType=Contractor)").Return([]string{"dave", "erin"}, nil)
+
+	excluded, err := r.fetchExcludedMembers(ctx, &usernautdevv1alpha1.MemberExclusion{
+		Users:     []string{"svc-bot"},
+		LDAPQuery: query,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"svc-bot": {}, "dave": {}, "erin": {}}, excluded)
+
+	excluded, err = r.fetchExcludedMembers(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, excluded)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+// testReconcilerOption customizes the GroupReconciler built by newTestReconciler
+type testReconcilerOption func(*GroupReconciler)
+
+// newTestReconciler returns a GroupReconciler backed by an in-memory cache and locker, whose team
+// names are the group names unless a pattern is added with withPattern
+func newTestReconciler(t *testing.T, opts ...testReconcilerOption) *GroupReconciler {
+	t.Helper()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store:         store.New(inMemCache),
+		Locker:        locker.NewMemoryLocker(),
+		log:           logrus.NewEntry(logrus.New()),
+		backendLogger: logrus.NewEntry(logrus.New()),
+		AppConfig: &config.AppConfig{
+			Pattern: map[string][]config.PatternEntry{"default": {{Input: "^(.*)$", Output: "$1"}}},
+		},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// withPattern sets the team name pattern of a backend type
+func withPattern(backendType string, entries ...config.PatternEntry) testReconcilerOption {
+	return func(r *GroupReconciler) {
+		r.AppConfig.Pattern[backendType] = entries
+	}
+}
+
+// withLdapUsers sets the LDAP data of the users, whose email is derived from their uid
+func withLdapUsers(uids ...string) testReconcilerOption {
+	return func(r *GroupReconciler) {
+		r.allLdapUserData = make(map[string]*structs.LDAPUser, len(uids))
+		for _, uid := range uids {
+			r.allLdapUserData[uid] = &structs.LDAPUser{UID: uid, Email: uid + "@example.com"}
+		}
+	}
+}
+
+// withGroups serves the Group CRs through the client of the reconciler
+func withGroups(groups ...*usernautdevv1alpha1.Group) testReconcilerOption {
+	return func(r *GroupReconciler) {
+		getter := &groupGetter{groups: make(map[string]*usernautdevv1alpha1.Group, len(groups))}
+		for _, group := range groups {
+			getter.groups[group.Name] = group
+		}
+		r.Client = getter
+	}
+}
+
+// groupGetter is a minimal client.Client that only serves Group gets
+type groupGetter struct {
+	client.Client
+	groups map[string]*usernautdevv1alpha1.Group
+}
+
+func (g *groupGetter) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	group, ok := g.groups[key.Name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "groups"}, key.Name)
+	}
+	group.DeepCopyInto(obj.(*usernautdevv1alpha1.Group))
+	return nil
+}
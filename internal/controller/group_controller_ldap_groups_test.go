@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-data-and-ai/usernaut/internal/controller/mocks"
+)
+
+func TestFetchLDAPGroupMembers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{LdapConn: ldapClient}
+
+	ldapClient.EXPECT().GetGroupMembers(gomock.Any(), "cn=a,ou=groups,dc=example,dc=com").Return([]string{"alice", "bob"}, nil)
+	ldapClient.EXPECT().GetGroupMembers(gomock.Any(), "cn=b,ou=groups,dc=example,dc=com").Return([]string{"bob", "carol"}, nil)
+
+	members, err := r.fetchLDAPGroupMembers(ctx, []string{"cn=a,ou=groups,dc=example,dc=com", "cn=b,ou=groups,dc=example,dc=com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "bob", "carol"}, members)
+}
+
+func TestFetchLDAPGroupMembers_Error(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{LdapConn: ldapClient}
+
+	ldapErr := errors.New("no LDAP entries found for group")
+	ldapClient.EXPECT().GetGroupMembers(gomock.Any(), "cn=missing,ou=groups,dc=example,dc=com").Return(nil, ldapErr)
+
+	members, err := r.fetchLDAPGroupMembers(ctx, []string{"cn=missing,ou=groups,dc=example,dc=com"})
+	require.ErrorIs(t, err, ldapErr)
+	assert.Contains(t, err.Error(), "cn=missing,ou=groups,dc=example,dc=com")
+	assert.Nil(t, members)
+}
@@ -1,12 +1,19 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/internal/controller/mocks"
+	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
 )
 
 func TestReplaceManagerInFilters(t *testing.T) {
@@ -260,3 +267,93 @@ func TestExtractManagerUIDsFromQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchQueryMembers_IndirectReports(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{LdapConn: ldapClient}
+
+	// vp manages alice and bob, carol reports to alice and manages vp, closing a cycle
+	reportsOf := map[string][]string{
+		"vp":    {"alice", "bob"},
+		"alice": {"carol"},
+		"carol": {"vp"},
+	}
+	managerFilter := regexp.MustCompile(`\(manager=uid=([^,]+),ou=users\)`)
+
+	ldapClient.EXPECT().BuildLDAPQueryFromSpec(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, query *usernautdevv1alpha1.LDAPQuery) (string, error) {
+			return ldap.BuildQueryFromSpec(query, "ou=users")
+		}).AnyTimes()
+
+	var mu sync.Mutex
+	var searches []string
+	ldapClient.EXPECT().GetQueryMembers(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filter string) ([]string, error) {
+			mu.Lock()
+			searches = append(searches, filter)
+			mu.Unlock()
+			var members []string
+			for _, match := range managerFilter.FindAllStringSubmatch(filter, -1) {
+				members = append(members, reportsOf[match[1]]...)
+			}
+			return members, nil
+		}).AnyTimes()
+
+	query := &usernautdevv1alpha1.LDAPQuery{
+		Operator: "and",
+		Filters:  []usernautdevv1alpha1.LDAPFilter{{Key: "manager", Criteria: "equals", Value: "vp"}},
+	}
+
+	members, err := r.fetchQueryMembers(ctx, query, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, members)
+
+	searches = nil
+	members, err = r.fetchQueryMembers(ctx, query, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob", "carol", "vp"}, members)
+
+	// one search for the query and one per level, the managers of a level are batched in one filter
+	assert.Equal(t, []string{
+		"(&(manager=uid=vp,ou=users))",
+		"(|(&(manager=uid=alice,ou=users))(&(manager=uid=bob,ou=users)))",
+		"(&(manager=uid=carol,ou=users))",
+		"(&(manager=uid=vp,ou=users))",
+	}, searches)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = r.fetchIndirectReports(canceled, query, []string{"alice"})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFetchIndirectReports_FailedBatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{LdapConn: ldapClient}
+
+	ldapClient.EXPECT().BuildLDAPQueryFromSpec(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, query *usernautdevv1alpha1.LDAPQuery) (string, error) {
+			return ldap.BuildQueryFromSpec(query, "ou=users")
+		}).AnyTimes()
+	ldapClient.EXPECT().GetQueryMembers(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("ldap server unavailable")).AnyTimes()
+
+	query := &usernautdevv1alpha1.LDAPQuery{
+		Operator: "and",
+		Filters:  []usernautdevv1alpha1.LDAPFilter{{Key: "manager", Criteria: "equals", Value: "vp"}},
+	}
+
+	// the subtrees of the managers of the batch are not silently dropped, the reconciliation is retried
+	reports, err := r.fetchIndirectReports(ctx, query, []string{"alice", "bob"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ldap server unavailable")
+	assert.Nil(t, reports)
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	clientmocks "github.com/redhat-data-and-ai/usernaut/internal/controller/periodicjobs/mocks"
+)
+
+func TestNewGroupNesting(t *testing.T) {
+	t.Parallel()
+	r := newTestReconciler(t)
+
+	nestedGroups := []nestedGroup{
+		{name: "team-b", members: []string{"bob", "carol"}},
+		{name: "team-c", members: []string{"dave", "mallory"}},
+	}
+	excluded := map[string]struct{}{"mallory": {}}
+
+	// team-c can't be nested as mallory would be a member through it
+	nesting := r.newGroupNesting([]string{"alice", "mallory"}, nestedGroups, excluded)
+	assert.Equal(t, []string{"alice", "dave"}, nesting.directMembers)
+	assert.Equal(t, nestedGroups[:1], nesting.nestedGroups)
+}
+
+func TestResolveNesting(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	gitlab := usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab"}
+	r := newTestReconciler(t, withGroups(
+		&usernautdevv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-b", Namespace: "default"},
+			Spec:       usernautdevv1alpha1.GroupSpec{GroupName: "team-b", Backends: []usernautdevv1alpha1.Backend{gitlab}},
+		},
+		&usernautdevv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-c", Namespace: "default"},
+			Spec:       usernautdevv1alpha1.GroupSpec{GroupName: "team-c", Backends: []usernautdevv1alpha1.Backend{gitlab}},
+		},
+		&usernautdevv1alpha1.Group{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-d", Namespace: "default"},
+			Spec: usernautdevv1alpha1.GroupSpec{
+				GroupName: "team-d",
+				Backends:  []usernautdevv1alpha1.Backend{{Name: "rover", Type: "rover"}},
+			},
+		},
+	))
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-b", "gitlab", "gitlab", "20"))
+
+	nesting := &groupNesting{
+		directMembers: []string{"alice"},
+		nestedGroups: []nestedGroup{
+			{name: "team-b", members: []string{"bob"}},
+			// team-c has no gitlab team yet and team-d doesn't use gitlab
+			{name: "team-c", members: []string{"carol", "alice"}},
+			{name: "team-d", members: []string{"dave"}},
+		},
+	}
+	members, nestedTeamIDs, err := r.resolveNesting(ctx, "default", gitlab, nesting)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "carol", "dave"}, members)
+	assert.Equal(t, []string{"20"}, nestedTeamIDs)
+
+	nesting.nestedGroups = append(nesting.nestedGroups, nestedGroup{name: "missing"})
+	_, _, err = r.resolveNesting(ctx, "default", gitlab, nesting)
+	require.Error(t, err)
+}
+
+func TestPlanNestedTeams(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t)
+
+	ctrl := gomock.NewController(t)
+	nester := clientmocks.NewMockTeamNester(ctrl)
+	rover := usernautdevv1alpha1.Backend{Name: "rover", Type: "rover"}
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "rover", "rover", "team-a"))
+
+	// a group that doesn't use native nesting doesn't look at the backend
+	changes, err := r.planNestedTeams(ctx, nester, "team-a", rover, "team-a", nil)
+	require.NoError(t, err)
+	assert.False(t, changes.changed())
+
+	nester.EXPECT().FetchNestedTeams(gomock.Any(), "team-a").Return([]string{"manual"}, nil)
+	changes, err = r.planNestedTeams(ctx, nester, "team-a", rover, "team-a", []string{"team-b", "team-c"})
+	require.NoError(t, err)
+	assert.Equal(t, nestedTeamChanges{toNest: []string{"team-b", "team-c"}, stale: true}, changes)
+
+	require.NoError(t, r.Store.Group.SetNestedTeams(ctx, "team-a", "rover", "rover", []string{"team-b", "team-c"}))
+
+	// only the teams nested by the operator are unnested
+	nester.EXPECT().FetchNestedTeams(gomock.Any(), "team-a").Return([]string{"manual", "team-b", "team-c"}, nil)
+	changes, err = r.planNestedTeams(ctx, nester, "team-a", rover, "team-a", []string{"team-c"})
+	require.NoError(t, err)
+	assert.Equal(t, nestedTeamChanges{toUnnest: []string{"team-b"}, stale: true}, changes)
+
+	nester.EXPECT().FetchNestedTeams(gomock.Any(), "team-a").Return([]string{"team-c", "team-b"}, nil)
+	changes, err = r.planNestedTeams(ctx, nester, "team-a", rover, "team-a", []string{"team-c", "team-b"})
+	require.NoError(t, err)
+	assert.False(t, changes.changed())
+}
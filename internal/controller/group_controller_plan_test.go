@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+func TestAppliesChanges(t *testing.T) {
+	t.Parallel()
+
+	gitlab := usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab", Suspend: true}
+	fivetran := usernautdevv1alpha1.Backend{Name: "fivetran", Type: "fivetran"}
+
+	spec := usernautdevv1alpha1.GroupSpec{Backends: []usernautdevv1alpha1.Backend{fivetran, gitlab}}
+	assert.False(t, spec.IsPlan())
+	assert.True(t, spec.AppliesChanges(fivetran))
+	assert.False(t, spec.AppliesChanges(gitlab))
+
+	spec.Mode = usernautdevv1alpha1.GroupModePlan
+	assert.True(t, spec.IsPlan())
+	assert.False(t, spec.AppliesChanges(fivetran))
+	assert.False(t, spec.AppliesChanges(gitlab))
+}
+
+func TestSetPlanned(t *testing.T) {
+	t.Parallel()
+
+	groupCR := &usernautdevv1alpha1.Group{}
+	groupCR.Generation = 2
+	groupCR.Status.LastAppliedGeneration = 1
+	groupCR.SetWaiting()
+	groupCR.SetPlanned()
+
+	require.Len(t, groupCR.Status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, groupCR.Status.Conditions[0].Status)
+	assert.Equal(t, usernautdevv1alpha1.Planned, groupCR.Status.Conditions[0].Reason)
+	assert.Equal(t, int64(1), groupCR.Status.LastAppliedGeneration)
+}
+
+func TestCleanupRemovedBackends_Plan(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store: store.New(inMemCache),
+		log:   logrus.NewEntry(logrus.New()),
+	}
+
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{GroupName: "team-a", Mode: usernautdevv1alpha1.GroupModePlan},
+	}
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "snowflake", "snowflake", "s-1"))
+
+	statuses, err := r.cleanupRemovedBackends(ctx, groupCR)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Status)
+	assert.Equal(t, "Removed from spec, cleanup is planned: team deleted", statuses[0].Message)
+
+	id, err := r.Store.Group.GetBackendID(ctx, "team-a", "snowflake", "snowflake")
+	require.NoError(t, err)
+	assert.Equal(t, "s-1", id)
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/constants"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+)
+
+func TestRemovalsExceedLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                           string
+		removals, teamSize             int
+		maxRemovals, maxRemovalPercent int
+		want                           bool
+	}{
+		{name: "no removals", removals: 0, teamSize: 10, maxRemovals: 1, maxRemovalPercent: 1, want: false},
+		{name: "limits disabled", removals: 100, teamSize: 100, want: false},
+		{name: "at the absolute limit", removals: 5, teamSize: 100, maxRemovals: 5, want: false},
+		{name: "above the absolute limit", removals: 6, teamSize: 100, maxRemovals: 5, want: true},
+		{name: "at the percentage limit", removals: 25, teamSize: 100, maxRemovalPercent: 25, want: false},
+		{name: "above the percentage limit", removals: 26, teamSize: 100, maxRemovalPercent: 25, want: true},
+		{name: "either limit", removals: 6, teamSize: 10, maxRemovals: 50, maxRemovalPercent: 50, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want,
+				removalsExceedLimits(tt.removals, tt.teamSize, tt.maxRemovals, tt.maxRemovalPercent))
+		})
+	}
+}
+
+func TestRemovalGuardLimits(t *testing.T) {
+	t.Parallel()
+
+	r := &GroupReconciler{AppConfig: &config.AppConfig{
+		ControllerConfig: config.ControllerConfig{
+			RemovalGuard: config.RemovalGuardConfig{MaxRemovals: 50, MaxRemovalPercent: 20},
+		},
+	}}
+
+	groupCR := &usernautdevv1alpha1.Group{}
+	maxRemovals, maxRemovalPercent := r.removalGuardLimits(groupCR)
+	assert.Equal(t, 50, maxRemovals)
+	assert.Equal(t, 20, maxRemovalPercent)
+
+	// each limit is overridden on its own, 0 disables it
+	disabled := int32(0)
+	groupCR.Spec.RemovalGuard = &usernautdevv1alpha1.RemovalGuard{MaxRemovalPercent: &disabled}
+	maxRemovals, maxRemovalPercent = r.removalGuardLimits(groupCR)
+	assert.Equal(t, 50, maxRemovals)
+	assert.Equal(t, 0, maxRemovalPercent)
+}
+
+func TestGuardRemovals(t *testing.T) {
+	t.Parallel()
+
+	r := &GroupReconciler{
+		backendLogger: logrus.NewEntry(logrus.New()),
+		AppConfig: &config.AppConfig{
+			ControllerConfig: config.ControllerConfig{
+				RemovalGuard: config.RemovalGuardConfig{MaxRemovals: 1},
+			},
+		},
+	}
+	backend := usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab"}
+	existing := map[string]*structs.User{
+		"1": {ID: "1", Email: "alice@example.com"},
+		"2": {ID: "2"},
+		"3": {ID: "3"},
+	}
+	groupCR := &usernautdevv1alpha1.Group{}
+
+	assert.Nil(t, r.guardRemovals(groupCR, backend, []string{"1"}, existing))
+
+	diff := r.guardRemovals(groupCR, backend, []string{"2", "1"}, existing)
+	require.NotNil(t, diff)
+	assert.Equal(t, []string{"2", "alice@example.com"}, diff.UsersToRemove)
+	assert.Len(t, diff.ApprovalToken, removalApprovalTokenLength)
+
+	// the token doesn't depend on the order of the removals, but on the removals themselves
+	assert.Equal(t, diff.ApprovalToken, removalApprovalToken("gitlab_gitlab", []string{"1", "2"}))
+	assert.NotEqual(t, diff.ApprovalToken, removalApprovalToken("gitlab_gitlab", []string{"1", "3"}))
+
+	groupCR.Annotations = map[string]string{constants.ApproveRemovalsAnnotation: "other, " + diff.ApprovalToken}
+	assert.Nil(t, r.guardRemovals(groupCR, backend, []string{"2", "1"}, existing))
+	assert.NotNil(t, r.guardRemovals(groupCR, backend, []string{"3", "1"}, existing))
+}
+
+func TestBlockedRemovalsMessage(t *testing.T) {
+	t.Parallel()
+
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{
+			Backends: []usernautdevv1alpha1.Backend{
+				{Name: "gitlab", Type: "gitlab"},
+				{Name: "rover", Type: "rover", Suspend: true},
+			},
+		},
+	}
+	pendingChanges := map[string]*usernautdevv1alpha1.MembershipDiff{
+		"rover_rover": {UsersToRemove: []string{"bob"}},
+	}
+	assert.Empty(t, blockedRemovalsMessage(groupCR, pendingChanges))
+
+	pendingChanges["gitlab_gitlab"] = &usernautdevv1alpha1.MembershipDiff{
+		UsersToRemove: []string{"alice", "bob"},
+		ApprovalToken: "abc",
+	}
+	message := blockedRemovalsMessage(groupCR, pendingChanges)
+	assert.Equal(t, "removal of 2 from gitlab/gitlab exceeds the removal guard, "+
+		"approve with annotation operator.dataverse.redhat.com/approve-removals=abc", message)
+
+	groupCR.SetRemovalBlocked(message)
+	require.Len(t, groupCR.Status.Conditions, 1)
+	assert.Equal(t, usernautdevv1alpha1.RemovalBlockedCondition, groupCR.Status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, groupCR.Status.Conditions[0].Status)
+
+	groupCR.ClearRemovalBlocked()
+	assert.Empty(t, groupCR.Status.Conditions)
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+// every group maps to the same snowflake role
+var withSharedSnowflakeRole = withPattern("snowflake", config.PatternEntry{Input: "^.*$", Output: "shared_role"})
+
+func TestAppliedGroupName(t *testing.T) {
+	t.Parallel()
+
+	groupCR := &usernautdevv1alpha1.Group{Spec: usernautdevv1alpha1.GroupSpec{GroupName: "new-team"}}
+	assert.Equal(t, "new-team", appliedGroupName(groupCR))
+
+	groupCR.Status.AppliedGroupName = "old-team"
+	assert.Equal(t, "old-team", appliedGroupName(groupCR))
+}
+
+func TestRenameGroup(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withSharedSnowflakeRole)
+
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "old-team", "snowflake", "snowflake", "shared_role"))
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "old-team", "fivetran", "fivetran", "f-1"))
+	require.NoError(t, r.Store.Group.SetMembers(ctx, "old-team", []string{"alice"}))
+	require.NoError(t, r.Store.UserGroups.AddGroup(ctx, "alice", "old-team"))
+
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{
+			GroupName: "new-team",
+			Backends: []usernautdevv1alpha1.Backend{
+				{Name: "snowflake", Type: "snowflake"},
+				{Name: "fivetran", Type: "fivetran"},
+			},
+		},
+		Status: usernautdevv1alpha1.GroupStatus{AppliedGroupName: "old-team"},
+	}
+
+	// the snowflake role name doesn't change and fivetran is not configured, so both keep their team
+	require.NoError(t, r.renameGroup(ctx, groupCR))
+
+	exists, err := r.Store.Group.Exists(ctx, "old-team")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	backends, err := r.Store.Group.GetBackends(ctx, "new-team")
+	require.NoError(t, err)
+	assert.Equal(t, "shared_role", backends["snowflake_snowflake"].ID)
+	assert.Equal(t, "f-1", backends["fivetran_fivetran"].ID)
+
+	groups, err := r.Store.UserGroups.GetGroups(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new-team"}, groups)
+}
+
+func TestRenameGroup_NotRenamed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withSharedSnowflakeRole)
+
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team", "fivetran", "fivetran", "f-1"))
+
+	for _, applied := range []string{"", "team"} {
+		groupCR := &usernautdevv1alpha1.Group{
+			Spec:   usernautdevv1alpha1.GroupSpec{GroupName: "team"},
+			Status: usernautdevv1alpha1.GroupStatus{AppliedGroupName: applied},
+		}
+		require.NoError(t, r.renameGroup(ctx, groupCR))
+
+		id, err := r.Store.Group.GetBackendID(ctx, "team", "fivetran", "fivetran")
+		require.NoError(t, err)
+		assert.Equal(t, "f-1", id)
+	}
+}
+
+func TestRenameGroup_NameTaken(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withSharedSnowflakeRole)
+
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "old-team", "fivetran", "fivetran", "f-1"))
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "new-team", "fivetran", "fivetran", "f-2"))
+	require.NoError(t, r.Store.Group.SetMembers(ctx, "new-team", []string{"bob"}))
+
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{
+			GroupName: "new-team",
+			Backends:  []usernautdevv1alpha1.Backend{{Name: "fivetran", Type: "fivetran"}},
+		},
+		Status: usernautdevv1alpha1.GroupStatus{AppliedGroupName: "old-team"},
+	}
+
+	// the records of the group already using the name are kept
+	err := r.renameGroup(ctx, groupCR)
+	assert.ErrorIs(t, err, store.ErrGroupExists)
+
+	data, err := r.Store.Group.Get(ctx, "new-team")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, data.Members)
+	assert.Equal(t, "f-2", data.Backends["fivetran_fivetran"].ID)
+	id, err := r.Store.Group.GetBackendID(ctx, "old-team", "fivetran", "fivetran")
+	require.NoError(t, err)
+	assert.Equal(t, "f-1", id)
+}
+
+func TestApplyGroupName_SuspendedRenamedThenUnsuspended(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withSharedSnowflakeRole)
+
+	// the first reconciliation of a group whose backend is suspended
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{
+			GroupName: "old-team",
+			Backends:  []usernautdevv1alpha1.Backend{{Name: "fivetran", Type: "fivetran", Suspend: true}},
+		},
+	}
+	seedAppliedGroupName(groupCR)
+	require.NoError(t, r.applyGroupName(ctx, groupCR))
+	assert.Equal(t, "old-team", groupCR.Status.AppliedGroupName)
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "old-team", "fivetran", "fivetran", "f-1"))
+
+	// the rename waits for the backend to be resumed
+	groupCR.Spec.GroupName = "new-team"
+	seedAppliedGroupName(groupCR)
+	require.NoError(t, r.applyGroupName(ctx, groupCR))
+	assert.Equal(t, "old-team", groupCR.Status.AppliedGroupName)
+	id, err := r.Store.Group.GetBackendID(ctx, "old-team", "fivetran", "fivetran")
+	require.NoError(t, err)
+	assert.Equal(t, "f-1", id)
+
+	groupCR.Spec.Backends[0].Suspend = false
+	seedAppliedGroupName(groupCR)
+	require.NoError(t, r.applyGroupName(ctx, groupCR))
+	assert.Equal(t, "new-team", groupCR.Status.AppliedGroupName)
+
+	exists, err := r.Store.Group.Exists(ctx, "old-team")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	id, err = r.Store.Group.GetBackendID(ctx, "new-team", "fivetran", "fivetran")
+	require.NoError(t, err)
+	assert.Equal(t, "f-1", id)
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+)
+
+func TestResyncInterval(t *testing.T) {
+	t.Parallel()
+
+	r := &GroupReconciler{
+		log:       logrus.NewEntry(logrus.New()),
+		AppConfig: &config.AppConfig{},
+	}
+	groupCR := &usernautdevv1alpha1.Group{}
+	assert.Equal(t, defaultResyncInterval, r.resyncInterval(groupCR))
+
+	r.AppConfig.ControllerConfig.ResyncInterval = "not-a-duration"
+	assert.Equal(t, defaultResyncInterval, r.resyncInterval(groupCR))
+
+	r.AppConfig.ControllerConfig.ResyncInterval = "2h"
+	assert.Equal(t, 2*time.Hour, r.resyncInterval(groupCR))
+
+	groupCR.Spec.ResyncInterval = &metav1.Duration{Duration: 15 * time.Minute}
+	assert.Equal(t, 15*time.Minute, r.resyncInterval(groupCR))
+}
+
+func TestWithJitter(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		interval := withJitter(time.Hour)
+		assert.GreaterOrEqual(t, interval, time.Hour)
+		assert.Less(t, interval, time.Hour+6*time.Minute)
+	}
+	assert.Equal(t, time.Duration(0), withJitter(0))
+}
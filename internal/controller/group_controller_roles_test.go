@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+func TestMemberRolesForBackend(t *testing.T) {
+	t.Parallel()
+
+	roles := []usernautdevv1alpha1.MemberRole{
+		{Backend: "gitlab", Role: "maintainer", Users: []string{"alice", "bob"}},
+		{Backend: "fivetran", Role: "Team Manager", Users: []string{"alice"}},
+	}
+
+	assert.Equal(t, map[string]string{"alice": "maintainer", "bob": "maintainer"}, memberRolesForBackend(roles, "gitlab"))
+	assert.Equal(t, map[string]string{"alice": "Team Manager"}, memberRolesForBackend(roles, "fivetran"))
+	assert.Empty(t, memberRolesForBackend(roles, "rover"))
+}
+
+func TestMemberRolesForGroup(t *testing.T) {
+	t.Parallel()
+
+	spec := &usernautdevv1alpha1.GroupSpec{
+		Owners: []string{"carol", "bob"},
+		Members: usernautdevv1alpha1.Members{
+			Roles: []usernautdevv1alpha1.MemberRole{
+				{Backend: "gitlab", Role: "maintainer", Users: []string{"alice", "bob"}},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"alice": "maintainer", "bob": "owner", "carol": "owner"},
+		memberRolesForGroup(spec, "gitlab"))
+	assert.Equal(t, map[string]string{"bob": "Team Manager", "carol": "Team Manager"},
+		memberRolesForGroup(spec, "fivetran"))
+	assert.Empty(t, memberRolesForGroup(spec, "snowflake"))
+}
+
+func TestProcessUsers_Roles(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store:         store.New(inMemCache),
+		backendLogger: logrus.NewEntry(logrus.New()),
+		allLdapUserData: map[string]*structs.LDAPUser{
+			"alice": {UID: "alice", Email: "alice@example.com"},
+			"bob":   {UID: "bob", Email: "bob@example.com"},
+			"carol": {UID: "carol", Email: "carol@example.com"},
+		},
+	}
+	for uid, id := range map[string]string{"alice": "1", "bob": "2", "carol": "3"} {
+		require.NoError(t, r.Store.User.SetBackend(ctx, uid, "gitlab_gitlab", id))
+	}
+
+	existing := map[string]*structs.User{
+		"1": {ID: "1", Role: "developer"},
+		"2": {ID: "2", Role: "developer"},
+		"9": {ID: "9", Role: "developer"},
+	}
+	memberRoles := map[string]string{"alice": "maintainer", "carol": "owner"}
+
+	changes, err := r.processUsers(ctx, []string{"alice", "bob", "carol"}, nil, existing, "gitlab", "gitlab", memberRoles)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{"owner": {"3"}}, changes.usersToAdd)
+	assert.Equal(t, []string{"9"}, changes.usersToRemove)
+	assert.Equal(t, map[string][]string{"maintainer": {"1"}}, changes.usersToUpdate)
+}
+
+func TestProcessUsers_BackendWithoutRoles(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store:         store.New(inMemCache),
+		backendLogger: logrus.NewEntry(logrus.New()),
+		allLdapUserData: map[string]*structs.LDAPUser{
+			"alice": {UID: "alice", Email: "alice@example.com"},
+		},
+	}
+	require.NoError(t, r.Store.User.SetBackend(ctx, "alice", "snowflake_snowflake", "alice"))
+
+	changes, err := r.processUsers(ctx, []string{"alice"}, nil, map[string]*structs.User{"alice": {ID: "alice"}},
+		"snowflake", "snowflake", nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, changes.usersToAdd)
+	assert.Empty(t, changes.usersToRemove)
+	assert.Empty(t, changes.usersToUpdate)
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	clientmocks "github.com/redhat-data-and-ai/usernaut/internal/controller/periodicjobs/mocks"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+)
+
+func TestEnsureServiceAccounts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withLdapUsers("alice"))
+
+	ctrl := gomock.NewController(t)
+	backendClient := clientmocks.NewMockClient(ctrl)
+
+	require.NoError(t, r.Store.ServiceAccount.SetBackend(ctx, "etl-bot", "snowflake_snowflake", "etl_bot"))
+
+	// only the service account without a backend user is created, without any LDAP lookup
+	backendClient.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, u *structs.User) (*structs.User, error) {
+			assert.True(t, u.IsServiceAccount())
+			assert.Equal(t, "ci-bot", u.UserName)
+			assert.Equal(t, "ci-bot@example.com", u.Email)
+			return &structs.User{ID: "ci_bot"}, nil
+		})
+
+	ids, err := r.ensureServiceAccounts(ctx, []usernautdevv1alpha1.ServiceAccount{
+		{Name: "etl-bot"},
+		{Name: "ci-bot", Email: "ci-bot@example.com"},
+	}, "snowflake", "snowflake", backendClient)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"etl-bot": "etl_bot", "ci-bot": "ci_bot"}, ids)
+
+	backends, err := r.Store.ServiceAccount.GetBackends(ctx, "ci-bot")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"snowflake_snowflake": "ci_bot"}, backends)
+
+	// service accounts are never stored as users, so the offboarding job doesn't see them
+	exists, err := r.Store.User.Exists(ctx, "ci-bot")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestProcessUsers_ServiceAccounts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withLdapUsers("alice"))
+	require.NoError(t, r.Store.User.SetBackend(ctx, "alice", "gitlab_gitlab", "1"))
+	// a service account that was created by usernaut and dropped from the spec since
+	require.NoError(t, r.Store.ServiceAccount.SetBackend(ctx, "old-bot", "gitlab_gitlab", "30"))
+
+	existing := map[string]*structs.User{
+		"1":  {ID: "1", Role: "developer"},
+		"20": {ID: "20", Role: "developer", ServiceAccount: true},
+		"30": {ID: "30", Role: "developer", ServiceAccount: true},
+	}
+	serviceAccountIDs := map[string]string{"ci-bot": "20", "etl-bot": "21"}
+
+	changes, err := r.processUsers(ctx, []string{"alice"}, serviceAccountIDs, existing, "gitlab", "gitlab", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{"developer": {"21"}}, changes.usersToAdd)
+	assert.Equal(t, []string{"30"}, changes.usersToRemove)
+	assert.Empty(t, changes.usersToUpdate)
+
+	// a service account without a user in the backend yet is reported as one to create
+	changes, err = r.processUsers(ctx, []string{"alice"}, map[string]string{"ci-bot": ""}, existing,
+		"gitlab", "gitlab", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci-bot"}, changes.usersToCreate)
+}
+
+func TestProcessUsers_KeepsUnmanagedServiceAccounts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withLdapUsers("alice"))
+	require.NoError(t, r.Store.User.SetBackend(ctx, "alice", "rover_rover", "alice"))
+	require.NoError(t, r.Store.ServiceAccount.SetBackend(ctx, "ci-bot", "rover_rover", "serviceaccount:ci-bot"))
+	require.NoError(t, r.Store.ServiceAccount.SetBackend(ctx, "old-bot", "rover_rover", "serviceaccount:old-bot"))
+
+	// the members of the rover group as reported by the backend, hand-added-bot was added outside of usernaut
+	existing := map[string]*structs.User{
+		"alice":                         {ID: "alice", Role: "member"},
+		"bob":                           {ID: "bob", Role: "member"},
+		"serviceaccount:ci-bot":         {ID: "serviceaccount:ci-bot", Role: "member", ServiceAccount: true},
+		"serviceaccount:old-bot":        {ID: "serviceaccount:old-bot", Role: "member", ServiceAccount: true},
+		"serviceaccount:hand-added-bot": {ID: "serviceaccount:hand-added-bot", Role: "member", ServiceAccount: true},
+	}
+	serviceAccountIDs := map[string]string{"ci-bot": "serviceaccount:ci-bot"}
+
+	changes, err := r.processUsers(ctx, []string{"alice"}, serviceAccountIDs, existing, "rover", "rover", nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes.usersToAdd)
+	assert.ElementsMatch(t, []string{"bob", "serviceaccount:old-bot"}, changes.usersToRemove)
+
+	// the next reconcile, once old-bot and bob were removed, leaves the team as it is
+	delete(existing, "bob")
+	delete(existing, "serviceaccount:old-bot")
+	changes, err = r.processUsers(ctx, []string{"alice"}, serviceAccountIDs, existing, "rover", "rover", nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes.usersToAdd)
+	assert.Empty(t, changes.usersToRemove)
+	assert.Contains(t, existing, "serviceaccount:hand-added-bot")
+}
+
+func TestMembershipDiff_ServiceAccounts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t, withLdapUsers("alice"))
+
+	existing := map[string]*structs.User{"20": {ID: "20", ServiceAccount: true}}
+	serviceAccountIDs := map[string]string{"ci-bot": "20", "etl-bot": ""}
+
+	changes, err := r.processUsers(ctx, nil, serviceAccountIDs, existing, "rover", "rover", nil)
+	require.NoError(t, err)
+	assert.Equal(t, &usernautdevv1alpha1.MembershipDiff{UsersToAdd: []string{"etl-bot"}},
+		membershipDiff(changes, existing))
+}
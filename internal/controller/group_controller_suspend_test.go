@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+func TestIsSuspended(t *testing.T) {
+	t.Parallel()
+
+	gitlab := usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab", Suspend: true}
+	fivetran := usernautdevv1alpha1.Backend{Name: "fivetran", Type: "fivetran"}
+
+	spec := usernautdevv1alpha1.GroupSpec{Backends: []usernautdevv1alpha1.Backend{fivetran}}
+	assert.False(t, spec.IsSuspended(fivetran))
+	assert.False(t, spec.HasSuspendedBackends())
+
+	spec.Backends = append(spec.Backends, gitlab)
+	assert.False(t, spec.IsSuspended(fivetran))
+	assert.True(t, spec.IsSuspended(gitlab))
+	assert.True(t, spec.HasSuspendedBackends())
+
+	spec.Suspend = true
+	assert.True(t, spec.IsSuspended(fivetran))
+}
+
+func TestMembershipDiff(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store:         store.New(inMemCache),
+		backendLogger: logrus.NewEntry(logrus.New()),
+		allLdapUserData: map[string]*structs.LDAPUser{
+			"alice": {UID: "alice", Email: "alice@example.com"},
+			"bob":   {UID: "bob", Email: "bob@example.com"},
+			"carol": {UID: "carol", Email: "carol@example.com"},
+		},
+	}
+	require.NoError(t, r.Store.User.SetBackend(ctx, "alice", "gitlab_gitlab", "1"))
+	require.NoError(t, r.Store.User.SetBackend(ctx, "bob", "gitlab_gitlab", "2"))
+
+	existing := map[string]*structs.User{
+		"1": {ID: "1", Role: "developer"},
+		"8": {ID: "8", Email: "dave@example.com", Role: "developer"},
+		"9": {ID: "9", Role: "developer"},
+	}
+	memberRoles := map[string]string{"alice": "maintainer"}
+
+	// carol has no gitlab user yet and ghost is not in LDAP, neither is an error while suspended
+	changes, err := r.processUsers(ctx, []string{"alice", "bob", "carol", "ghost"}, nil, existing,
+		"gitlab", "gitlab", memberRoles)
+	require.NoError(t, err)
+
+	assert.Equal(t, &usernautdevv1alpha1.MembershipDiff{
+		UsersToAdd:    []string{"bob", "carol"},
+		UsersToRemove: []string{"9", "dave@example.com"},
+		UsersToUpdate: []string{"alice"},
+	}, membershipDiff(changes, existing))
+
+	// nothing is created while computing the diff
+	backends, err := r.Store.User.GetBackends(ctx, "carol")
+	require.NoError(t, err)
+	assert.Empty(t, backends)
+}
+
+func TestLookupTeamID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store: store.New(inMemCache),
+		AppConfig: &config.AppConfig{
+			Pattern: map[string][]config.PatternEntry{"default": {{Input: "^(.*)$", Output: "$1"}}},
+		},
+	}
+
+	fivetran := &structs.BackendParams{Name: "fivetran", Type: "fivetran"}
+	id, err := r.lookupTeamID(ctx, "team-a", fivetran)
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	require.NoError(t, r.Store.Team.SetBackend(ctx, "team-a", "fivetran_fivetran", "t-1"))
+	id, err = r.lookupTeamID(ctx, "team-a", fivetran)
+	require.NoError(t, err)
+	assert.Equal(t, "t-1", id)
+
+	// the lookup doesn't adopt the team in the GroupStore
+	exists, err := r.Store.Group.Exists(ctx, "team-a")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "fivetran", "fivetran", "g-1"))
+	id, err = r.lookupTeamID(ctx, "team-a", fivetran)
+	require.NoError(t, err)
+	assert.Equal(t, "g-1", id)
+}
+
+func TestCleanupRemovedBackends_Suspended(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store: store.New(inMemCache),
+		log:   logrus.NewEntry(logrus.New()),
+	}
+
+	groupCR := &usernautdevv1alpha1.Group{
+		Spec: usernautdevv1alpha1.GroupSpec{GroupName: "team-a", Suspend: true},
+	}
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "snowflake", "snowflake", "s-1"))
+
+	statuses, err := r.cleanupRemovedBackends(ctx, groupCR)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Status)
+	assert.Equal(t, "Removed from spec, cleanup is suspended", statuses[0].Message)
+
+	id, err := r.Store.Group.GetBackendID(ctx, "team-a", "snowflake", "snowflake")
+	require.NoError(t, err)
+	assert.Equal(t, "s-1", id)
+}
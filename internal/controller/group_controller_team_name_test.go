@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "github.com/redhat-data-and-ai/usernaut/internal/controller/periodicjobs/mocks"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+)
+
+func TestFetchOrCreateTeam_AdoptsTeamName(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t)
+
+	ctrl := gomock.NewController(t)
+	backendClient := clientmocks.NewMockClient(ctrl)
+
+	require.NoError(t, r.Store.Group.SetBackend(ctx, "team-a", "fivetran", "fivetran", "f-1"))
+	require.NoError(t, r.Store.Team.SetBackend(ctx, "legacy-team", "fivetran_fivetran", "f-9"))
+
+	// without an override the team of the GroupStore is kept
+	params := &structs.BackendParams{Name: "fivetran", Type: "fivetran"}
+	teamID, created, err := r.fetchOrCreateTeam(ctx, "team-a", backendClient, params)
+	require.NoError(t, err)
+	assert.Equal(t, "f-1", teamID)
+	assert.False(t, created)
+
+	// the override adopts the existing team with that name
+	params.TeamName = "legacy-team"
+	teamID, err = r.lookupTeamID(ctx, "team-a", params)
+	require.NoError(t, err)
+	assert.Equal(t, "f-9", teamID)
+
+	teamID, created, err = r.fetchOrCreateTeam(ctx, "team-a", backendClient, params)
+	require.NoError(t, err)
+	assert.Equal(t, "f-9", teamID)
+	assert.False(t, created)
+
+	id, err := r.Store.Group.GetBackendID(ctx, "team-a", "fivetran", "fivetran")
+	require.NoError(t, err)
+	assert.Equal(t, "f-9", id)
+}
+
+func TestFetchOrCreateTeam_CreatesTeamName(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestReconciler(t)
+
+	ctrl := gomock.NewController(t)
+	backendClient := clientmocks.NewMockClient(ctrl)
+
+	backendClient.EXPECT().CreateTeam(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, team *structs.Team) (*structs.Team, error) {
+			assert.Equal(t, "custom-team", team.Name)
+			return &structs.Team{ID: "f-2", Name: team.Name}, nil
+		})
+
+	params := &structs.BackendParams{Name: "fivetran", Type: "fivetran", TeamName: "custom-team"}
+	teamID, created, err := r.fetchOrCreateTeam(ctx, "team-a", backendClient, params)
+	require.NoError(t, err)
+	assert.Equal(t, "f-2", teamID)
+	assert.True(t, created)
+
+	// once created, the team is found in the GroupStore
+	teamID, created, err = r.fetchOrCreateTeam(ctx, "team-a", backendClient, params)
+	require.NoError(t, err)
+	assert.Equal(t, "f-2", teamID)
+	assert.False(t, created)
+}
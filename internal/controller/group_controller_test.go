@@ -20,7 +20,6 @@ import (
 	"context"
 	"os"
 	"path/filepath"
-	"sync"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -37,6 +36,7 @@ import (
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
 	"github.com/redhat-data-and-ai/usernaut/pkg/store"
 )
 
@@ -91,12 +91,12 @@ var _ = Describe("Group Controller", func() {
 		ldapClient := mocks.NewMockLDAPClient(ctrl)
 
 		return &GroupReconciler{
-			Client:     k8sClient,
-			Scheme:     k8sClient.Scheme(),
-			AppConfig:  appConfig,
-			Store:      store.New(Cache),
-			LdapConn:   ldapClient,
-			CacheMutex: &sync.RWMutex{},
+			Client:    k8sClient,
+			Scheme:    k8sClient.Scheme(),
+			AppConfig: appConfig,
+			Store:     store.New(Cache),
+			LdapConn:  ldapClient,
+			Locker:    locker.NewMemoryLocker(),
 		}, ldapClient
 	}
 
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+)
+
+func TestTimeBoundMembers_ActiveUsers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		mt := metav1.NewTime(now.Add(d))
+		return &mt
+	}
+
+	groupCR := &usernautdevv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "incident-response"},
+		Spec: usernautdevv1alpha1.GroupSpec{
+			Members: usernautdevv1alpha1.Members{
+				TimeBoundUsers: []usernautdevv1alpha1.TimeBoundUser{
+					{UID: "open-ended"},
+					{UID: "expiring", ExpiresAt: at(48 * time.Hour)},
+					{UID: "expiring-soon", NotBefore: at(-time.Hour), ExpiresAt: at(2 * time.Hour)},
+					{UID: "expired", ExpiresAt: at(-time.Minute)},
+					{UID: "not-started", NotBefore: at(30 * time.Minute)},
+				},
+			},
+		},
+	}
+
+	tb := &timeBoundMembers{now: now}
+	assert.Equal(t, []string{"open-ended", "expiring", "expiring-soon"}, tb.activeUsers(groupCR))
+	assert.Equal(t, now.Add(30*time.Minute), tb.nextBoundary)
+	assert.Equal(t, 30*time.Minute+time.Second, tb.requeueAfter(defaultResyncInterval))
+
+	expiries := tb.sortedExpiries()
+	if assert.Len(t, expiries, 2) {
+		assert.Equal(t, "expiring-soon", expiries[0].User)
+		assert.Equal(t, "incident-response", expiries[0].Group)
+		assert.Equal(t, "expiring", expiries[1].User)
+	}
+}
+
+func TestTimeBoundMembers_RequeueAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	assert.Equal(t, time.Hour, (&timeBoundMembers{now: now}).requeueAfter(time.Hour))
+	assert.Equal(t, time.Hour, (&timeBoundMembers{now: now, nextBoundary: now.Add(30 * 24 * time.Hour)}).requeueAfter(time.Hour))
+	assert.Equal(t, 5*time.Minute+time.Second,
+		(&timeBoundMembers{now: now, nextBoundary: now.Add(5 * time.Minute)}).requeueAfter(time.Hour))
+}
+
+func TestTimeBoundMembers_SortedExpiriesDeduplicatesNestedGroups(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+	tb := &timeBoundMembers{upcomingExpiries: []usernautdevv1alpha1.MemberExpiry{
+		{User: "alice", Group: "child", ExpiresAt: expiresAt},
+		{User: "alice", Group: "child", ExpiresAt: expiresAt},
+	}}
+
+	assert.Len(t, tb.sortedExpiries(), 1)
+}
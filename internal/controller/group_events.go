@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+)
+
+// Reasons of the events recorded on the Group CRs
+const (
+	eventReasonTeamCreated           = "TeamCreated"
+	eventReasonMembersAdded          = "MembersAdded"
+	eventReasonMembersRemoved        = "MembersRemoved"
+	eventReasonMemberRolesUpdated    = "MemberRolesUpdated"
+	eventReasonTeamsNested           = "TeamsNested"
+	eventReasonTeamsUnnested         = "TeamsUnnested"
+	eventReasonGroupParamsReconciled = "GroupParamsReconciled"
+	eventReasonLDAPUsersNotFound     = "LDAPUsersNotFound"
+	eventReasonBackendFailed         = "BackendFailed"
+)
+
+// maxEventUsers is the maximum number of users listed in an event message, events are meant
+// for humans and their message is truncated by the API server anyway
+const maxEventUsers = 10
+
+// recordEvent records an event on the group CR, it is a no-op when the reconciler has no recorder
+func (r *GroupReconciler) recordEvent(groupCR *usernautdevv1alpha1.Group, eventType, reason, messageFmt string,
+	args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(groupCR, eventType, reason, messageFmt, args...)
+}
+
+// recordBackendEvent records a normal event counting the changes applied to the team of a backend,
+// e.g. "3 members added on gitlab/gitlab"
+func (r *GroupReconciler) recordBackendEvent(groupCR *usernautdevv1alpha1.Group,
+	backend usernautdevv1alpha1.Backend, reason, changes string, count int) {
+	if count == 0 {
+		return
+	}
+	r.recordEvent(groupCR, corev1.EventTypeNormal, reason, "%d %s on %s/%s", count, changes, backend.Type, backend.Name)
+}
+
+// withRole appends the role to the description of the changes, backends without roles use an empty role
+func withRole(changes, role string) string {
+	if role == "" {
+		return changes
+	}
+	return changes + " with role " + role
+}
+
+// summarizeUsers lists the first maxEventUsers users, followed by the number of users left out
+func summarizeUsers(users []string) string {
+	if len(users) <= maxEventUsers {
+		return strings.Join(users, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(users[:maxEventUsers], ", "), len(users)-maxEventUsers)
+}
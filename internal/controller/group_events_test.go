@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+)
+
+func TestSummarizeUsers(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "alice, bob", summarizeUsers([]string{"alice", "bob"}))
+
+	users := make([]string, 0, maxEventUsers+2)
+	for i := 0; i < maxEventUsers+2; i++ {
+		users = append(users, "u"+string(rune('a'+i)))
+	}
+	assert.Equal(t, "ua, ub, uc, ud, ue, uf, ug, uh, ui, uj and 2 more", summarizeUsers(users))
+}
+
+func TestRecordBackendEvent(t *testing.T) {
+	t.Parallel()
+
+	recorder := record.NewFakeRecorder(10)
+	r := &GroupReconciler{Recorder: recorder}
+	groupCR := &usernautdevv1alpha1.Group{}
+	gitlab := usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab"}
+
+	// nothing to report without changes
+	r.recordBackendEvent(groupCR, gitlab, eventReasonMembersRemoved, "members removed", 0)
+	r.recordBackendEvent(groupCR, gitlab, eventReasonMembersAdded, withRole("members added", "developer"), 3)
+	r.recordEvent(groupCR, corev1.EventTypeWarning, eventReasonBackendFailed, "failed to reconcile %s", "gitlab/gitlab")
+
+	assert.Len(t, recorder.Events, 2)
+	assert.Equal(t, "Normal MembersAdded 3 members added with role developer on gitlab/gitlab", <-recorder.Events)
+	assert.Equal(t, "Warning BackendFailed failed to reconcile gitlab/gitlab", <-recorder.Events)
+
+	// a reconciler without recorder doesn't record events
+	(&GroupReconciler{}).recordBackendEvent(groupCR, gitlab, eventReasonMembersAdded, "members added", 1)
+}
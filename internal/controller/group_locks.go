@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
+)
+
+// The cache records are protected by two kinds of locks:
+//   - the group lock, held for the whole reconciliation or deletion of a group so that the Group CRs
+//     sharing a group_name, or reconciled by different replicas, don't update its teams concurrently
+//   - the user and service account locks, held only while their records are updated. They are leaf
+//     locks: nothing else is locked while holding them, so they can't deadlock with the group locks.
+//   - the teams lock of a backend, held while a team of the backend is renamed or released so that the
+//     cache preload of another replica doesn't store the team as it was before. It is a leaf lock too.
+
+// userLockBatchSize bounds the number of users locked at once by withUserBatchesLocked
+const userLockBatchSize = 100
+
+// lockGroup locks the group of the CR under both its applied and its desired group name, so that
+// a rename doesn't race with a Group CR that already uses the new name
+func (r *GroupReconciler) lockGroup(ctx context.Context, groupCR *usernautdevv1alpha1.Group) (*locker.Lease, error) {
+	lease, err := r.Locker.Lock(ctx,
+		locker.GroupKey(appliedGroupName(groupCR)), locker.GroupKey(groupCR.Spec.GroupName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock group %s: %w", groupCR.Spec.GroupName, err)
+	}
+	return lease, nil
+}
+
+// unlock releases the locks of the lease, a failed release is only logged as the locks expire anyway
+func (r *GroupReconciler) unlock(lease *locker.Lease) {
+	if err := lease.Unlock(); err != nil {
+		r.log.WithError(err).Warn("error releasing locks")
+	}
+}
+
+// withUsersLocked runs fn while holding the locks of all the users at once
+func (r *GroupReconciler) withUsersLocked(ctx context.Context, uids []string,
+	fn func(ctx context.Context) error) error {
+	if len(uids) == 0 {
+		return fn(ctx)
+	}
+	keys := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		keys = append(keys, locker.UserKey(uid))
+	}
+	return locker.Do(ctx, r.Locker, keys, fn)
+}
+
+// withUserBatchesLocked runs fn on the sorted users in batches of at most userLockBatchSize, holding
+// the locks of the users of the current batch only. A large group then neither waits for all its
+// members to be free at the same time nor blocks every group sharing one of them until it is done.
+func (r *GroupReconciler) withUserBatchesLocked(ctx context.Context, uids []string,
+	fn func(ctx context.Context, batch []string) error) error {
+	for batch := range slices.Chunk(slices.Compact(slices.Sorted(slices.Values(uids))), userLockBatchSize) {
+		err := r.withUsersLocked(ctx, batch, func(ctx context.Context) error {
+			return fn(ctx, batch)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withTeamsLocked runs fn while holding the teams lock of the backend
+func (r *GroupReconciler) withTeamsLocked(ctx context.Context, backend usernautdevv1alpha1.Backend,
+	fn func(ctx context.Context) error) error {
+	return locker.Do(ctx, r.Locker, []string{locker.TeamsKey(backend.Name + "_" + backend.Type)}, fn)
+}
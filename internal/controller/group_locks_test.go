@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	clientmocks "github.com/redhat-data-and-ai/usernaut/internal/controller/periodicjobs/mocks"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+func TestCreateUsersInBackendAndCache_Concurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	r := &GroupReconciler{
+		Store:         store.New(inMemCache),
+		Locker:        locker.NewMemoryLocker(),
+		backendLogger: logrus.NewEntry(logrus.New()),
+		allLdapUserData: map[string]*structs.LDAPUser{
+			"alice": {UID: "alice", Email: "alice@example.com"},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	backendClient := clientmocks.NewMockClient(ctrl)
+	// alice is a member of both groups, but is created only once
+	backendClient.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&structs.User{ID: "1"}, nil).Times(1)
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := *r
+			assert.NoError(t, worker.createUsersInBackendAndCache(ctx, []string{"alice"}, "gitlab", "gitlab", backendClient))
+		}()
+	}
+	wg.Wait()
+
+	backends, err := r.Store.User.GetBackends(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gitlab_gitlab": "1"}, backends)
+
+	owner, err := r.Store.User.GetByBackendID(ctx, "gitlab_gitlab", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+}
+
+func TestLockGroup(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := &GroupReconciler{Locker: locker.NewMemoryLocker()}
+
+	groupCR := &usernautdevv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec:       usernautdevv1alpha1.GroupSpec{GroupName: "team-b"},
+		Status:     usernautdevv1alpha1.GroupStatus{AppliedGroupName: "team-a"},
+	}
+	lease, err := r.lockGroup(ctx, groupCR)
+	require.NoError(t, err)
+
+	// a group being renamed holds both its old and its new name
+	for _, name := range []string{"team-a", "team-b"} {
+		waitCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := r.Locker.Lock(waitCtx, locker.GroupKey(name))
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	require.NoError(t, lease.Unlock())
+
+	other, err := r.Locker.Lock(ctx, locker.GroupKey("team-a"), locker.GroupKey("team-b"))
+	require.NoError(t, err)
+	require.NoError(t, other.Unlock())
+}
+
+func TestWithUserBatchesLocked(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := &GroupReconciler{Locker: locker.NewMemoryLocker()}
+
+	uids := make([]string, 0, 2*userLockBatchSize+userLockBatchSize/2+1)
+	for i := range 2*userLockBatchSize + userLockBatchSize/2 {
+		uids = append(uids, fmt.Sprintf("user-%03d", i))
+	}
+	// duplicates are locked once
+	uids = append(uids, "user-000")
+
+	var batches [][]string
+	err := r.withUserBatchesLocked(ctx, uids, func(ctx context.Context, batch []string) error {
+		batches = append(batches, batch)
+		// only the users of the current batch are locked
+		lockedCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := r.Locker.Lock(lockedCtx, locker.UserKey(batch[0]))
+		require.ErrorIs(t, err, context.Canceled)
+		if last := uids[len(uids)-2]; !slices.Contains(batch, last) {
+			lease, err := r.Locker.Lock(ctx, locker.UserKey(last))
+			require.NoError(t, err)
+			require.NoError(t, lease.Unlock())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], userLockBatchSize)
+	assert.Len(t, batches[1], userLockBatchSize)
+	assert.Len(t, batches[2], userLockBatchSize/2)
+	assert.True(t, slices.IsSorted(slices.Concat(batches...)))
+
+	// an error stops the remaining batches
+	calls := 0
+	failure := errors.New("boom")
+	err = r.withUserBatchesLocked(ctx, uids, func(context.Context, []string) error {
+		calls++
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, calls)
+}
+
+func TestReleaseBackendTeam_WaitsForTeamsLock(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := &GroupReconciler{Locker: locker.NewMemoryLocker()}
+	backend := usernautdevv1alpha1.Backend{Name: "fivetran", Type: "fivetran"}
+
+	// the preload of another replica is storing the teams of the backend
+	lease, err := r.Locker.Lock(ctx, locker.TeamsKey("fivetran_fivetran"))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, lease.Unlock()) }()
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = r.releaseBackendTeam(waitCtx, logrus.NewEntry(logrus.New()), "team-a", backend,
+		usernautdevv1alpha1.DeletionPolicyRetain)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -59,8 +59,8 @@ var _ = Describe("Group spec.members validation", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid Group (CEL/members): %v", err)
 		msg := err.Error()
-		Expect(strings.Contains(msg, "users must be a non-empty list when ldap_query is omitted") ||
-			strings.Contains(msg, "ldap_query is omitted")).To(BeTrue(), "unexpected error: %v", err)
+		Expect(strings.Contains(msg, "users or time_bound_users must be a non-empty list")).
+			To(BeTrue(), "unexpected error: %v", err)
 	}
 
 	It("rejects members with no ldap_query and empty users list", func() {
@@ -146,6 +146,21 @@ func (mr *MockLDAPClientMockRecorder) GetBulkUserLDAPData(ctx, userIDs interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkUserLDAPData", reflect.TypeOf((*MockLDAPClient)(nil).GetBulkUserLDAPData), ctx, userIDs)
 }
 
+// GetGroupMembers mocks base method.
+func (m *MockLDAPClient) GetGroupMembers(ctx context.Context, groupDN string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupMembers", ctx, groupDN)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupMembers indicates an expected call of GetGroupMembers.
+func (mr *MockLDAPClientMockRecorder) GetGroupMembers(ctx, groupDN interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembers", reflect.TypeOf((*MockLDAPClient)(nil).GetGroupMembers), ctx, groupDN)
+}
+
 // GetQueryMembers mocks base method.
 func (m *MockLDAPClient) GetQueryMembers(ctx context.Context, query string) ([]string, error) {
 	m.ctrl.T.Helper()
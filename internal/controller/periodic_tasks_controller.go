@@ -3,13 +3,13 @@ package controller
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/redhat-data-and-ai/usernaut/internal/controller/periodicjobs"
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
 	"github.com/redhat-data-and-ai/usernaut/pkg/store"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -31,7 +31,7 @@ type PeriodicTasksReconciler struct {
 
 func NewPeriodicTasksReconciler(
 	k8sClient client.Client,
-	sharedCacheMutex *sync.RWMutex,
+	sharedLocker locker.Locker,
 	cacheClient cache.Cache,
 	dataStore *store.Store,
 	ldapClient ldap.LDAPClient,
@@ -41,7 +41,7 @@ func NewPeriodicTasksReconciler(
 
 	// Add jobs to the periodic task manager
 	userOffboardingJob := periodicjobs.NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		ldapClient,
 		backendClients,
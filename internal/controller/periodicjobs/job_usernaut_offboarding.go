@@ -24,14 +24,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients"
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
 	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 	"github.com/redhat-data-and-ai/usernaut/pkg/store"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/types"
@@ -69,11 +70,9 @@ type UserOffboardingJob struct {
 	// mapped by their unique identifier "{name}_{type}".
 	backendClients map[string]clients.Client
 
-	// cacheMutex prevents concurrent access to the cache during user offboarding operations.
-	// This shared mutex ensures that the GroupReconciler and UserOffboardingJob don't interfere
-	// with each other when reading or modifying user data in Redis.
-	// This mutex is shared across components and passed from main.go.
-	cacheMutex *sync.RWMutex
+	// locker locks each user while it is offboarded so that the GroupReconciler doesn't
+	// create or update it in the meantime. It is shared with the GroupReconciler.
+	locker locker.Locker
 
 	// exclusionList contains email addresses that should be excluded from offboarding
 	// Using a map for O(1) lookup performance instead of O(n) slice iteration
@@ -92,7 +91,7 @@ type UserOffboardingJob struct {
 //   - Returns a fully configured job ready for execution
 //
 // Parameters:
-//   - sharedCacheMutex: Shared mutex to prevent race conditions with other components
+//   - sharedLocker: Locker shared with the GroupReconciler to lock the users being offboarded
 //   - dataStore: Shared store instance with prefixed keys
 //   - ldapClient: Shared LDAP client instance
 //   - backendClients: Map of initialized backend clients
@@ -100,7 +99,7 @@ type UserOffboardingJob struct {
 // Returns:
 //   - *UserOffboardingJob: A configured job instance
 func NewUserOffboardingJob(
-	sharedCacheMutex *sync.RWMutex,
+	sharedLocker locker.Locker,
 	dataStore *store.Store,
 	ldapClient ldap.LDAPClient,
 	backendClients map[string]clients.Client,
@@ -109,7 +108,7 @@ func NewUserOffboardingJob(
 		store:          dataStore,
 		ldapClient:     ldapClient,
 		backendClients: backendClients,
-		cacheMutex:     sharedCacheMutex,
+		locker:         sharedLocker,
 		exclusionList:  make(map[string]bool),
 	}
 }
@@ -254,11 +253,13 @@ func (uoj *UserOffboardingJob) Run(ctx context.Context) error {
 	}
 
 	uoj.logger.WithField("count", len(userKeys)).Info("Found users in cache")
+	metrics.StoreEntries.WithLabelValues("user").Set(float64(len(userKeys)))
 
 	result := uoj.processUsers(ctx, userKeys)
 
 	// Log comprehensive job summary
 	uoj.logJobSummary(result, len(userKeys))
+	recordJobMetrics(result)
 
 	if len(result.errors) > 0 {
 		return fmt.Errorf("user offboarding completed with %d errors: %v", len(result.errors), result.errors)
@@ -296,18 +297,32 @@ func (uoj *UserOffboardingJob) processUsers(ctx context.Context, userKeys []stri
 	var result processingResult
 
 	for _, userKey := range userKeys {
-		normalizedKey := strings.ToLower(strings.TrimSpace(userKey))
-		if normalizedKey == "" {
+		if strings.TrimSpace(userKey) == "" {
 			uoj.logger.WithField("userKey", userKey).Info("Skipping user: userKey is empty")
 			continue
-		} else if uoj.isInExclusionList(normalizedKey) {
+		}
+
+		// The users are keyed by their uid, or by their email until it is linked to their uid. A user
+		// without an email in LDAP has no identity record but is still keyed by its uid
+		userEmail, err := uoj.store.User.GetEmail(ctx, userKey)
+		if err != nil {
+			result.errors = append(result.errors, fmt.Sprintf("failed to get email of user %s: %v", userKey, err))
+			continue
+		}
+		identified := userEmail != "" || !strings.Contains(userKey, "@")
+		if userEmail == "" {
+			userEmail = userKey
+		}
+
+		normalizedKey := strings.ToLower(strings.TrimSpace(userEmail))
+		if uoj.isInExclusionList(normalizedKey) {
 			result.excludedCount++
 			uoj.logger.WithField("userKey", userKey).Info("Excluding user from offboarding")
 			continue
 		}
 
 		uoj.logger.WithField("userKey", userKey).Debug("Processing user")
-		offboarded, err := uoj.processUser(ctx, userKey)
+		offboarded, err := uoj.processUser(ctx, userKey, identified)
 		if err != nil {
 			result.errors = append(result.errors, err.Error())
 		} else if offboarded {
@@ -319,6 +334,17 @@ func (uoj *UserOffboardingJob) processUsers(ctx context.Context, userKeys []stri
 	return result
 }
 
+// recordJobMetrics records the results of a job execution in the offboarding metrics.
+//
+// Parameters:
+//   - result: Processing results containing counts and lists of processed users
+func recordJobMetrics(result processingResult) {
+	metrics.OffboardingUsers.WithLabelValues(metrics.ResultOffboarded).Add(float64(result.offboardedCount))
+	metrics.OffboardingUsers.WithLabelValues(metrics.ResultExcluded).Add(float64(result.excludedCount))
+	metrics.OffboardingUsers.WithLabelValues(metrics.ResultError).Add(float64(len(result.errors)))
+	metrics.OffboardingLastRun.SetToCurrentTime()
+}
+
 // isInExclusionList checks if a normalized email address is in the exclusion list.
 // Uses map lookup for O(1) performance instead of O(n) slice iteration.
 //
@@ -341,13 +367,13 @@ func (uoj *UserOffboardingJob) isInExclusionList(normalizedKey string) bool {
 // Parameters:
 //   - ctx: Context for cancellation and logging
 //   - userKey: The Redis key for this user
-//   - userID: The extracted user identifier
+//   - identified: Whether the user key is a uid rather than an email
 //
 // Returns:
 //   - bool: true if user was offboarded, false if user is still active
 //   - error: Any error encountered during user processing, nil if successful
-func (uoj *UserOffboardingJob) processUser(ctx context.Context, userKey string) (bool, error) {
-	isActive, err := uoj.isUserActiveInLDAP(ctx, userKey)
+func (uoj *UserOffboardingJob) processUser(ctx context.Context, userKey string, identified bool) (bool, error) {
+	isActive, err := uoj.isUserActiveInLDAP(ctx, userKey, identified)
 	if err != nil {
 		uoj.logger.Error(err, "Failed to check LDAP status for user", "userKey", userKey)
 		return false, fmt.Errorf("failed to check LDAP for user %s: %v", userKey, err)
@@ -382,30 +408,33 @@ func (uoj *UserOffboardingJob) processUser(ctx context.Context, userKey string)
 // Returns:
 //   - error: Any error encountered during offboarding, nil if successful
 func (uoj *UserOffboardingJob) offboardUser(ctx context.Context, userKey string) error {
-	userData, userEmail, err := uoj.getUserDataFromCache(ctx, userKey)
-	if err != nil {
-		return fmt.Errorf("failed to get user data from cache: %w", err)
-	}
-	err = uoj.offboardUserFromAllBackends(ctx, userKey, userData)
-	if err != nil {
-		uoj.logger.WithField("userKey", userKey).Error(err, "Failed to offboard user from backends")
-		return fmt.Errorf("failed to offboard user %s from backends: %v", userKey, err)
-	}
+	// Lock the user for the whole offboarding to prevent concurrent modifications, its backends
+	// are read again as a group may have created it in a backend since the scan
+	return locker.Do(ctx, uoj.locker, []string{locker.UserKey(userKey)}, func(ctx context.Context) error {
+		uoj.logger.WithField("userKey", userKey).Info("Acquired user lock for offboarding")
 
-	// Lock cache before deletion operations to prevent concurrent modifications
-	uoj.cacheMutex.Lock()
-	defer uoj.cacheMutex.Unlock()
-
-	uoj.logger.WithField("userKey", userKey).Info("Acquired cache lock for user deletion operations")
+		userData, err := uoj.store.User.GetBackends(ctx, userKey)
+		if err != nil {
+			return fmt.Errorf("failed to get user data from cache: %w", err)
+		}
+		if len(userData) == 0 {
+			return fmt.Errorf("no user found in cache with key: %s", userKey)
+		}
+		err = uoj.offboardUserFromAllBackends(ctx, userKey, userData)
+		if err != nil {
+			uoj.logger.WithField("userKey", userKey).Error(err, "Failed to offboard user from backends")
+			return fmt.Errorf("failed to offboard user %s from backends: %v", userKey, err)
+		}
 
-	err = uoj.store.User.Delete(ctx, userEmail)
-	if err != nil {
-		uoj.logger.Error(err, "Failed to remove user from cache", "userKey", userKey, "userEmail", userEmail)
-		return fmt.Errorf("failed to remove user %s from cache: %v", userKey, err)
-	}
+		err = uoj.store.User.Delete(ctx, userKey)
+		if err != nil {
+			uoj.logger.Error(err, "Failed to remove user from cache", "userKey", userKey)
+			return fmt.Errorf("failed to remove user %s from cache: %v", userKey, err)
+		}
 
-	uoj.logger.WithField("userKey", userKey).Info("Successfully offboarded user")
-	return nil
+		uoj.logger.WithField("userKey", userKey).Info("Successfully offboarded user")
+		return nil
+	})
 }
 
 // logJobSummary logs a comprehensive summary of the offboarding job execution.
@@ -444,10 +473,6 @@ func (uoj *UserOffboardingJob) logJobSummary(result processingResult, totalUsers
 func (uoj *UserOffboardingJob) getUserListFromCache(ctx context.Context) ([]string, error) {
 	uoj.logger.Info("Scanning cache for user keys")
 
-	// Lock cache for read operation
-	uoj.cacheMutex.RLock()
-	defer uoj.cacheMutex.RUnlock()
-
 	userMap, err := uoj.store.User.GetByPattern(ctx, "*")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user list from cache: %w", err)
@@ -463,64 +488,34 @@ func (uoj *UserOffboardingJob) getUserListFromCache(ctx context.Context) ([]stri
 	return userKeys, nil
 }
 
-// getUserFromCache retrieves and deserializes user data from the cache.
-//
-// This method fetches the JSON representation of user data from cache
-// and unmarshals it into a User struct for processing. It supports both exact key
-// matching and pattern-based searching for email patterns.
-//
-// Parameters:
-//   - ctx: Context for cancellation and logging
-//   - userKey: The username to search for in cache (e.g., "subhatta" will match "subhatta@redhat.com")
-//
-// Returns:
-//   - map[string]string: The backend mappings for the user (backend_name_type -> user_id)
-//   - error: Any error encountered during retrieval or unmarshaling
-func (uoj *UserOffboardingJob) getUserDataFromCache(
-	ctx context.Context, userKey string,
-) (map[string]string, string, error) {
-	// Lock cache for read operation
-	uoj.cacheMutex.RLock()
-	defer uoj.cacheMutex.RUnlock()
-
-	// userKey is a username (e.g., "subhatta"), search for cache keys that contain this username
-	// We don't know the exact email, so we search broadly and then filter
-	// Pattern: *username* (e.g., "*subhatta*" matches emails like "subhatta@example.com")
-	usernamePattern := fmt.Sprintf("*%s*", userKey)
-
-	userDataList, err := uoj.store.User.GetByPattern(ctx, usernamePattern)
-	if err != nil {
-		return nil, "", err
-	}
-
-	// Search through all matching cache entries to find the user's backend mappings
-	for email, backendMap := range userDataList {
-		// Return the first valid match
-		return backendMap, email, nil
-	}
-
-	return nil, "", fmt.Errorf("No user found with username: %s", userKey)
-}
-
 // isUserActiveInLDAP verifies whether a user exists and is active in the LDAP directory.
 //
-// This method queries the LDAP directory for the specified user ID. If the user
-// is found, they are considered active. If the user is not found (ErrNoUserFound),
-// they are considered inactive and should be offboarded.
+// This method queries the LDAP directory for the specified user, by uid if the
+// user is identified by its uid and by email otherwise. If the user is found,
+// they are considered active. If the user is not found (ErrNoUserFound), they
+// are considered inactive and should be offboarded.
 //
 // Parameters:
 //   - ctx: Context for cancellation and logging
-//   - userEmail: The user identifier to check in LDAP
+//   - userKey: The uid or, for users not linked to their uid yet, the email to check in LDAP
+//   - identified: Whether the user key is a uid
 //
 // Returns:
 //   - bool: true if user is active in LDAP, false if inactive
 //   - error: Any LDAP query error (excluding ErrNoUserFound which indicates inactivity)
-func (uoj *UserOffboardingJob) isUserActiveInLDAP(ctx context.Context, userEmail string) (bool, error) {
-	userData, err := uoj.ldapClient.GetUserLDAPDataByEmail(ctx, userEmail)
+func (uoj *UserOffboardingJob) isUserActiveInLDAP(ctx context.Context, userKey string, identified bool) (bool, error) {
+	var userData map[string]interface{}
+	var err error
+	if identified {
+		userData, err = uoj.ldapClient.GetUserLDAPData(ctx, userKey)
+	} else {
+		userData, err = uoj.ldapClient.GetUserLDAPDataByEmail(ctx, userKey)
+	}
 	if err != nil {
 		if err == ldap.ErrNoUserFound {
 			// User not found in LDAP means they're inactive
-			uoj.logger.WithError(err).WithField("userEmail", userEmail).Debug("User not found in LDAP, treating as inactive")
+			uoj.logger.WithError(err).WithField("userKey", logger.MaskEmail(userKey)).
+				Debug("User not found in LDAP, treating as inactive")
 			return false, nil
 		}
 		return false, err
@@ -528,7 +523,7 @@ func (uoj *UserOffboardingJob) isUserActiveInLDAP(ctx context.Context, userEmail
 
 	// Check if userData is empty - treat as inactive user
 	if len(userData) == 0 {
-		uoj.logger.WithField("userEmail", logger.MaskEmail(userEmail)).Info("User data is empty, treating as inactive")
+		uoj.logger.WithField("userKey", logger.MaskEmail(userKey)).Info("User data is empty, treating as inactive")
 		return false, nil
 	}
 
@@ -595,6 +590,22 @@ func (uoj *UserOffboardingJob) offboardUserFromAllBackends(
 			continue
 		}
 
+		// Don't delete a backend user that was linked to another user since, e.g. a reused username
+		owner, err := uoj.store.User.GetByBackendID(ctx, backendKey, userIDStr)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("backend %s: %v", backendKey, err))
+			continue
+		}
+		if owner != "" && owner != userKey {
+			uoj.logger.WithFields(logrus.Fields{
+				"userKey":       userKey,
+				"backendUserID": userIDStr,
+				"backend":       backendKey,
+				"owner":         owner,
+			}).Warn("Backend user is owned by another user, skipping")
+			continue
+		}
+
 		// Proceed with offboarding for all other backends
 		uoj.logger.WithFields(logrus.Fields{
 			"userKey":       userKey,
@@ -603,7 +614,7 @@ func (uoj *UserOffboardingJob) offboardUserFromAllBackends(
 			"type":          backendType,
 		}).Info("Starting user offboarding from backend")
 
-		err := client.DeleteUser(ctx, userIDStr)
+		err = client.DeleteUser(ctx, userIDStr)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("backend %s: %v", backendKey, err))
 			uoj.logger.WithFields(logrus.Fields{
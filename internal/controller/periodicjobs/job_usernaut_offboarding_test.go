@@ -7,7 +7,6 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
-	"sync"
 	"testing"
 	"time"
 
@@ -22,6 +21,7 @@ import (
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
 	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
 	"github.com/redhat-data-and-ai/usernaut/pkg/store"
 )
 
@@ -121,9 +121,9 @@ func TestUserOffboardingJob(t *testing.T) {
 	}
 
 	// Create the job
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -217,9 +217,9 @@ func TestUserOffboardingJobBackendErrors(t *testing.T) {
 		"fivetran_fivetran": mockBackendClient,
 	}
 
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -247,6 +247,164 @@ func TestUserOffboardingJobBackendErrors(t *testing.T) {
 	})
 }
 
+// TestUserOffboardingJobBackendUserOwnership tests that only the backend users owned by the
+// offboarded user are deleted
+func TestUserOffboardingJobBackendUserOwnership(t *testing.T) {
+	defer setupTestConfig(t)()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLDAPClient := ldapmocks.NewMockLDAPClient(ctrl)
+	mockBackendClient := clientmocks.NewMockClient(ctrl)
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	dataStore := store.New(inMemCache)
+	ctx := context.Background()
+
+	// malice's email contains alice's, she must not be mistaken for alice
+	require.NoError(t, dataStore.User.SetBackend(ctx, "alice@example.com", "fivetran_fivetran", "alice_id"))
+	require.NoError(t, dataStore.User.SetBackend(ctx, "malice@example.com", "fivetran_fivetran", "malice_id"))
+	// a stale record of a user whose backend user was linked to bob since
+	require.NoError(t, dataStore.User.SetBackend(ctx, "bob@example.com", "fivetran_fivetran", "shared_id"))
+	require.NoError(t, inMemCache.HSet(ctx, "user:carol@example.com", map[string]string{"fivetran_fivetran": "shared_id"}))
+
+	job := NewUserOffboardingJob(
+		locker.NewMemoryLocker(),
+		dataStore,
+		mockLDAPClient,
+		map[string]clients.Client{"fivetran_fivetran": mockBackendClient},
+	)
+
+	mockLDAPClient.EXPECT().
+		GetUserLDAPDataByEmail(gomock.Any(), "alice@example.com").
+		Return(nil, ldap.ErrNoUserFound).
+		Times(1)
+	mockLDAPClient.EXPECT().
+		GetUserLDAPDataByEmail(gomock.Any(), "carol@example.com").
+		Return(nil, ldap.ErrNoUserFound).
+		Times(1)
+	mockLDAPClient.EXPECT().
+		GetUserLDAPDataByEmail(gomock.Any(), gomock.Any()).
+		Return(map[string]interface{}{"mail": "active@example.com"}, nil).
+		AnyTimes()
+	mockBackendClient.EXPECT().
+		DeleteUser(gomock.Any(), "alice_id").
+		Return(nil).
+		Times(1)
+
+	require.NoError(t, job.Run(ctx))
+
+	for email, want := range map[string]bool{
+		"alice@example.com":  false,
+		"carol@example.com":  false,
+		"malice@example.com": true,
+		"bob@example.com":    true,
+	} {
+		exists, err := dataStore.User.Exists(ctx, email)
+		require.NoError(t, err)
+		assert.Equal(t, want, exists, email)
+	}
+	owner, err := dataStore.User.GetByBackendID(ctx, "fivetran_fivetran", "shared_id")
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", owner)
+}
+
+// TestUserOffboardingJobIdentifiedUsers tests that the users keyed by uid are checked in LDAP by uid
+// and matched against the exclusion list by their linked email
+func TestUserOffboardingJobIdentifiedUsers(t *testing.T) {
+	defer setupTestConfig(t)()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLDAPClient := ldapmocks.NewMockLDAPClient(ctrl)
+	mockBackendClient := clientmocks.NewMockClient(ctrl)
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	dataStore := store.New(inMemCache)
+	ctx := context.Background()
+
+	for uid, id := range map[string]string{"alice": "alice_id", "bob": "bob_id", "excluded": "excluded_id"} {
+		require.NoError(t, dataStore.User.SetBackend(ctx, uid, "fivetran_fivetran", id))
+		require.NoError(t, dataStore.User.SetEmail(ctx, uid, uid+"@example.com"))
+	}
+
+	exclusionListFile := filepath.Join(os.Getenv("WORKDIR"), "appconfig", "test_offboard_user_exclusion_list.yaml")
+	require.NoError(t, os.WriteFile(exclusionListFile, []byte("exclusions:\n  - excluded@example.com\n"), 0644))
+
+	job := NewUserOffboardingJob(
+		locker.NewMemoryLocker(),
+		dataStore,
+		mockLDAPClient,
+		map[string]clients.Client{"fivetran_fivetran": mockBackendClient},
+	)
+
+	mockLDAPClient.EXPECT().
+		GetUserLDAPData(gomock.Any(), "alice").
+		Return(nil, ldap.ErrNoUserFound).
+		Times(1)
+	mockLDAPClient.EXPECT().
+		GetUserLDAPData(gomock.Any(), "bob").
+		Return(map[string]interface{}{"uid": "bob", "mail": "bob@example.com"}, nil).
+		Times(1)
+	mockBackendClient.EXPECT().
+		DeleteUser(gomock.Any(), "alice_id").
+		Return(nil).
+		Times(1)
+
+	require.NoError(t, job.Run(ctx))
+
+	for uid, want := range map[string]bool{"alice": false, "bob": true, "excluded": true} {
+		exists, err := dataStore.User.Exists(ctx, uid)
+		require.NoError(t, err)
+		assert.Equal(t, want, exists, uid)
+	}
+	uid, err := dataStore.User.GetUIDByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, uid)
+}
+
+// TestUserOffboardingJobUserWithoutEmail tests that a user without an email in LDAP, which is keyed
+// by its uid but has no identity record, is looked up by its uid and kept while active
+func TestUserOffboardingJobUserWithoutEmail(t *testing.T) {
+	defer setupTestConfig(t)()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLDAPClient := ldapmocks.NewMockLDAPClient(ctrl)
+	mockBackendClient := clientmocks.NewMockClient(ctrl)
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	dataStore := store.New(inMemCache)
+	ctx := context.Background()
+
+	require.NoError(t, dataStore.User.SetBackend(ctx, "carol", "fivetran_fivetran", "carol_id"))
+
+	job := NewUserOffboardingJob(
+		locker.NewMemoryLocker(),
+		dataStore,
+		mockLDAPClient,
+		map[string]clients.Client{"fivetran_fivetran": mockBackendClient},
+	)
+
+	mockLDAPClient.EXPECT().
+		GetUserLDAPData(gomock.Any(), "carol").
+		Return(map[string]interface{}{"uid": "carol"}, nil).
+		Times(1)
+	mockBackendClient.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+
+	require.NoError(t, job.Run(ctx))
+
+	exists, err := dataStore.User.Exists(ctx, "carol")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
 // TestUserOffboardingJobEmptyUserList tests handling of empty user list
 func TestUserOffboardingJobEmptyUserList(t *testing.T) {
 	defer setupTestConfig(t)()
@@ -270,9 +428,9 @@ func TestUserOffboardingJobEmptyUserList(t *testing.T) {
 
 	backendClients := map[string]clients.Client{}
 
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -322,9 +480,9 @@ func TestUserOffboardingJobMultipleBackends(t *testing.T) {
 		"snowflake_prod": mockSnowflakeClient,
 	}
 
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -376,10 +534,10 @@ func TestUserOffboardingJobInterval(t *testing.T) {
 
 	dataStore := store.New(inMemCache)
 	backendClients := map[string]clients.Client{}
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -446,17 +604,16 @@ func TestUserOffboardingJobEmptyUserKey(t *testing.T) {
 	// Add empty user key by directly setting an empty key in cache
 	// This simulates a blank userKey in the cache
 	// (key "user:" results in empty email after prefix removal)
-	backendData := `{"fivetran_fivetran":"empty_user_id"}`
-	err = inMemCache.Set(ctx, "user:", backendData, 0)
+	err = inMemCache.HSet(ctx, "user:", map[string]string{"fivetran_fivetran": "empty_user_id"})
 	require.NoError(t, err)
 
 	backendClients := map[string]clients.Client{
 		"fivetran_fivetran": mockBackendClient,
 	}
 
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -540,10 +697,10 @@ func TestUserOffboardingJobExclusionList(t *testing.T) {
 		"fivetran_fivetran": mockBackendClient,
 	}
 
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	// Create job after exclusion list file is created so it loads the exclusion list
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
@@ -659,10 +816,10 @@ offboardUserExclusionListConfigPath: "` + server.URL + `/config/exclusion_list.y
 		"fivetran_fivetran": mockBackendClient,
 	}
 
-	sharedCacheMutex := &sync.RWMutex{}
+	sharedLocker := locker.NewMemoryLocker()
 	// Create job after config is updated so it loads the exclusion list from URL
 	job := NewUserOffboardingJob(
-		sharedCacheMutex,
+		sharedLocker,
 		dataStore,
 		mockLDAPClient,
 		backendClients,
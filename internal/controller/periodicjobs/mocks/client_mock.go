@@ -36,17 +36,17 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // AddUserToTeam mocks base method.
-func (m *MockClient) AddUserToTeam(ctx context.Context, teamID string, userIDs []string) error {
+func (m *MockClient) AddUserToTeam(ctx context.Context, teamID, role string, userIDs []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUserToTeam", ctx, teamID, userIDs)
+	ret := m.ctrl.Call(m, "AddUserToTeam", ctx, teamID, role, userIDs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddUserToTeam indicates an expected call of AddUserToTeam.
-func (mr *MockClientMockRecorder) AddUserToTeam(ctx, teamID, userIDs interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) AddUserToTeam(ctx, teamID, role, userIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserToTeam", reflect.TypeOf((*MockClient)(nil).AddUserToTeam), ctx, teamID, userIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserToTeam", reflect.TypeOf((*MockClient)(nil).AddUserToTeam), ctx, teamID, role, userIDs)
 }
 
 // CreateTeam mocks base method.
@@ -210,3 +210,121 @@ func (mr *MockClientMockRecorder) RemoveUserFromTeam(ctx, teamID, userIDs interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromTeam", reflect.TypeOf((*MockClient)(nil).RemoveUserFromTeam), ctx, teamID, userIDs)
 }
+
+// UpdateUserRoleInTeam mocks base method.
+func (m *MockClient) UpdateUserRoleInTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRoleInTeam", ctx, teamID, role, userIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRoleInTeam indicates an expected call of UpdateUserRoleInTeam.
+func (mr *MockClientMockRecorder) UpdateUserRoleInTeam(ctx, teamID, role, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRoleInTeam", reflect.TypeOf((*MockClient)(nil).UpdateUserRoleInTeam), ctx, teamID, role, userIDs)
+}
+
+// MockTeamRenamer is a mock of TeamRenamer interface.
+type MockTeamRenamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamRenamerMockRecorder
+}
+
+// MockTeamRenamerMockRecorder is the mock recorder for MockTeamRenamer.
+type MockTeamRenamerMockRecorder struct {
+	mock *MockTeamRenamer
+}
+
+// NewMockTeamRenamer creates a new mock instance.
+func NewMockTeamRenamer(ctrl *gomock.Controller) *MockTeamRenamer {
+	mock := &MockTeamRenamer{ctrl: ctrl}
+	mock.recorder = &MockTeamRenamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamRenamer) EXPECT() *MockTeamRenamerMockRecorder {
+	return m.recorder
+}
+
+// RenameTeam mocks base method.
+func (m *MockTeamRenamer) RenameTeam(ctx context.Context, teamID, newName string) (*structs.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTeam", ctx, teamID, newName)
+	ret0, _ := ret[0].(*structs.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameTeam indicates an expected call of RenameTeam.
+func (mr *MockTeamRenamerMockRecorder) RenameTeam(ctx, teamID, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTeam", reflect.TypeOf((*MockTeamRenamer)(nil).RenameTeam), ctx, teamID, newName)
+}
+
+// MockTeamNester is a mock of TeamNester interface.
+type MockTeamNester struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamNesterMockRecorder
+}
+
+// MockTeamNesterMockRecorder is the mock recorder for MockTeamNester.
+type MockTeamNesterMockRecorder struct {
+	mock *MockTeamNester
+}
+
+// NewMockTeamNester creates a new mock instance.
+func NewMockTeamNester(ctrl *gomock.Controller) *MockTeamNester {
+	mock := &MockTeamNester{ctrl: ctrl}
+	mock.recorder = &MockTeamNesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamNester) EXPECT() *MockTeamNesterMockRecorder {
+	return m.recorder
+}
+
+// FetchNestedTeams mocks base method.
+func (m *MockTeamNester) FetchNestedTeams(ctx context.Context, teamID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchNestedTeams", ctx, teamID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchNestedTeams indicates an expected call of FetchNestedTeams.
+func (mr *MockTeamNesterMockRecorder) FetchNestedTeams(ctx, teamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNestedTeams", reflect.TypeOf((*MockTeamNester)(nil).FetchNestedTeams), ctx, teamID)
+}
+
+// NestTeams mocks base method.
+func (m *MockTeamNester) NestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NestTeams", ctx, teamID, nestedTeamIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NestTeams indicates an expected call of NestTeams.
+func (mr *MockTeamNesterMockRecorder) NestTeams(ctx, teamID, nestedTeamIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NestTeams", reflect.TypeOf((*MockTeamNester)(nil).NestTeams), ctx, teamID, nestedTeamIDs)
+}
+
+// UnnestTeams mocks base method.
+func (m *MockTeamNester) UnnestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnnestTeams", ctx, teamID, nestedTeamIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnnestTeams indicates an expected call of UnnestTeams.
+func (mr *MockTeamNesterMockRecorder) UnnestTeams(ctx, teamID, nestedTeamIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnnestTeams", reflect.TypeOf((*MockTeamNester)(nil).UnnestTeams), ctx, teamID, nestedTeamIDs)
+}
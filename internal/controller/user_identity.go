@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/structs"
+	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+)
+
+// linkUserIdentity keeps the identity record of a user in line with its LDAP data. The records of
+// the users are keyed by their uid and the email is only an attribute of the identity record, so
+// an email changed in LDAP is re-linked to the uid without touching the backend IDs of the user.
+// The records still keyed by the email of the user, e.g. a backend user found by the preload before
+// its uid was known, are moved to the uid.
+// NOTE: Caller must hold the group lock, the user is locked while its records are updated
+func (r *GroupReconciler) linkUserIdentity(ctx context.Context, ldapUser *structs.LDAPUser) error {
+	uid, email := ldapUser.GetUID(), ldapUser.GetEmail()
+	if uid == "" || email == "" {
+		return nil
+	}
+
+	linked, err := r.Store.User.GetEmail(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if linked == email {
+		return nil
+	}
+
+	return r.withUsersLocked(ctx, []string{uid, email}, func(ctx context.Context) error {
+		if linked != "" {
+			r.log.WithFields(logrus.Fields{
+				"uid":            uid,
+				"previous_email": logger.MaskEmail(linked),
+				"email":          logger.MaskEmail(email),
+			}).Info("user email changed in LDAP, re-linking it")
+		}
+		if err := r.Store.RekeyUser(ctx, email, uid); err != nil {
+			return fmt.Errorf("failed to move the records of user %s to its uid: %w", uid, err)
+		}
+		if err := r.Store.User.SetEmail(ctx, uid, email); err != nil {
+			return fmt.Errorf("failed to link the email of user %s: %w", uid, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-data-and-ai/usernaut/internal/controller/mocks"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/locker"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
+)
+
+func TestFetchLDAPData_LinksUserIdentities(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{
+		Store:    store.New(inMemCache),
+		Locker:   locker.NewMemoryLocker(),
+		LdapConn: ldapClient,
+		log:      logrus.NewEntry(logrus.New()),
+	}
+
+	// alice is linked to her previous email, bob was cached by email before his uid was known
+	require.NoError(t, r.Store.User.SetBackend(ctx, "alice", "gitlab_gitlab", "1"))
+	require.NoError(t, r.Store.User.SetEmail(ctx, "alice", "alice@example.com"))
+	require.NoError(t, r.Store.User.SetBackend(ctx, "bob@example.com", "gitlab_gitlab", "2"))
+	require.NoError(t, r.Store.UserGroups.AddGroup(ctx, "bob@example.com", "team-a"))
+	require.NoError(t, r.Store.Group.SetMembers(ctx, "team-a", []string{"bob@example.com"}))
+
+	ldapClient.EXPECT().GetBulkUserLDAPData(gomock.Any(), []string{"alice", "bob"}).Return(
+		map[string]map[string]interface{}{
+			"alice": {"uid": "alice", "mail": "alice.smith@example.com"},
+			"bob":   {"uid": "bob", "mail": "bob@example.com"},
+		}, nil)
+
+	result, err := r.fetchLDAPData(ctx, []string{"alice", "bob"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, result.CurrentMembers)
+
+	// the changed email of alice is re-linked without touching her backend IDs
+	uid, err := r.Store.User.GetUIDByEmail(ctx, "alice.smith@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", uid)
+	uid, err = r.Store.User.GetUIDByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, uid)
+	backends, err := r.Store.User.GetBackends(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gitlab_gitlab": "1"}, backends)
+
+	// the records of bob are moved to his uid
+	backends, err = r.Store.User.GetBackends(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gitlab_gitlab": "2"}, backends)
+	exists, err := r.Store.User.Exists(ctx, "bob@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	groups, err := r.Store.UserGroups.GetGroups(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, groups)
+	members, err := r.Store.Group.GetMembers(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, members)
+}
+
+func TestFetchLDAPData_SkipsLinkingWhenReadOnly(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	ldapClient := mocks.NewMockLDAPClient(ctrl)
+	r := &GroupReconciler{
+		Store:    store.New(inMemCache),
+		Locker:   locker.NewMemoryLocker(),
+		LdapConn: ldapClient,
+		log:      logrus.NewEntry(logrus.New()),
+	}
+
+	require.NoError(t, r.Store.User.SetBackend(ctx, "alice", "gitlab_gitlab", "1"))
+	require.NoError(t, r.Store.User.SetEmail(ctx, "alice", "alice@example.com"))
+	require.NoError(t, r.Store.User.SetBackend(ctx, "bob@example.com", "gitlab_gitlab", "2"))
+
+	ldapClient.EXPECT().GetBulkUserLDAPData(gomock.Any(), []string{"alice", "bob"}).Return(
+		map[string]map[string]interface{}{
+			"alice": {"uid": "alice", "mail": "alice.smith@example.com"},
+			"bob":   {"uid": "bob", "mail": "bob@example.com"},
+		}, nil)
+
+	result, err := r.fetchLDAPData(ctx, []string{"alice", "bob"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, result.CurrentMembers)
+
+	// the identity records and the records keyed by email are left untouched
+	email, err := r.Store.User.GetEmail(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+	exists, err := r.Store.User.Exists(ctx, "bob@example.com")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = r.Store.User.Exists(ctx, "bob")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
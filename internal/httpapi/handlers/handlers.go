@@ -93,8 +93,19 @@ func (h *Handlers) GetUserGroups(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	// The users are keyed by their uid, or by their email until it is linked to their uid
+	userKey, err := h.store.User.GetUIDByEmail(ctx, email)
+	if err != nil {
+		logrus.WithField("email", logger.MaskEmail(email)).WithError(err).Error("failed to resolve user")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch user groups"})
+		return
+	}
+	if userKey == "" {
+		userKey = email
+	}
+
 	// Get groups for the user from the reverse index
-	groups, err := h.store.UserGroups.GetGroups(ctx, email)
+	groups, err := h.store.UserGroups.GetGroups(ctx, userKey)
 	if err != nil {
 		logrus.WithField("email", logger.MaskEmail(email)).WithError(err).Error("failed to fetch user groups")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch user groups"})
@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/redhat-data-and-ai/usernaut/pkg/store"
 )
 
 func TestEmailRegexAcceptsValidEmail(t *testing.T) {
@@ -68,3 +73,44 @@ func TestGetUserGroupsValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUserGroups(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	inMemCache, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	dataStore := store.New(inMemCache)
+	// alice is keyed by her uid, bob isn't linked to his uid yet and is keyed by his email
+	require.NoError(t, dataStore.User.SetEmail(ctx, "alice", "alice@example.com"))
+	require.NoError(t, dataStore.UserGroups.AddGroup(ctx, "alice", "team-a"))
+	require.NoError(t, dataStore.UserGroups.AddGroup(ctx, "bob@example.com", "team-b"))
+
+	tests := []struct {
+		email        string
+		expectedBody string
+	}{
+		{
+			email:        "alice@example.com",
+			expectedBody: `{"email":"alice@example.com","groups":[{"name":"team-a","backends":[]}]}`,
+		},
+		{
+			email:        "bob@example.com",
+			expectedBody: `{"email":"bob@example.com","groups":[{"name":"team-b","backends":[]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/user/"+tt.email+"/groups", nil)
+			c.Params = gin.Params{{Key: "email", Value: tt.email}}
+
+			NewHandlers(nil, dataStore).GetUserGroups(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,576 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/clients"
+	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+	"github.com/redhat-data-and-ai/usernaut/pkg/utils"
+)
+
+// SetupGroupWebhookWithManager registers the defaulting and validating webhooks for Group in the manager.
+// The validator reads Group CRs through the API reader so that it is not limited to the namespaces
+// watched by the manager cache.
+func SetupGroupWebhookWithManager(mgr ctrl.Manager, appConfig *config.AppConfig) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&usernautdevv1alpha1.Group{}).
+		WithDefaulter(&GroupCustomDefaulter{}).
+		WithValidator(&GroupCustomValidator{
+			Reader:    mgr.GetAPIReader(),
+			AppConfig: appConfig,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-operator-dataverse-redhat-com-v1alpha1-group,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.dataverse.redhat.com,resources=groups,verbs=create;update,versions=v1alpha1,name=mgroup-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// GroupCustomDefaulter normalizes a Group spec before it is persisted.
+type GroupCustomDefaulter struct{}
+
+var _ admission.CustomDefaulter = &GroupCustomDefaulter{}
+
+// Default implements admission.CustomDefaulter.
+func (d *GroupCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	group, ok := obj.(*usernautdevv1alpha1.Group)
+	if !ok {
+		return fmt.Errorf("expected a Group object but got %T", obj)
+	}
+	logger.Logger(ctx).WithField("group", group.Name).Debug("defaulting group")
+
+	if strings.TrimSpace(group.Spec.GroupName) == "" {
+		group.Spec.GroupName = group.Name
+	}
+	group.Spec.Owners = uniqueNonEmpty(group.Spec.Owners)
+	group.Spec.Contact = strings.TrimSpace(group.Spec.Contact)
+	group.Spec.Members.Users = uniqueNonEmpty(group.Spec.Members.Users)
+	group.Spec.Members.Groups = uniqueNonEmpty(group.Spec.Members.Groups)
+	group.Spec.Members.LDAPGroups = uniqueNonEmpty(group.Spec.Members.LDAPGroups)
+	for i := range group.Spec.Members.ServiceAccounts {
+		serviceAccount := &group.Spec.Members.ServiceAccounts[i]
+		serviceAccount.Name = strings.TrimSpace(serviceAccount.Name)
+		serviceAccount.Email = strings.TrimSpace(serviceAccount.Email)
+	}
+	normalizeLDAPQuery(group.Spec.Members.LDAPQuery)
+	if exclude := group.Spec.Members.Exclude; exclude != nil {
+		exclude.Users = uniqueNonEmpty(exclude.Users)
+		normalizeLDAPQuery(exclude.LDAPQuery)
+	}
+
+	return nil
+}
+
+// uniqueNonEmpty trims the given values and drops blanks and duplicates while preserving order.
+func uniqueNonEmpty(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, exists := seen[value]; exists {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+// normalizeLDAPQuery lowercases operators and criteria so that they match the CRD enums.
+func normalizeLDAPQuery(query *usernautdevv1alpha1.LDAPQuery) {
+	if query == nil {
+		return
+	}
+	query.Operator = strings.ToLower(strings.TrimSpace(query.Operator))
+	for i := range query.Filters {
+		filter := &query.Filters[i]
+		if filter.Criteria != "" {
+			filter.Criteria = strings.ToLower(strings.TrimSpace(filter.Criteria))
+		}
+		normalizeLDAPQuery(filter.LDAPQuery)
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-operator-dataverse-redhat-com-v1alpha1-group,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.dataverse.redhat.com,resources=groups,verbs=create;update,versions=v1alpha1,name=vgroup-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// GroupCustomValidator rejects Group specs that would otherwise only fail during reconciliation.
+type GroupCustomValidator struct {
+	Reader    client.Reader
+	AppConfig *config.AppConfig
+}
+
+var _ admission.CustomValidator = &GroupCustomValidator{}
+
+// minResyncInterval is the shortest spec.resyncInterval a Group may ask for
+const minResyncInterval = 5 * time.Minute
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *GroupCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	group, ok := obj.(*usernautdevv1alpha1.Group)
+	if !ok {
+		return nil, fmt.Errorf("expected a Group object but got %T", obj)
+	}
+	return v.validateGroup(ctx, group)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *GroupCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	group, ok := newObj.(*usernautdevv1alpha1.Group)
+	if !ok {
+		return nil, fmt.Errorf("expected a Group object but got %T", newObj)
+	}
+	// Groups that are being deleted only need their finalizer removed; do not block that update.
+	if !group.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	warnings, err := v.validateGroup(ctx, group)
+	if oldGroup, ok := oldObj.(*usernautdevv1alpha1.Group); ok && oldGroup.Spec.GroupName != group.Spec.GroupName {
+		warnings = append(warnings, fmt.Sprintf("spec.group_name changed from %q to %q: teams are renamed on "+
+			"backends that support it, on the other backends a new team is created and the old one is "+
+			"handled according to the deletion policy", oldGroup.Spec.GroupName, group.Spec.GroupName))
+	}
+	return warnings, err
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *GroupCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *GroupCustomValidator) validateGroup(ctx context.Context,
+	group *usernautdevv1alpha1.Group) (admission.Warnings, error) {
+	log := logger.Logger(ctx).WithField("group", group.Name)
+
+	specPath := field.NewPath("spec")
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, v.validateBackends(group.Spec.Backends, specPath.Child("backends"))...)
+	allErrs = append(allErrs, validateGroupParams(group.Spec, specPath.Child("group_params"))...)
+	allErrs = append(allErrs, validateContact(group.Spec.Contact, specPath.Child("contact"))...)
+	allErrs = append(allErrs, validateOwnerRoles(group.Spec, specPath.Child("owners"))...)
+	allErrs = append(allErrs, validateResyncInterval(group.Spec.ResyncInterval, specPath.Child("resyncInterval"))...)
+	allErrs = append(allErrs, v.validateLDAPQuery(group.Spec.Members.LDAPQuery,
+		specPath.Child("members", "ldap_query"))...)
+	allErrs = append(allErrs, validateLDAPGroups(group.Spec.Members.LDAPGroups,
+		specPath.Child("members", "ldap_groups"))...)
+	allErrs = append(allErrs, validateServiceAccounts(group.Spec,
+		specPath.Child("members", "service_accounts"))...)
+	if exclude := group.Spec.Members.Exclude; exclude != nil {
+		allErrs = append(allErrs, v.validateLDAPQuery(exclude.LDAPQuery,
+			specPath.Child("members", "exclude", "ldap_query"))...)
+	}
+	roleWarnings, roleErrs := validateMemberRoles(group.Spec, specPath.Child("members", "roles"))
+	allErrs = append(allErrs, roleErrs...)
+	roleWarnings = append(roleWarnings,
+		timeBoundUserWarnings(group.Spec.Members, specPath.Child("members", "time_bound_users"))...)
+	roleWarnings = append(roleWarnings,
+		excludedUserWarnings(group.Spec.Members, specPath.Child("members", "exclude", "users"))...)
+
+	warnings, groupErrs, err := v.validateMemberGroups(ctx, group, specPath.Child("members", "groups"))
+	warnings = append(roleWarnings, warnings...)
+	if err != nil {
+		log.WithError(err).Error("failed to validate member groups")
+		return warnings, err
+	}
+	allErrs = append(allErrs, groupErrs...)
+
+	teamNameErrs, err := v.validateTeamNames(ctx, group, specPath.Child("backends"))
+	if err != nil {
+		log.WithError(err).Error("failed to validate team names")
+		return warnings, err
+	}
+	allErrs = append(allErrs, teamNameErrs...)
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	log.WithField("errors", allErrs.ToAggregate().Error()).Info("rejecting invalid group")
+	return warnings, apierrors.NewInvalid(usernautdevv1alpha1.GroupVersion.WithKind("Group").GroupKind(),
+		group.Name, allErrs)
+}
+
+// validateBackends checks every backend against the configured backends in AppConfig.BackendMap.
+func (v *GroupCustomValidator) validateBackends(backends []usernautdevv1alpha1.Backend,
+	fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := make(map[string]struct{}, len(backends))
+	for i, backend := range backends {
+		idxPath := fldPath.Index(i)
+		backendKey := backend.Name + "_" + backend.Type
+		if _, exists := seen[backendKey]; exists {
+			allErrs = append(allErrs, field.Duplicate(idxPath, backend.Type+"/"+backend.Name))
+			continue
+		}
+		seen[backendKey] = struct{}{}
+
+		configured, ok := v.AppConfig.BackendMap[backend.Type][backend.Name]
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(idxPath, backend.Type+"/"+backend.Name))
+			continue
+		}
+		if !configured.Enabled {
+			allErrs = append(allErrs, field.Invalid(idxPath, backend.Type+"/"+backend.Name,
+				"backend is not enabled"))
+		}
+	}
+	return allErrs
+}
+
+// validateGroupParams mirrors the group param checks done in GroupReconciler.processAllBackends.
+func validateGroupParams(spec usernautdevv1alpha1.GroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validBackends := make(map[string]bool, len(spec.Backends))
+	for _, backend := range spec.Backends {
+		validBackends[backend.Name+"_"+backend.Type] = true
+	}
+	for i, param := range spec.GroupParams {
+		idxPath := fldPath.Index(i)
+		if !validBackends[param.Name+"_"+param.Backend] {
+			allErrs = append(allErrs, field.Invalid(idxPath, param.Backend+"/"+param.Name,
+				"group param refers to a backend that is not listed in spec.backends"))
+		}
+		if strings.TrimSpace(param.Property) == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("property"),
+				"group param property must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+// validateMemberRoles checks that every role is supported by its backend type and that a user
+// doesn't get more than one role for the same backend type.
+func validateMemberRoles(spec usernautdevv1alpha1.GroupSpec, fldPath *field.Path) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	allErrs := field.ErrorList{}
+
+	backendTypes := make(map[string]struct{}, len(spec.Backends))
+	for _, backend := range spec.Backends {
+		backendTypes[backend.Type] = struct{}{}
+	}
+
+	userRoles := make(map[string]string)
+	for i, memberRole := range spec.Members.Roles {
+		idxPath := fldPath.Index(i)
+		if err := clients.ValidateRole(memberRole.Backend, memberRole.Role); err != nil {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("role"), memberRole.Role,
+				clients.SupportedRoles(memberRole.Backend)))
+		}
+		if _, ok := backendTypes[memberRole.Backend]; !ok {
+			warnings = append(warnings, fmt.Sprintf("%s has no effect as no %s backend is listed in spec.backends",
+				idxPath.String(), memberRole.Backend))
+		}
+		for j, user := range memberRole.Users {
+			key := memberRole.Backend + "/" + user
+			if role, exists := userRoles[key]; exists && role != memberRole.Role {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("users").Index(j), user,
+					fmt.Sprintf("user already has role %q for %s backend", role, memberRole.Backend)))
+				continue
+			}
+			userRoles[key] = memberRole.Role
+		}
+	}
+	return warnings, allErrs
+}
+
+// timeBoundUserWarnings warns about time bound users whose time window has no effect
+// because they are also listed as permanent members.
+func timeBoundUserWarnings(members usernautdevv1alpha1.Members, fldPath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+	for i, user := range members.TimeBoundUsers {
+		if slices.Contains(members.Users, user.UID) {
+			warnings = append(warnings, fmt.Sprintf("%s: user %q is also listed in spec.members.users, "+
+				"its time window has no effect", fldPath.Index(i).String(), user.UID))
+		}
+	}
+	return warnings
+}
+
+// excludedUserWarnings warns about users that are listed as members and excluded at the same time,
+// the exclusion always wins.
+func excludedUserWarnings(members usernautdevv1alpha1.Members, fldPath *field.Path) admission.Warnings {
+	if members.Exclude == nil {
+		return nil
+	}
+	var warnings admission.Warnings
+	for i, user := range members.Exclude.Users {
+		if slices.Contains(members.Users, user) {
+			warnings = append(warnings, fmt.Sprintf("%s: user %q is also listed in spec.members.users, "+
+				"it will not be a member of the group", fldPath.Index(i).String(), user))
+		}
+	}
+	return warnings
+}
+
+// validateLDAPQuery builds the LDAP filter the same way the reconciler does and reports any build error.
+func (v *GroupCustomValidator) validateLDAPQuery(query *usernautdevv1alpha1.LDAPQuery,
+	fldPath *field.Path) field.ErrorList {
+	if query == nil {
+		return nil
+	}
+	if _, err := ldap.BuildQueryFromSpec(query, v.AppConfig.LDAP.BaseUserDN); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, query, err.Error())}
+	}
+	return nil
+}
+
+// validateContact checks that the contact is a bare email address.
+func validateContact(contact string, fldPath *field.Path) field.ErrorList {
+	if contact == "" {
+		return nil
+	}
+	if address, err := mail.ParseAddress(contact); err != nil || address.Address != contact {
+		return field.ErrorList{field.Invalid(fldPath, contact, "contact must be an email address")}
+	}
+	return nil
+}
+
+// validateOwnerRoles rejects owners that spec.members.roles assigns a role other than the owner
+// role of the backend, since owners always get the owner role.
+func validateOwnerRoles(spec usernautdevv1alpha1.GroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, owner := range spec.Owners {
+		for _, memberRole := range spec.Members.Roles {
+			ownerRole := clients.OwnerRole(memberRole.Backend)
+			if !slices.Contains(memberRole.Users, owner) || strings.EqualFold(memberRole.Role, ownerRole) {
+				continue
+			}
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), owner,
+				fmt.Sprintf("owner has role %q for %s backend in spec.members.roles, owners always get the %q role",
+					memberRole.Role, memberRole.Backend, ownerRole)))
+		}
+	}
+	return allErrs
+}
+
+// validateResyncInterval rejects resync intervals short enough to overload LDAP and the backends.
+func validateResyncInterval(interval *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	if interval == nil || interval.Duration >= minResyncInterval {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, interval.Duration.String(),
+		fmt.Sprintf("resync interval must be at least %s", minResyncInterval))}
+}
+
+// validateServiceAccounts rejects duplicate service accounts, invalid emails and service accounts
+// on groups with backends that can't create service users.
+func validateServiceAccounts(spec usernautdevv1alpha1.GroupSpec, fldPath *field.Path) field.ErrorList {
+	if len(spec.Members.ServiceAccounts) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	for _, backend := range spec.Backends {
+		if !clients.SupportsServiceAccounts(backend.Type) {
+			allErrs = append(allErrs, field.Invalid(fldPath, backend.Name,
+				fmt.Sprintf("service accounts are not supported by %s backend", backend.Type)))
+		}
+	}
+	seen := make(map[string]struct{}, len(spec.Members.ServiceAccounts))
+	for i, serviceAccount := range spec.Members.ServiceAccounts {
+		if _, exists := seen[serviceAccount.Name]; exists {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), serviceAccount.Name))
+		}
+		seen[serviceAccount.Name] = struct{}{}
+		if serviceAccount.Email == "" {
+			continue
+		}
+		if address, err := mail.ParseAddress(serviceAccount.Email); err != nil || address.Address != serviceAccount.Email {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("email"), serviceAccount.Email,
+				"email must be an email address"))
+		}
+	}
+	return allErrs
+}
+
+// validateLDAPGroups checks that every LDAP group is a valid DN.
+func validateLDAPGroups(groupDNs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, groupDN := range groupDNs {
+		if _, err := ldapv3.ParseDN(groupDN); err != nil || strings.TrimSpace(groupDN) == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), groupDN, "ldap group must be a valid DN"))
+		}
+	}
+	return allErrs
+}
+
+// validateMemberGroups detects cycles across spec.members.groups using the Group CRs in the same
+// namespace, with the incoming spec taking precedence over the stored one. Referenced groups that
+// do not exist yet are reported as warnings since they may be created afterwards.
+func (v *GroupCustomValidator) validateMemberGroups(ctx context.Context, group *usernautdevv1alpha1.Group,
+	fldPath *field.Path) (admission.Warnings, field.ErrorList, error) {
+	if len(group.Spec.Members.Groups) == 0 {
+		return nil, nil, nil
+	}
+
+	groupList := &usernautdevv1alpha1.GroupList{}
+	if err := v.Reader.List(ctx, groupList, client.InNamespace(group.Namespace)); err != nil {
+		return nil, nil, err
+	}
+
+	edges := make(map[string][]string, len(groupList.Items)+1)
+	groupsByName := make(map[string]*usernautdevv1alpha1.Group, len(groupList.Items))
+	for i, item := range groupList.Items {
+		edges[item.Name] = item.Spec.Members.Groups
+		groupsByName[item.Name] = &groupList.Items[i]
+	}
+	edges[group.Name] = group.Spec.Members.Groups
+
+	var warnings admission.Warnings
+	for _, subGroup := range group.Spec.Members.Groups {
+		if _, exists := edges[subGroup]; !exists {
+			warnings = append(warnings, fmt.Sprintf("member group %q does not exist in namespace %q",
+				subGroup, group.Namespace))
+		}
+	}
+	if group.Spec.UsesNativeNesting() {
+		warnings = append(warnings, nestingWarnings(group, groupsByName)...)
+	}
+
+	if cycle := findCycle(group.Name, edges); cycle != nil {
+		return warnings, field.ErrorList{field.Invalid(fldPath, group.Spec.Members.Groups,
+			"cyclic group dependency detected: "+strings.Join(cycle, " -> "))}, nil
+	}
+	return warnings, nil, nil
+}
+
+// nestingWarnings reports the backends on which the member groups can't be nested natively, their
+// members are added to the team individually there.
+func nestingWarnings(group *usernautdevv1alpha1.Group,
+	groupsByName map[string]*usernautdevv1alpha1.Group) admission.Warnings {
+	var warnings admission.Warnings
+	nestingBackends := make([]usernautdevv1alpha1.Backend, 0, len(group.Spec.Backends))
+	for _, backend := range group.Spec.Backends {
+		if clients.SupportsNativeNesting(backend.Type) {
+			nestingBackends = append(nestingBackends, backend)
+		} else {
+			warnings = append(warnings, fmt.Sprintf("spec.nesting: %s backend doesn't support native nesting, "+
+				"the members of the member groups are added to %s/%s individually", backend.Type, backend.Type, backend.Name))
+		}
+	}
+	for _, subGroup := range group.Spec.Members.Groups {
+		subGroupCR, exists := groupsByName[subGroup]
+		if !exists {
+			continue
+		}
+		for _, backend := range nestingBackends {
+			if !slices.ContainsFunc(subGroupCR.Spec.Backends, func(b usernautdevv1alpha1.Backend) bool {
+				return b.Name == backend.Name && b.Type == backend.Type
+			}) {
+				warnings = append(warnings, fmt.Sprintf("spec.nesting: member group %q has no team on %s/%s, "+
+					"its members are added individually", subGroup, backend.Type, backend.Name))
+			}
+		}
+	}
+	return warnings
+}
+
+// validateTeamNames rejects backend teams that are already managed by another Group CR in the same namespace.
+// Only the teams named by a teamName override on either side are compared, Groups sharing a group_name
+// are left to the existing behaviour.
+func (v *GroupCustomValidator) validateTeamNames(ctx context.Context, group *usernautdevv1alpha1.Group,
+	fldPath *field.Path) (field.ErrorList, error) {
+	groupList := &usernautdevv1alpha1.GroupList{}
+	if err := v.Reader.List(ctx, groupList, client.InNamespace(group.Namespace)); err != nil {
+		return nil, err
+	}
+	if !hasTeamNameOverride(group) && !slices.ContainsFunc(groupList.Items, func(other usernautdevv1alpha1.Group) bool {
+		return other.Name != group.Name && hasTeamNameOverride(&other)
+	}) {
+		return nil, nil
+	}
+
+	allErrs := field.ErrorList{}
+	for i, backend := range group.Spec.Backends {
+		teamName, err := utils.GetBackendTeamName(v.AppConfig, backend.Type, backend.TeamName, group.Spec.GroupName)
+		if err != nil {
+			continue
+		}
+		for _, other := range groupList.Items {
+			if other.Namespace == group.Namespace && other.Name == group.Name {
+				continue
+			}
+			for _, otherBackend := range other.Spec.Backends {
+				if otherBackend.Name != backend.Name || otherBackend.Type != backend.Type ||
+					(backend.TeamName == "" && otherBackend.TeamName == "") {
+					continue
+				}
+				otherTeamName, err := utils.GetBackendTeamName(v.AppConfig, otherBackend.Type,
+					otherBackend.TeamName, other.Spec.GroupName)
+				if err != nil || otherTeamName != teamName {
+					continue
+				}
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("teamName"), teamName,
+					fmt.Sprintf("team of %s backend is already managed by Group %s/%s",
+						backend.Name, other.Namespace, other.Name)))
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// hasTeamNameOverride reports whether any backend of the Group names its team through a teamName override.
+func hasTeamNameOverride(group *usernautdevv1alpha1.Group) bool {
+	return slices.ContainsFunc(group.Spec.Backends, func(backend usernautdevv1alpha1.Backend) bool {
+		return backend.TeamName != ""
+	})
+}
+
+// findCycle returns the path of the first cycle that leads back to start, or nil if there is none.
+func findCycle(start string, edges map[string][]string) []string {
+	done := make(map[string]struct{})
+	path := []string{start}
+
+	var visit func(node string) []string
+	visit = func(node string) []string {
+		for _, next := range edges[node] {
+			if next == start {
+				return append(append([]string{}, path...), next)
+			}
+			if _, ok := done[next]; ok {
+				continue
+			}
+			done[next] = struct{}{}
+			path = append(path, next)
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+			path = path[:len(path)-1]
+		}
+		return nil
+	}
+	return visit(start)
+}
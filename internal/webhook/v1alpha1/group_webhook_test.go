@@ -0,0 +1,431 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	usernautdevv1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/clients/ldap"
+	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+)
+
+// groupListReader is a minimal client.Reader that only serves Group lists. Like the namespaced Role of the
+// manager it forbids listing Groups across all namespaces.
+type groupListReader struct {
+	groups []usernautdevv1alpha1.Group
+}
+
+func (r *groupListReader) Get(_ context.Context, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	return nil
+}
+
+func (r *groupListReader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Namespace == "" {
+		return apierrors.NewForbidden(schema.GroupResource{Group: "operator.dataverse.redhat.com", Resource: "groups"},
+			"", errors.New("groups is forbidden at the cluster scope"))
+	}
+
+	groups := &list.(*usernautdevv1alpha1.GroupList).Items
+	for _, group := range r.groups {
+		if group.Namespace == listOpts.Namespace {
+			*groups = append(*groups, group)
+		}
+	}
+	return nil
+}
+
+func newTestValidator(groups ...usernautdevv1alpha1.Group) *GroupCustomValidator {
+	return &GroupCustomValidator{
+		Reader: &groupListReader{groups: groups},
+		AppConfig: &config.AppConfig{
+			LDAP: ldap.LDAP{BaseUserDN: "ou=users,dc=example,dc=com"},
+			Pattern: map[string][]config.PatternEntry{
+				"default": {{Input: "^(.*)$", Output: "$1"}},
+			},
+			BackendMap: map[string]map[string]config.Backend{
+				"fivetran": {"fivetran": {Name: "fivetran", Type: "fivetran", Enabled: true}},
+				"gitlab":   {"gitlab": {Name: "gitlab", Type: "gitlab", Enabled: false}},
+				"rover":    {"rover": {Name: "rover", Type: "rover", Enabled: true}},
+			},
+		},
+	}
+}
+
+func newTestGroup(name string, subGroups ...string) usernautdevv1alpha1.Group {
+	return usernautdevv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: usernautdevv1alpha1.GroupSpec{
+			GroupName: name,
+			Members: usernautdevv1alpha1.Members{
+				Users:  []string{"alice"},
+				Groups: subGroups,
+			},
+			Backends: []usernautdevv1alpha1.Backend{{Name: "fivetran", Type: "fivetran"}},
+		},
+	}
+}
+
+func TestGroupCustomValidator_ValidGroup(t *testing.T) {
+	t.Parallel()
+
+	child := newTestGroup("child")
+	group := newTestGroup("parent", "child")
+
+	warnings, err := newTestValidator(child).ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestGroupCustomValidator_RejectsInvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mutate  func(g *usernautdevv1alpha1.Group)
+		errPart string
+	}{
+		{
+			name: "unknown backend",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Backends = append(g.Spec.Backends, usernautdevv1alpha1.Backend{Name: "nope", Type: "rover"})
+			},
+			errPart: "spec.backends[1]: Not found",
+		},
+		{
+			name: "disabled backend",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Backends = append(g.Spec.Backends, usernautdevv1alpha1.Backend{Name: "gitlab", Type: "gitlab"})
+			},
+			errPart: "backend is not enabled",
+		},
+		{
+			name: "duplicate backend",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Backends = append(g.Spec.Backends, g.Spec.Backends[0])
+			},
+			errPart: "spec.backends[1]: Duplicate value",
+		},
+		{
+			name: "group param for backend not in spec",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.GroupParams = []usernautdevv1alpha1.GroupParam{
+					{Backend: "snowflake", Name: "snowflake", Property: "roles", Value: []string{"a"}},
+				}
+			},
+			errPart: "group param refers to a backend that is not listed in spec.backends",
+		},
+		{
+			name: "group param with empty property",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.GroupParams = []usernautdevv1alpha1.GroupParam{
+					{Backend: "fivetran", Name: "fivetran", Value: []string{"a"}},
+				}
+			},
+			errPart: "spec.group_params[0].property: Required value",
+		},
+		{
+			name: "ldap query filter with both simple and nested parts",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.LDAPQuery = &usernautdevv1alpha1.LDAPQuery{
+					Operator: "and",
+					Filters: []usernautdevv1alpha1.LDAPFilter{{
+						Key: "co", Criteria: "equals", Value: "US",
+						LDAPQuery: &usernautdevv1alpha1.LDAPQuery{
+							Operator: "or",
+							Filters:  []usernautdevv1alpha1.LDAPFilter{{Key: "st", Criteria: "equals", Value: "NC"}},
+						},
+					}},
+				}
+			},
+			errPart: "cannot have both key/criteria/value and ldap_query",
+		},
+		{
+			name: "unsupported member role",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.Roles = []usernautdevv1alpha1.MemberRole{
+					{Backend: "fivetran", Role: "maintainer", Users: []string{"alice"}},
+				}
+			},
+			errPart: "spec.members.roles[0].role: Unsupported value",
+		},
+		{
+			name: "conflicting member roles",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.Roles = []usernautdevv1alpha1.MemberRole{
+					{Backend: "fivetran", Role: "Team Manager", Users: []string{"alice"}},
+					{Backend: "fivetran", Role: "Team Member", Users: []string{"alice"}},
+				}
+			},
+			errPart: "spec.members.roles[1].users[0]",
+		},
+		{
+			name: "invalid ldap group dn",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.LDAPGroups = []string{"cn=team,ou=groups,dc=example,dc=com", "not a dn"}
+			},
+			errPart: "spec.members.ldap_groups[1]",
+		},
+		{
+			name: "invalid exclude ldap query",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.Exclude = &usernautdevv1alpha1.MemberExclusion{
+					LDAPQuery: &usernautdevv1alpha1.LDAPQuery{
+						Operator: "and",
+						Filters:  []usernautdevv1alpha1.LDAPFilter{{Key: "co", Criteria: "equals"}},
+					},
+				}
+			},
+			errPart: "spec.members.exclude.ldap_query",
+		},
+		{
+			name: "invalid contact",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Contact = "Team <team@example.com>"
+			},
+			errPart: "spec.contact",
+		},
+		{
+			name: "owner with a non owner role",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Owners = []string{"alice"}
+				g.Spec.Members.Roles = []usernautdevv1alpha1.MemberRole{
+					{Backend: "fivetran", Role: "Team Member", Users: []string{"alice"}},
+				}
+			},
+			errPart: "spec.owners[0]",
+		},
+		{
+			name: "too short resync interval",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.ResyncInterval = &metav1.Duration{Duration: time.Minute}
+			},
+			errPart: "spec.resyncInterval",
+		},
+		{
+			name: "service accounts on a backend without service users",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.ServiceAccounts = []usernautdevv1alpha1.ServiceAccount{{Name: "ci-bot"}}
+			},
+			errPart: "service accounts are not supported by fivetran backend",
+		},
+		{
+			name: "duplicate service account",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.ServiceAccounts = []usernautdevv1alpha1.ServiceAccount{{Name: "ci-bot"}, {Name: "ci-bot"}}
+			},
+			errPart: "spec.members.service_accounts[1].name: Duplicate value",
+		},
+		{
+			name: "invalid service account email",
+			mutate: func(g *usernautdevv1alpha1.Group) {
+				g.Spec.Members.ServiceAccounts = []usernautdevv1alpha1.ServiceAccount{{Name: "ci-bot", Email: "ci-bot"}}
+			},
+			errPart: "spec.members.service_accounts[0].email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			group := newTestGroup("group")
+			tt.mutate(&group)
+
+			_, err := newTestValidator().ValidateCreate(context.Background(), &group)
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Contains(t, err.Error(), tt.errPart)
+		})
+	}
+}
+
+func TestGroupCustomValidator_RejectsTeamManagedByAnotherGroup(t *testing.T) {
+	t.Parallel()
+
+	other := newTestGroup("other")
+	other.Spec.Backends[0].TeamName = "legacy-team"
+
+	// an override pointing to the team of another Group
+	group := newTestGroup("group")
+	group.Spec.Backends[0].TeamName = "legacy-team"
+	_, err := newTestValidator(other).ValidateCreate(context.Background(), &group)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.backends[0].teamName")
+	assert.Contains(t, err.Error(), "already managed by Group default/other")
+
+	// a group_name deriving the team name that another Group adopted through an override
+	group = newTestGroup("legacy-team")
+	_, err = newTestValidator(other).ValidateCreate(context.Background(), &group)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already managed by Group default/other")
+
+	// the Group itself is skipped on update
+	group = newTestGroup("group")
+	group.Spec.Backends[0].TeamName = "own-team"
+	_, err = newTestValidator(group).ValidateUpdate(context.Background(), &group, &group)
+	require.NoError(t, err)
+
+	// Groups in other namespaces are not visible to the namespaced Role of the manager
+	elsewhere := other
+	elsewhere.Namespace = "other-namespace"
+	group = newTestGroup("group")
+	group.Spec.Backends[0].TeamName = "legacy-team"
+	_, err = newTestValidator(elsewhere).ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+}
+
+func TestGroupCustomValidator_ListsGroupsInItsNamespace(t *testing.T) {
+	t.Parallel()
+
+	// the validator only lists the namespace of the Group, so creates and updates, including the finalizer
+	// update of the controller, are admitted under the namespaced Role of the manager
+	group := newTestGroup("group")
+	validator := newTestValidator(newTestGroup("other"))
+	_, err := validator.ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+
+	updated := group
+	updated.Finalizers = []string{"operator.dataverse.redhat.com/finalizer"}
+	_, err = validator.ValidateUpdate(context.Background(), &group, &updated)
+	require.NoError(t, err)
+
+	group.Spec.Backends[0].TeamName = "own-team"
+	_, err = validator.ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+}
+
+func TestGroupCustomValidator_DetectsCycles(t *testing.T) {
+	t.Parallel()
+
+	a := newTestGroup("a", "b")
+	b := newTestGroup("b", "c")
+	c := newTestGroup("c")
+
+	updated := newTestGroup("c", "a")
+	_, err := newTestValidator(a, b, c).ValidateUpdate(context.Background(), &c, &updated)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic group dependency detected: c -> a -> b -> c")
+
+	self := newTestGroup("self", "self")
+	_, err = newTestValidator().ValidateCreate(context.Background(), &self)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "self -> self")
+}
+
+func TestGroupCustomValidator_WarnsOnMissingMemberGroup(t *testing.T) {
+	t.Parallel()
+
+	group := newTestGroup("parent", "missing")
+
+	warnings, err := newTestValidator().ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], `"missing"`)
+}
+
+func TestGroupCustomValidator_WarnsOnFlattenedNesting(t *testing.T) {
+	t.Parallel()
+
+	child := newTestGroup("child")
+	group := newTestGroup("parent", "child")
+	group.Spec.Backends = append(group.Spec.Backends, usernautdevv1alpha1.Backend{Name: "rover", Type: "rover"})
+
+	warnings, err := newTestValidator(child).ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	group.Spec.Nesting = usernautdevv1alpha1.NestingNative
+	warnings, err = newTestValidator(child).ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "fivetran backend doesn't support native nesting")
+	assert.Contains(t, warnings[1], `member group "child" has no team on rover/rover`)
+}
+
+func TestGroupCustomValidator_WarnsOnExcludedMember(t *testing.T) {
+	t.Parallel()
+
+	group := newTestGroup("group")
+	group.Spec.Members.Exclude = &usernautdevv1alpha1.MemberExclusion{Users: []string{"alice", "bob"}}
+
+	warnings, err := newTestValidator().ValidateCreate(context.Background(), &group)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "spec.members.exclude.users[0]")
+}
+
+func TestGroupCustomValidator_WarnsOnRename(t *testing.T) {
+	t.Parallel()
+
+	oldGroup := newTestGroup("group")
+	newGroup := newTestGroup("group")
+	newGroup.Spec.GroupName = "renamed"
+
+	warnings, err := newTestValidator().ValidateUpdate(context.Background(), &oldGroup, &newGroup)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], `from "group" to "renamed"`)
+}
+
+func TestGroupCustomDefaulter_Default(t *testing.T) {
+	t.Parallel()
+
+	group := &usernautdevv1alpha1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "default"},
+		Spec: usernautdevv1alpha1.GroupSpec{
+			Owners:  []string{" carol", "carol"},
+			Contact: " team@example.com ",
+			Members: usernautdevv1alpha1.Members{
+				Users:  []string{"alice", " alice ", "", "bob"},
+				Groups: []string{"x", "x"},
+				ServiceAccounts: []usernautdevv1alpha1.ServiceAccount{
+					{Name: " ci-bot ", Email: " ci-bot@example.com"},
+				},
+				LDAPQuery: &usernautdevv1alpha1.LDAPQuery{
+					Operator: "AND",
+					Filters: []usernautdevv1alpha1.LDAPFilter{
+						{Key: "co", Criteria: "Equals", Value: "US"},
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, (&GroupCustomDefaulter{}).Default(context.Background(), group))
+	assert.Equal(t, "team-a", group.Spec.GroupName)
+	assert.Equal(t, []string{"alice", "bob"}, group.Spec.Members.Users)
+	assert.Equal(t, []string{"carol"}, group.Spec.Owners)
+	assert.Equal(t, "team@example.com", group.Spec.Contact)
+	assert.Equal(t, []string{"x"}, group.Spec.Members.Groups)
+	assert.Equal(t, []usernautdevv1alpha1.ServiceAccount{{Name: "ci-bot", Email: "ci-bot@example.com"}},
+		group.Spec.Members.ServiceAccounts)
+	assert.Equal(t, "and", group.Spec.Members.LDAPQuery.Operator)
+	assert.Equal(t, "equals", group.Spec.Members.LDAPQuery.Filters[0].Criteria)
+}
@@ -42,6 +42,53 @@ type Cache interface {
 	// returns nil if the key was deleted successfully
 	// returns an error if the key was not deleted successfully
 	Delete(ctx context.Context, key string) error
+
+	// HGetAll returns the fields of the hash stored at key
+	// returns an empty map if the key was not found
+	// returns an error if the key doesn't hold a hash
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+
+	// HGet returns the value of a field of the hash stored at key
+	// returns an empty string if the key or the field was not found
+	// returns an error if the key doesn't hold a hash
+	HGet(ctx context.Context, key, field string) (string, error)
+
+	// HGetAllByPattern returns the fields of the hashes whose key matches the pattern, by key
+	// keys matching the pattern that don't hold a hash are skipped
+	HGetAllByPattern(ctx context.Context, keyPattern string) (map[string]map[string]string, error)
+
+	// HSet sets the given fields of the hash stored at key atomically, the other fields are kept
+	// the hash is created if the key was not found
+	HSet(ctx context.Context, key string, values map[string]string) error
+
+	// HReplace replaces the value stored at key with a hash of the given fields atomically,
+	// whatever the type of the previous value, the key is deleted if there are no fields
+	HReplace(ctx context.Context, key string, values map[string]string) error
+
+	// HDel deletes the given fields of the hash stored at key atomically
+	// the key is deleted along with the last field of the hash
+	HDel(ctx context.Context, key string, fields ...string) error
+
+	// SMembers returns the members of the set stored at key
+	// returns an empty slice if the key was not found
+	// returns an error if the key doesn't hold a set
+	SMembers(ctx context.Context, key string) ([]string, error)
+
+	// SAdd adds the members to the set stored at key atomically
+	// the set is created if the key was not found
+	SAdd(ctx context.Context, key string, members ...string) error
+
+	// SReplace replaces the value stored at key with a set of the given members atomically,
+	// whatever the type of the previous value, the key is deleted if there are no members
+	SReplace(ctx context.Context, key string, members ...string) error
+
+	// SRem removes the members from the set stored at key atomically
+	// the key is deleted along with the last member of the set
+	SRem(ctx context.Context, key string, members ...string) error
+
+	// RenameNX renames key to newKey atomically, whatever the type of its value
+	// returns false if newKey already exists or key was not found
+	RenameNX(ctx context.Context, key, newKey string) (bool, error)
 }
 
 // Config is the configuration for the cache client
@@ -2,16 +2,28 @@ package inmemory
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"sync"
 	"time"
 
 	gocache "github.com/patrickmn/go-cache"
 )
 
+// ErrWrongType is returned when the operation doesn't match the type of the value of the key,
+// like the WRONGTYPE error of redis
+var ErrWrongType = errors.New("operation against a key holding the wrong kind of value")
+
 // InMemoryCache holds the handler for the in-memory cache using go-cache
+// Hashes are stored as map[string]string and sets as map[string]struct{}, they are updated in place
+// and copied when they are returned to callers
 type InMemoryCache struct {
 	client *gocache.Cache
+	// mu guards the hashes and sets, and serializes the updates so that they are atomic
+	mu sync.RWMutex
 }
 
 // Config is the configuration for the in-memory cache
@@ -45,6 +57,9 @@ func (imc *InMemoryCache) Set(
 	value string,
 	ttl time.Duration,
 ) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
 	imc.client.Set(key, value, ttl)
 	return nil
 }
@@ -55,6 +70,9 @@ func (imc *InMemoryCache) Get(ctx context.Context, key string) (interface{}, err
 	if !found {
 		return "", fmt.Errorf("key not found")
 	}
+	if _, ok := val.(string); !ok {
+		return "", ErrWrongType
+	}
 	return val, nil
 }
 
@@ -67,9 +85,11 @@ func (imc *InMemoryCache) GetByPattern(ctx context.Context, keyPattern string) (
 
 	values := make(map[string]interface{})
 	for _, key := range keys {
-		val, found := imc.client.Get(key)
-		if found {
-			values[key] = val
+		// like MGET, hashes and sets are skipped
+		if val, found := imc.client.Get(key); found {
+			if _, ok := val.(string); ok {
+				values[key] = val
+			}
 		}
 	}
 	return values, nil
@@ -77,6 +97,9 @@ func (imc *InMemoryCache) GetByPattern(ctx context.Context, keyPattern string) (
 
 // Delete implements Cache.
 func (imc *InMemoryCache) Delete(ctx context.Context, key string) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
 	_, found := imc.client.Get(key)
 	if found {
 		imc.client.Delete(key)
@@ -84,6 +107,227 @@ func (imc *InMemoryCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// HGetAll implements Cache.
+func (imc *InMemoryCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	hash, err := imc.hash(key)
+	if err != nil {
+		return nil, err
+	}
+	if hash == nil {
+		return map[string]string{}, nil
+	}
+	return maps.Clone(hash), nil
+}
+
+// HGet implements Cache.
+func (imc *InMemoryCache) HGet(ctx context.Context, key, field string) (string, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	hash, err := imc.hash(key)
+	if err != nil {
+		return "", err
+	}
+	return hash[field], nil
+}
+
+// HGetAllByPattern implements Cache.
+func (imc *InMemoryCache) HGetAllByPattern(ctx context.Context,
+	keyPattern string) (map[string]map[string]string, error) {
+	keys, err := imc.ScanKeys(ctx, keyPattern)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning keys: %w", err)
+	}
+
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	values := make(map[string]map[string]string)
+	for _, key := range keys {
+		if val, found := imc.client.Get(key); found {
+			if hash, ok := val.(map[string]string); ok {
+				values[key] = maps.Clone(hash)
+			}
+		}
+	}
+	return values, nil
+}
+
+// HSet implements Cache.
+func (imc *InMemoryCache) HSet(ctx context.Context, key string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	hash, err := imc.hash(key)
+	if err != nil {
+		return err
+	}
+	if hash == nil {
+		hash = make(map[string]string, len(values))
+		imc.client.Set(key, hash, gocache.NoExpiration)
+	}
+	maps.Copy(hash, values)
+	return nil
+}
+
+// HReplace implements Cache.
+func (imc *InMemoryCache) HReplace(ctx context.Context, key string, values map[string]string) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	if len(values) == 0 {
+		imc.client.Delete(key)
+		return nil
+	}
+	imc.client.Set(key, maps.Clone(values), gocache.NoExpiration)
+	return nil
+}
+
+// HDel implements Cache.
+func (imc *InMemoryCache) HDel(ctx context.Context, key string, fields ...string) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	hash, err := imc.hash(key)
+	if err != nil || hash == nil {
+		return err
+	}
+	for _, field := range fields {
+		delete(hash, field)
+	}
+	imc.deleteIfEmpty(key, len(hash))
+	return nil
+}
+
+// SMembers implements Cache.
+func (imc *InMemoryCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	set, err := imc.set(key)
+	if err != nil {
+		return nil, err
+	}
+	return slices.AppendSeq(make([]string, 0, len(set)), maps.Keys(set)), nil
+}
+
+// SAdd implements Cache.
+func (imc *InMemoryCache) SAdd(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	set, err := imc.set(key)
+	if err != nil {
+		return err
+	}
+	if set == nil {
+		set = make(map[string]struct{}, len(members))
+		imc.client.Set(key, set, gocache.NoExpiration)
+	}
+	for _, member := range members {
+		set[member] = struct{}{}
+	}
+	return nil
+}
+
+// SReplace implements Cache.
+func (imc *InMemoryCache) SReplace(ctx context.Context, key string, members ...string) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	if len(members) == 0 {
+		imc.client.Delete(key)
+		return nil
+	}
+	set := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		set[member] = struct{}{}
+	}
+	imc.client.Set(key, set, gocache.NoExpiration)
+	return nil
+}
+
+// SRem implements Cache.
+func (imc *InMemoryCache) SRem(ctx context.Context, key string, members ...string) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	set, err := imc.set(key)
+	if err != nil || set == nil {
+		return err
+	}
+	for _, member := range members {
+		delete(set, member)
+	}
+	imc.deleteIfEmpty(key, len(set))
+	return nil
+}
+
+// RenameNX implements Cache.
+func (imc *InMemoryCache) RenameNX(ctx context.Context, key, newKey string) (bool, error) {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	val, expiration, found := imc.client.GetWithExpiration(key)
+	if !found {
+		return false, nil
+	}
+	if _, exists := imc.client.Get(newKey); exists {
+		return false, nil
+	}
+	ttl := gocache.NoExpiration
+	if !expiration.IsZero() {
+		ttl = time.Until(expiration)
+	}
+	imc.client.Set(newKey, val, ttl)
+	imc.client.Delete(key)
+	return true, nil
+}
+
+// hash returns the hash stored at key, nil if the key was not found, imc.mu must be held
+func (imc *InMemoryCache) hash(key string) (map[string]string, error) {
+	val, found := imc.client.Get(key)
+	if !found {
+		return nil, nil
+	}
+	hash, ok := val.(map[string]string)
+	if !ok {
+		return nil, ErrWrongType
+	}
+	return hash, nil
+}
+
+// set returns the set stored at key, nil if the key was not found, imc.mu must be held
+func (imc *InMemoryCache) set(key string) (map[string]struct{}, error) {
+	val, found := imc.client.Get(key)
+	if !found {
+		return nil, nil
+	}
+	set, ok := val.(map[string]struct{})
+	if !ok {
+		return nil, ErrWrongType
+	}
+	return set, nil
+}
+
+// deleteIfEmpty deletes a hash or a set without fields or members left like redis does, imc.mu must be held
+func (imc *InMemoryCache) deleteIfEmpty(key string, size int) {
+	if size == 0 {
+		imc.client.Delete(key)
+	}
+}
+
 // ScanKeys returns all keys matching the given pattern from in-memory cache
 // Pattern is a glob pattern (like Redis SCAN), where * matches any sequence of characters
 // and ? matches any single character
@@ -120,3 +120,138 @@ func TestInMemoryCacheGetByPattern(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(values))
 }
+
+func TestInMemoryCache_Hashes(t *testing.T) {
+	mem, err := NewCache(nil)
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	// a missing hash has no fields
+	fields, err := mem.HGetAll(ctx, "user:1")
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+
+	err = mem.HSet(ctx, "user:1", map[string]string{"gitlab": "1", "fivetran": "2"})
+	assert.Nil(t, err)
+	err = mem.HSet(ctx, "user:1", map[string]string{"fivetran": "3"})
+	assert.Nil(t, err)
+	err = mem.HSet(ctx, "user:2", map[string]string{"gitlab": "4"})
+	assert.Nil(t, err)
+	err = mem.Set(ctx, "user:3", "value3", time.Minute)
+	assert.Nil(t, err)
+
+	fields, err = mem.HGetAll(ctx, "user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"gitlab": "1", "fivetran": "3"}, fields)
+
+	// the hashes returned are copies
+	fields["gitlab"] = "6"
+	stored, err := mem.HGetAll(ctx, "user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", stored["gitlab"])
+
+	// the string records are skipped
+	hashes, err := mem.HGetAllByPattern(ctx, "user:*")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"user:1": {"gitlab": "1", "fivetran": "3"},
+		"user:2": {"gitlab": "4"},
+	}, hashes)
+
+	// the hash is removed with its last field
+	err = mem.HDel(ctx, "user:1", "gitlab", "fivetran")
+	assert.Nil(t, err)
+	_, found := mem.client.Get("user:1")
+	assert.False(t, found)
+
+	// values of the wrong type are not overwritten
+	_, err = mem.HGetAll(ctx, "user:3")
+	assert.ErrorIs(t, err, ErrWrongType)
+	err = mem.HSet(ctx, "user:3", map[string]string{"gitlab": "5"})
+	assert.ErrorIs(t, err, ErrWrongType)
+	_, err = mem.Get(ctx, "user:2")
+	assert.ErrorIs(t, err, ErrWrongType)
+
+	// a replaced record keeps none of its previous fields, whatever its type
+	assert.Nil(t, mem.HReplace(ctx, "user:2", map[string]string{"fivetran": "5"}))
+	assert.Nil(t, mem.HReplace(ctx, "user:3", map[string]string{"gitlab": "6"}))
+	hashes, err = mem.HGetAllByPattern(ctx, "user:*")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"user:2": {"fivetran": "5"},
+		"user:3": {"gitlab": "6"},
+	}, hashes)
+	assert.Nil(t, mem.HReplace(ctx, "user:2", nil))
+	_, found = mem.client.Get("user:2")
+	assert.False(t, found)
+}
+
+func TestInMemoryCache_Sets(t *testing.T) {
+	mem, err := NewCache(nil)
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	members, err := mem.SMembers(ctx, "user:groups:1")
+	assert.Nil(t, err)
+	assert.Empty(t, members)
+
+	err = mem.SAdd(ctx, "user:groups:1", "team-a", "team-b", "team-a")
+	assert.Nil(t, err)
+	err = mem.SRem(ctx, "user:groups:1", "team-a", "team-c")
+	assert.Nil(t, err)
+
+	members, err = mem.SMembers(ctx, "user:groups:1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-b"}, members)
+
+	// the set is removed with its last member
+	err = mem.SRem(ctx, "user:groups:1", "team-b")
+	assert.Nil(t, err)
+	_, found := mem.client.Get("user:groups:1")
+	assert.False(t, found)
+
+	// a replaced record keeps none of its previous members, whatever its type
+	assert.Nil(t, mem.SAdd(ctx, "user:groups:2", "team-a"))
+	assert.Nil(t, mem.SReplace(ctx, "user:groups:2", "team-b"))
+	assert.Nil(t, mem.Set(ctx, "user:groups:3", "[]", time.Minute))
+	assert.Nil(t, mem.SReplace(ctx, "user:groups:3", "team-c"))
+	members, err = mem.SMembers(ctx, "user:groups:2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-b"}, members)
+	members, err = mem.SMembers(ctx, "user:groups:3")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-c"}, members)
+	assert.Nil(t, mem.SReplace(ctx, "user:groups:2"))
+	_, found = mem.client.Get("user:groups:2")
+	assert.False(t, found)
+}
+
+func TestInMemoryCache_RenameNX(t *testing.T) {
+	mem, err := NewCache(nil)
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	assert.Nil(t, mem.HSet(ctx, "group:a", map[string]string{"members": "[]"}))
+	assert.Nil(t, mem.HSet(ctx, "group:b", map[string]string{"members": "[]"}))
+
+	renamed, err := mem.RenameNX(ctx, "group:a", "group:c")
+	assert.Nil(t, err)
+	assert.True(t, renamed)
+	_, found := mem.client.Get("group:a")
+	assert.False(t, found)
+	fields, err := mem.HGetAll(ctx, "group:c")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"members": "[]"}, fields)
+
+	// an existing key is not overwritten
+	renamed, err = mem.RenameNX(ctx, "group:c", "group:b")
+	assert.Nil(t, err)
+	assert.False(t, renamed)
+	_, found = mem.client.Get("group:c")
+	assert.True(t, found)
+
+	// a missing key is not renamed
+	renamed, err = mem.RenameNX(ctx, "group:a", "group:d")
+	assert.Nil(t, err)
+	assert.False(t, renamed)
+}
@@ -2,7 +2,9 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/extra/redisotel/v9"
@@ -119,11 +121,135 @@ func (rc *RedisCache) GetByPattern(ctx context.Context, keyPattern string) (map[
 	return values, nil
 }
 
+// Client returns the underlying redis client, e.g. to hold locks next to the cached records
+func (rc *RedisCache) Client() redis.UniversalClient {
+	return rc.client
+}
+
 // Delete - deletes a key from redis
 func (rc *RedisCache) Delete(ctx context.Context, key string) error {
 	return rc.client.Del(ctx, key).Err()
 }
 
+// HGetAll - gets all the fields of a hash from redis
+func (rc *RedisCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return rc.client.HGetAll(ctx, key).Result()
+}
+
+// HGet - gets a field of a hash from redis
+func (rc *RedisCache) HGet(ctx context.Context, key, field string) (string, error) {
+	val, err := rc.client.HGet(ctx, key, field).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return val, err
+}
+
+// HGetAllByPattern - gets all the fields of the hashes whose key matches the pattern
+func (rc *RedisCache) HGetAllByPattern(ctx context.Context, keyPattern string) (map[string]map[string]string, error) {
+	// Only the hashes are collected, other records may share the prefix of the pattern
+	var keys []string
+	iter := rc.client.ScanType(ctx, 0, keyPattern, 0, "hash").Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	// Use a pipeline to retrieve all the hashes in a single round trip
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	_, err := rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.HGetAll(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the hashes deleted between SCAN and HGETALL
+	values := make(map[string]map[string]string, len(keys))
+	for i, key := range keys {
+		if fields := cmds[i].Val(); len(fields) > 0 {
+			values[key] = fields
+		}
+	}
+	return values, nil
+}
+
+// HSet - sets fields of a hash in redis
+func (rc *RedisCache) HSet(ctx context.Context, key string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return rc.client.HSet(ctx, key, values).Err()
+}
+
+// HReplace - replaces a key in redis with a hash, DEL and HSET run in a single MULTI/EXEC transaction
+func (rc *RedisCache) HReplace(ctx context.Context, key string, values map[string]string) error {
+	_, err := rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(values) > 0 {
+			pipe.HSet(ctx, key, values)
+		}
+		return nil
+	})
+	return err
+}
+
+// HDel - deletes fields of a hash from redis
+func (rc *RedisCache) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return rc.client.HDel(ctx, key, fields...).Err()
+}
+
+// SMembers - gets the members of a set from redis
+func (rc *RedisCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	return rc.client.SMembers(ctx, key).Result()
+}
+
+// SAdd - adds members to a set in redis
+func (rc *RedisCache) SAdd(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	return rc.client.SAdd(ctx, key, members).Err()
+}
+
+// SReplace - replaces a key in redis with a set, DEL and SADD run in a single MULTI/EXEC transaction
+func (rc *RedisCache) SReplace(ctx context.Context, key string, members ...string) error {
+	_, err := rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(members) > 0 {
+			pipe.SAdd(ctx, key, members)
+		}
+		return nil
+	})
+	return err
+}
+
+// SRem - removes members from a set in redis
+func (rc *RedisCache) SRem(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	return rc.client.SRem(ctx, key, members).Err()
+}
+
+// RenameNX - renames a key in redis unless the new key exists
+func (rc *RedisCache) RenameNX(ctx context.Context, key, newKey string) (bool, error) {
+	renamed, err := rc.client.RenameNX(ctx, key, newKey).Result()
+	// RENAMENX fails on a missing key instead of reporting it
+	if err != nil && strings.Contains(err.Error(), "no such key") {
+		return false, nil
+	}
+	return renamed, err
+}
+
 // Disconnect ... disconnects from the redis server
 func (rc *RedisCache) Disconnect() error {
 	err := rc.client.Close()
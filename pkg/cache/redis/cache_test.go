@@ -101,3 +101,125 @@ func TestRedisCacheGetByPattern(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(values))
 }
+
+func TestRedisCache_Hashes(t *testing.T) {
+	srv := miniredis.RunT(t)
+	cache, err := NewCache(&Config{Host: srv.Host(), Port: srv.Port()})
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	// a missing hash has no fields
+	fields, err := cache.HGetAll(ctx, "user:1")
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+
+	err = cache.HSet(ctx, "user:1", map[string]string{"gitlab": "1", "fivetran": "2"})
+	assert.Nil(t, err)
+	err = cache.HSet(ctx, "user:1", map[string]string{"fivetran": "3"})
+	assert.Nil(t, err)
+	err = cache.HSet(ctx, "user:2", map[string]string{"gitlab": "4"})
+	assert.Nil(t, err)
+	err = cache.Set(ctx, "user:3", "value3", time.Minute)
+	assert.Nil(t, err)
+
+	fields, err = cache.HGetAll(ctx, "user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"gitlab": "1", "fivetran": "3"}, fields)
+
+	// the string records are skipped
+	hashes, err := cache.HGetAllByPattern(ctx, "user:*")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"user:1": {"gitlab": "1", "fivetran": "3"},
+		"user:2": {"gitlab": "4"},
+	}, hashes)
+
+	// the hash is removed with its last field
+	err = cache.HDel(ctx, "user:1", "gitlab", "fivetran")
+	assert.Nil(t, err)
+	assert.False(t, srv.Exists("user:1"))
+
+	// nothing to write is a no-op
+	assert.Nil(t, cache.HSet(ctx, "user:4", map[string]string{}))
+	assert.Nil(t, cache.HDel(ctx, "user:4"))
+
+	// a replaced record keeps none of its previous fields, whatever its type
+	assert.Nil(t, cache.HReplace(ctx, "user:2", map[string]string{"fivetran": "5"}))
+	assert.Nil(t, cache.HReplace(ctx, "user:3", map[string]string{"gitlab": "6"}))
+	hashes, err = cache.HGetAllByPattern(ctx, "user:*")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"user:2": {"fivetran": "5"},
+		"user:3": {"gitlab": "6"},
+	}, hashes)
+	assert.Nil(t, cache.HReplace(ctx, "user:2", nil))
+	assert.False(t, srv.Exists("user:2"))
+}
+
+func TestRedisCache_Sets(t *testing.T) {
+	srv := miniredis.RunT(t)
+	cache, err := NewCache(&Config{Host: srv.Host(), Port: srv.Port()})
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	members, err := cache.SMembers(ctx, "user:groups:1")
+	assert.Nil(t, err)
+	assert.Empty(t, members)
+
+	err = cache.SAdd(ctx, "user:groups:1", "team-a", "team-b", "team-a")
+	assert.Nil(t, err)
+	err = cache.SRem(ctx, "user:groups:1", "team-a", "team-c")
+	assert.Nil(t, err)
+
+	members, err = cache.SMembers(ctx, "user:groups:1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-b"}, members)
+
+	// the set is removed with its last member
+	err = cache.SRem(ctx, "user:groups:1", "team-b")
+	assert.Nil(t, err)
+	assert.False(t, srv.Exists("user:groups:1"))
+
+	// a replaced record keeps none of its previous members, whatever its type
+	assert.Nil(t, cache.SAdd(ctx, "user:groups:2", "team-a"))
+	assert.Nil(t, cache.SReplace(ctx, "user:groups:2", "team-b"))
+	assert.Nil(t, cache.Set(ctx, "user:groups:3", "[]", time.Minute))
+	assert.Nil(t, cache.SReplace(ctx, "user:groups:3", "team-c"))
+	members, err = cache.SMembers(ctx, "user:groups:2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-b"}, members)
+	members, err = cache.SMembers(ctx, "user:groups:3")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-c"}, members)
+	assert.Nil(t, cache.SReplace(ctx, "user:groups:2"))
+	assert.False(t, srv.Exists("user:groups:2"))
+}
+
+func TestRedisCache_RenameNX(t *testing.T) {
+	srv := miniredis.RunT(t)
+	cache, err := NewCache(&Config{Host: srv.Host(), Port: srv.Port()})
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	assert.Nil(t, cache.HSet(ctx, "group:a", map[string]string{"members": "[]"}))
+	assert.Nil(t, cache.HSet(ctx, "group:b", map[string]string{"members": "[]"}))
+
+	renamed, err := cache.RenameNX(ctx, "group:a", "group:c")
+	assert.Nil(t, err)
+	assert.True(t, renamed)
+	assert.False(t, srv.Exists("group:a"))
+	fields, err := cache.HGetAll(ctx, "group:c")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"members": "[]"}, fields)
+
+	// an existing key is not overwritten
+	renamed, err = cache.RenameNX(ctx, "group:c", "group:b")
+	assert.Nil(t, err)
+	assert.False(t, renamed)
+	assert.True(t, srv.Exists("group:c"))
+
+	// a missing key is not renamed
+	renamed, err = cache.RenameNX(ctx, "group:a", "group:d")
+	assert.Nil(t, err)
+	assert.False(t, renamed)
+}
@@ -19,6 +19,7 @@ package clients
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/redhat-data-and-ai/usernaut/pkg/clients/fivetran"
@@ -29,6 +30,12 @@ import (
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
 )
 
+var (
+	_ TeamNester = (*redhatrover.RoverClient)(nil)
+	_ TeamNester = (*gitlab.GitlabClient)(nil)
+	_ TeamNester = (*snowflake.SnowflakeClient)(nil)
+)
+
 var (
 	// ErrInvalidBackend is returned when an invalid backend type is provided
 	ErrInvalidBackend = errors.New("invalid backend")
@@ -60,10 +67,111 @@ type Client interface {
 	FetchTeamMembersByTeamID(ctx context.Context, teamID string) (map[string]*structs.User, error)
 	// ReconcileGroupParams reconciles backend-specific parameters for a group/team.
 	ReconcileGroupParams(ctx context.Context, teamID string, groupParams structs.TeamParams) error
-	// Adds a member to the team
-	AddUserToTeam(ctx context.Context, teamID string, userIDs []string) error
+	// Adds members to the team with the given backend specific role,
+	// an empty role means the default role of the backend
+	AddUserToTeam(ctx context.Context, teamID, role string, userIDs []string) error
 	// Removes a member from the team
 	RemoveUserFromTeam(ctx context.Context, teamID string, userIDs []string) error
+	// Changes the role of existing team members
+	UpdateUserRoleInTeam(ctx context.Context, teamID, role string, userIDs []string) error
+}
+
+// TeamRenamer is implemented by the backend clients that can rename an existing team in place,
+// keeping its ID, members and permissions
+type TeamRenamer interface {
+	// Renames the team and returns the renamed team
+	RenameTeam(ctx context.Context, teamID, newName string) (*structs.Team, error)
+}
+
+// TeamContactSetter is implemented by the backend clients whose teams have a contact address
+type TeamContactSetter interface {
+	// Sets the contact address of the team, it is a no-op when the contact is already up to date
+	SetTeamContact(ctx context.Context, teamID, contact string) error
+}
+
+// TeamNester is implemented by the backend clients that can nest teams natively, the members of a
+// nested team are members of the team it is nested in without being added to it one by one
+type TeamNester interface {
+	// Returns the IDs of the teams nested directly in the team
+	FetchNestedTeams(ctx context.Context, teamID string) ([]string, error)
+	// Nests the given teams in the team
+	NestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error
+	// Removes the given nested teams from the team, their own members are left untouched
+	UnnestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error
+}
+
+// DefaultRole returns the role assigned to team members of the given backend type that
+// don't have an explicit role. An empty string means the backend has no member roles.
+func DefaultRole(backendType string) string {
+	switch strings.ToLower(backendType) {
+	case "fivetran":
+		return fivetran.DefaultTeamRole
+	case "rover":
+		return redhatrover.DefaultRole
+	case "gitlab":
+		return gitlab.DefaultRole
+	default:
+		return ""
+	}
+}
+
+// OwnerRole returns the role that makes a team member an owner of the team for the given backend
+// type. An empty string means the backend has no owner role.
+func OwnerRole(backendType string) string {
+	switch strings.ToLower(backendType) {
+	case "fivetran":
+		return fivetran.TeamManagerRole
+	case "rover":
+		return redhatrover.RoleOwner
+	case "gitlab":
+		return gitlab.RoleOwner
+	default:
+		return ""
+	}
+}
+
+// SupportsServiceAccounts reports whether the given backend type can create service account users
+func SupportsServiceAccounts(backendType string) bool {
+	switch strings.ToLower(backendType) {
+	case "snowflake", "gitlab", "rover":
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsNativeNesting reports whether the given backend type can nest teams natively
+func SupportsNativeNesting(backendType string) bool {
+	switch strings.ToLower(backendType) {
+	case "snowflake", "gitlab", "rover":
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportedRoles returns the member roles supported by the given backend type
+func SupportedRoles(backendType string) []string {
+	switch strings.ToLower(backendType) {
+	case "fivetran":
+		return fivetran.TeamRoles
+	case "rover":
+		return redhatrover.Roles
+	case "gitlab":
+		return gitlab.Roles
+	default:
+		return nil
+	}
+}
+
+// ValidateRole returns an error if role is not supported by the given backend type
+func ValidateRole(backendType, role string) error {
+	for _, supported := range SupportedRoles(backendType) {
+		if strings.EqualFold(supported, role) {
+			return nil
+		}
+	}
+	return fmt.Errorf("role %q is not supported by %s backend", role, backendType)
 }
 
 func New(backendName, backendType string, backends map[string]map[string]config.Backend) (Client, error) {
@@ -17,7 +17,10 @@ limitations under the License.
 package fivetran
 
 import (
+	"net/http"
+
 	"github.com/fivetran/go-fivetran"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 )
 
 type FivetranClient struct {
@@ -25,7 +28,9 @@ type FivetranClient struct {
 }
 
 func NewClient(apiKey, apiSecret string) *FivetranClient {
+	client := fivetran.New(apiKey, apiSecret)
+	client.SetHttpClient(&http.Client{Transport: metrics.InstrumentRoundTripper("fivetran", http.DefaultTransport)})
 	return &FivetranClient{
-		fivetranClient: fivetran.New(apiKey, apiSecret),
+		fivetranClient: client,
 	}
 }
@@ -61,10 +61,14 @@ func (fc *FivetranClient) FetchTeamMembersByTeamID(
 
 }
 
-func (fc *FivetranClient) AddUserToTeam(ctx context.Context, teamID string, userIDs []string) error {
+func (fc *FivetranClient) AddUserToTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	if role == "" {
+		role = DefaultTeamRole
+	}
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
 		"service":    "fivetran",
 		"teamID":     teamID,
+		"role":       role,
 		"user_count": len(userIDs),
 	})
 
@@ -88,7 +92,7 @@ func (fc *FivetranClient) AddUserToTeam(ctx context.Context, teamID string, user
 				NewTeamUserMembershipCreate().
 				TeamId(teamID).
 				UserId(uid).
-				Role("Team Member").
+				Role(role).
 				Do(ctx)
 
 			if err != nil {
@@ -164,6 +168,60 @@ func (fc *FivetranClient) RemoveUserFromTeam(ctx context.Context, teamID string,
 	return nil
 }
 
+// UpdateUserRoleInTeam changes the team membership role of existing team members
+func (fc *FivetranClient) UpdateUserRoleInTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	if role == "" {
+		role = DefaultTeamRole
+	}
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service":    "fivetran",
+		"teamID":     teamID,
+		"role":       role,
+		"user_count": len(userIDs),
+	})
+
+	log.Info("updating role of team members")
+	var wg sync.WaitGroup
+	errch := make(chan error, len(userIDs))
+	sem := make(chan struct{}, maxConcurrentUsers)
+
+	for _, id := range userIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(uid string, log logrus.FieldLogger) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			slog := log.WithField("userID", uid)
+			slog.Info("updating role of team member")
+			resp, err := fc.fivetranClient.NewTeamUserMembershipModify().
+				TeamId(teamID).
+				UserId(uid).
+				Role(role).
+				Do(ctx)
+			if err != nil {
+				slog.WithField("response", resp).WithError(err).Error("error updating role of team member")
+				errch <- fmt.Errorf("%s: %w", uid, err)
+				return
+			}
+			slog.Info("team member role updated successfully")
+		}(id, log)
+	}
+
+	wg.Wait()
+	close(errch)
+
+	allErrors := make([]error, 0, len(userIDs))
+	for err := range errch {
+		allErrors = append(allErrors, err)
+	}
+	if len(allErrors) > 0 {
+		return fmt.Errorf("multiple errors occurred: %v", allErrors)
+	}
+	return nil
+}
+
 func (fc *FivetranClient) ReconcileGroupParams(
 	ctx context.Context, teamID string, groupParams structs.TeamParams) error {
 	// TODO: Implement group parameter reconciliation for Fivetran if applicable.
@@ -117,6 +117,20 @@ func (fc *FivetranClient) UpdateTeam(ctx context.Context, g *UpdateTeam) (*struc
 	}, nil
 }
 
+// RenameTeam renames the team keeping its role and description
+func (fc *FivetranClient) RenameTeam(ctx context.Context, teamID, newName string) (*structs.Team, error) {
+	team, err := fc.FetchTeamDetails(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return fc.UpdateTeam(ctx, &UpdateTeam{
+		ExistingTeamID: teamID,
+		NewTeamName:    newName,
+		NewRole:        team.Role,
+		NewDescription: team.Description,
+	})
+}
+
 func (fc *FivetranClient) FetchTeamDetails(ctx context.Context, teamID string) (*structs.Team, error) {
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
 		"service": "fivetran",
@@ -20,8 +20,18 @@ const (
 	AccountReviewerRole  = "Account Reviewer"
 	ConnectorAdminRole   = "Connector Administrator"
 	ConnectorCreatorRole = "Connector Creator"
+
+	// Team membership roles
+	TeamMemberRole  = "Team Member"
+	TeamManagerRole = "Team Manager"
+
+	// DefaultTeamRole is used for team members that don't have an explicit role
+	DefaultTeamRole = TeamMemberRole
 )
 
+// TeamRoles lists the team membership roles supported by the Fivetran backend
+var TeamRoles = []string{TeamMemberRole, TeamManagerRole}
+
 type UpdateTeam struct {
 	ExistingTeamID string
 	NewTeamName    string
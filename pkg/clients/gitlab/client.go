@@ -24,6 +24,7 @@ import (
 
 	"github.com/gojek/heimdall/v7"
 	"github.com/redhat-data-and-ai/usernaut/pkg/config"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 	"github.com/redhat-data-and-ai/usernaut/pkg/request"
 	"github.com/redhat-data-and-ai/usernaut/pkg/request/httpclient"
 	"github.com/redhat-data-and-ai/usernaut/pkg/utils"
@@ -50,7 +51,8 @@ func NewClient(
 	gitlabConfig.URL = baseUrl
 
 	// Gitlab SDK Client
-	client, err := gitlab.NewClient(gitlabConfig.Token, gitlab.WithBaseURL(baseUrl))
+	client, err := gitlab.NewClient(gitlabConfig.Token, gitlab.WithBaseURL(baseUrl),
+		gitlab.WithHTTPClient(&http.Client{Transport: metrics.InstrumentRoundTripper("gitlab", http.DefaultTransport)}))
 	if err != nil {
 		return nil, err
 	}
@@ -45,15 +45,17 @@ func (g *GitlabClient) FetchTeamMembersByTeamID(ctx context.Context, teamID stri
 			ID:       fmt.Sprintf("%d", m.ID),
 			Email:    m.PublicEmail,
 			UserName: m.Username,
+			Role:     roleForAccessLevel(m.AccessLevel),
 		}
 	}
 	return teamMembers, nil
 }
 
-func (g *GitlabClient) AddUserToTeam(ctx context.Context, teamID string, userIDs []string) error {
+func (g *GitlabClient) AddUserToTeam(ctx context.Context, teamID, role string, userIDs []string) error {
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
 		"service": "gitlab",
 		"teamID":  teamID,
+		"role":    role,
 		"userIDs": userIDs,
 	})
 	log.Info("adding users to team")
@@ -62,7 +64,10 @@ func (g *GitlabClient) AddUserToTeam(ctx context.Context, teamID string, userIDs
 		return nil
 	}
 
-	accessLevel := gitlab.DeveloperPermissions
+	accessLevel, err := accessLevelForRole(role)
+	if err != nil {
+		return err
+	}
 	for _, userID := range userIDs {
 		userIDInt, convErr := strconv.Atoi(userID)
 		if convErr != nil {
@@ -111,6 +116,119 @@ func (g *GitlabClient) RemoveUserFromTeam(ctx context.Context, teamID string, us
 	return nil
 }
 
+// UpdateUserRoleInTeam changes the access level of existing group members to match the given role
+func (g *GitlabClient) UpdateUserRoleInTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service": "gitlab",
+		"teamID":  teamID,
+		"role":    role,
+		"userIDs": userIDs,
+	})
+	log.Info("updating role of team members")
+
+	if g.ldapSync || len(userIDs) == 0 {
+		return nil
+	}
+
+	accessLevel, err := accessLevelForRole(role)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		userIDInt, convErr := strconv.Atoi(userID)
+		if convErr != nil {
+			return convErr
+		}
+		editMemberOpts := &gitlab.EditGroupMemberOptions{
+			AccessLevel: &accessLevel,
+		}
+		_, resp, err := g.gitlabClient.GroupMembers.EditGroupMember(teamID, userIDInt, editMemberOpts)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to update role of user %s in team %s, status: %s", userID, teamID, resp.Status)
+		}
+	}
+	return nil
+}
+
+// FetchNestedTeams returns the IDs of the groups the group is shared with
+func (g *GitlabClient) FetchNestedTeams(ctx context.Context, teamID string) ([]string, error) {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service": "gitlab",
+		"teamID":  teamID,
+	})
+	log.Info("fetching nested teams")
+
+	group, _, err := g.gitlabClient.Groups.GetGroup(teamID, &gitlab.GetGroupOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nested := make([]string, 0, len(group.SharedWithGroups))
+	for _, shared := range group.SharedWithGroups {
+		nested = append(nested, strconv.Itoa(shared.GroupID))
+	}
+	return nested, nil
+}
+
+// NestTeams shares the group with the given groups, their members get the default role in the group
+func (g *GitlabClient) NestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service":       "gitlab",
+		"teamID":        teamID,
+		"nestedTeamIDs": nestedTeamIDs,
+	})
+	log.Info("nesting teams")
+
+	accessLevel, err := accessLevelForRole(DefaultRole)
+	if err != nil {
+		return err
+	}
+	for _, nestedTeamID := range nestedTeamIDs {
+		groupID, convErr := strconv.Atoi(nestedTeamID)
+		if convErr != nil {
+			return convErr
+		}
+		_, resp, err := g.gitlabClient.Groups.ShareGroupWithGroup(teamID, &gitlab.ShareGroupWithGroupOptions{
+			GroupID:     &groupID,
+			GroupAccess: &accessLevel,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusCreated {
+			return fmt.Errorf("failed to share team %s with team %s, status: %s", teamID, nestedTeamID, resp.Status)
+		}
+	}
+	return nil
+}
+
+// UnnestTeams stops sharing the group with the given groups
+func (g *GitlabClient) UnnestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service":       "gitlab",
+		"teamID":        teamID,
+		"nestedTeamIDs": nestedTeamIDs,
+	})
+	log.Info("unnesting teams")
+
+	for _, nestedTeamID := range nestedTeamIDs {
+		groupID, convErr := strconv.Atoi(nestedTeamID)
+		if convErr != nil {
+			return convErr
+		}
+		resp, err := g.gitlabClient.Groups.UnshareGroupFromGroup(teamID, groupID)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusNoContent {
+			return fmt.Errorf("failed to unshare team %s from team %s, status: %s", teamID, nestedTeamID, resp.Status)
+		}
+	}
+	return nil
+}
+
 func (g *GitlabClient) ReconcileGroupParams(ctx context.Context, teamID string, groupParams structs.TeamParams) error {
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
 		"service":     "gitlab",
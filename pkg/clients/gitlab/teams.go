@@ -126,6 +126,33 @@ func (g *GitlabClient) CreateTeam(ctx context.Context, team *structs.Team) (*str
 	}, nil
 }
 
+// RenameTeam changes the name and path of the group, project shares and members are kept
+func (g *GitlabClient) RenameTeam(ctx context.Context, teamID, newName string) (*structs.Team, error) {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service": "gitlab",
+		"teamID":  teamID,
+		"newName": newName,
+	})
+	log.Info("renaming team")
+
+	group, response, err := g.gitlabClient.Groups.UpdateGroup(teamID, &gitlab.UpdateGroupOptions{
+		Name: &newName,
+		Path: &newName,
+	})
+	if err != nil {
+		statusCode := 0
+		if response != nil {
+			statusCode = response.StatusCode
+		}
+		return nil, fmt.Errorf("failed to rename team: %v, status code: %d", err, statusCode)
+	}
+
+	return &structs.Team{
+		ID:   fmt.Sprintf("%d", group.ID),
+		Name: group.Name,
+	}, nil
+}
+
 func (g *GitlabClient) DeleteTeamByID(ctx context.Context, teamID string) error {
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
 		"service": "gitlab",
@@ -17,14 +17,63 @@ limitations under the License.
 package gitlab
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gojek/heimdall/v7"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
+// Roles that can be assigned to members of a GitLab group
+const (
+	RoleGuest      = "guest"
+	RoleReporter   = "reporter"
+	RoleDeveloper  = "developer"
+	RoleMaintainer = "maintainer"
+	RoleOwner      = "owner"
+
+	// DefaultRole is used for members that don't have an explicit role
+	DefaultRole = RoleDeveloper
+)
+
 var (
 	ldapProvider = "ldapmain"
+
+	// Roles lists the roles supported by the GitLab backend
+	Roles = []string{RoleGuest, RoleReporter, RoleDeveloper, RoleMaintainer, RoleOwner}
+
+	roleAccessLevels = map[string]gitlab.AccessLevelValue{
+		RoleGuest:      gitlab.GuestPermissions,
+		RoleReporter:   gitlab.ReporterPermissions,
+		RoleDeveloper:  gitlab.DeveloperPermissions,
+		RoleMaintainer: gitlab.MaintainerPermissions,
+		RoleOwner:      gitlab.OwnerPermissions,
+	}
 )
 
+// accessLevelForRole returns the GitLab access level for the given role, an empty role maps to DefaultRole
+func accessLevelForRole(role string) (gitlab.AccessLevelValue, error) {
+	if role == "" {
+		role = DefaultRole
+	}
+	accessLevel, ok := roleAccessLevels[strings.ToLower(role)]
+	if !ok {
+		return 0, fmt.Errorf("unsupported gitlab role %q", role)
+	}
+	return accessLevel, nil
+}
+
+// roleForAccessLevel returns the role name for the given GitLab access level, or an empty string
+// if the access level doesn't correspond to one of the supported roles
+func roleForAccessLevel(accessLevel gitlab.AccessLevelValue) string {
+	for role, level := range roleAccessLevels {
+		if level == accessLevel {
+			return role
+		}
+	}
+	return ""
+}
+
 type GitlabClient struct {
 	gitlabClient    *gitlab.Client
 	gitlabConfig    *GitlabConfig
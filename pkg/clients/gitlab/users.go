@@ -141,6 +141,10 @@ func (g *GitlabClient) CreateUser(ctx context.Context, u *structs.User) (*struct
 	})
 	log.Info("creating user")
 
+	if u.IsServiceAccount() {
+		return g.createServiceAccountUser(ctx, u)
+	}
+
 	if g.ldapSync {
 		user, err := g.FetchUserDetails(ctx, u.UserName)
 		if err != nil {
@@ -175,6 +179,33 @@ func (g *GitlabClient) CreateUser(ctx context.Context, u *structs.User) (*struct
 	return userDetails(user), nil
 }
 
+// createServiceAccountUser creates a bot user in GitLab, service accounts are never synced
+// from LDAP so they are created even when ldapSync is enabled
+func (g *GitlabClient) createServiceAccountUser(ctx context.Context, u *structs.User) (*structs.User, error) {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service":         "gitlab",
+		"service_account": u.UserName,
+	})
+	log.Info("creating service account user")
+
+	opts := &gitlab.CreateServiceAccountUserOptions{
+		Name:     &u.UserName,
+		Username: &u.UserName,
+	}
+	if u.Email != "" {
+		opts.Email = &u.Email
+	}
+	user, _, err := g.gitlabClient.Users.CreateServiceAccountUser(opts)
+	if err != nil {
+		log.WithError(err).Error("failed to create service account user")
+		return nil, err
+	}
+
+	serviceAccount := userDetails(user)
+	serviceAccount.ServiceAccount = true
+	return serviceAccount, nil
+}
+
 func (g *GitlabClient) DeleteUser(ctx context.Context, userID string) error {
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
 		"service": "gitlab",
@@ -9,6 +9,7 @@ import (
 
 	ldapv3 "github.com/go-ldap/ldap/v3"
 	v1alpha1 "github.com/redhat-data-and-ai/usernaut/api/v1alpha1"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 )
 
 type LDAP struct {
@@ -53,6 +54,7 @@ type LDAPClient interface {
 	GetQueryMembers(ctx context.Context, query string) ([]string, error)
 	BuildLDAPQueryFromSpec(ctx context.Context, query *v1alpha1.LDAPQuery) (string, error)
 	GetUserLDAPDataByEmail(ctx context.Context, email string) (map[string]interface{}, error)
+	GetGroupMembers(ctx context.Context, groupDN string) ([]string, error)
 }
 
 // InitLdap initializes a connection to the LDAP server using the provided configuration.
@@ -81,6 +83,15 @@ func InitLdap(ldapConfig LDAP) (LDAPClient, error) {
 	}, nil
 }
 
+// search runs the search request on the connection and records its latency under the operation.
+func search(conn LDAPConnClient, operation string,
+	searchRequest *ldapv3.SearchRequest) (*ldapv3.SearchResult, error) {
+	start := time.Now()
+	resp, err := conn.Search(searchRequest)
+	metrics.ObserveLDAPSearch(operation, time.Since(start), err)
+	return resp, err
+}
+
 // getConn returns the underlying LDAP connection.
 func (l *LDAPConn) getConn() LDAPConnClient {
 	if l.conn != nil && l.conn.IsClosing() {
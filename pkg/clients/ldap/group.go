@@ -0,0 +1,156 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+)
+
+var (
+	ErrNoGroupFound = errors.New("no LDAP entries found for group")
+)
+
+// groupMemberAttributes are the attributes holding the members of the supported LDAP group
+// object classes: member (groupOfNames), uniqueMember (groupOfUniqueNames) and memberUid (posixGroup)
+var groupMemberAttributes = []string{"member", "uniqueMember", "memberUid"}
+
+// groupEntryAttributes are the attributes read from the entries of the member DNs: the member
+// attributes of a nested group, or the uid of a user whose DN is not keyed by uid (e.g. cn=John Doe)
+var groupEntryAttributes = append([]string{"uid"}, groupMemberAttributes...)
+
+// GetGroupMembers returns the uids of the members of the LDAP group with the given DN.
+// Member DNs without a uid RDN are read from LDAP: an entry with a uid and no members is a user
+// whose DN is keyed by another attribute, any other entry is treated as a nested LDAP group and
+// resolved recursively. Nested groups that no longer exist are skipped. Each group is resolved
+// once, so cyclic nesting is tolerated.
+func (l *LDAPConn) GetGroupMembers(ctx context.Context, groupDN string) ([]string, error) {
+	// Do not call LDAP if the request context is already cancelled or its deadline has passed
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	log := logger.Logger(ctx).WithField("groupDN", groupDN)
+	log.Info("fetching LDAP group members")
+
+	seenUIDs := make(map[string]struct{})
+	members := make([]string, 0)
+	visited := map[string]struct{}{strings.ToLower(groupDN): {}}
+	pending := []string{groupDN}
+
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		currentDN := pending[0]
+		pending = pending[1:]
+
+		entry, err := l.searchGroup(currentDN)
+		if err != nil {
+			if errors.Is(err, ErrNoGroupFound) && currentDN != groupDN {
+				log.WithField("nestedGroupDN", currentDN).Warn("nested LDAP group not found, skipping")
+				continue
+			}
+			log.WithError(err).WithField("currentDN", currentDN).Error("failed to search LDAP group")
+			return nil, err
+		}
+
+		if currentDN != groupDN && !hasMembers(entry) {
+			uid := entry.GetAttributeValue("uid")
+			if uid == "" {
+				log.WithField("memberDN", currentDN).Warn("LDAP group member has neither a uid nor members, skipping")
+				continue
+			}
+			// a user whose DN is not keyed by uid
+			if _, ok := seenUIDs[uid]; !ok {
+				seenUIDs[uid] = struct{}{}
+				members = append(members, uid)
+			}
+			continue
+		}
+
+		uids, nestedGroups := splitGroupMembers(log, entry)
+		for _, uid := range uids {
+			if _, ok := seenUIDs[uid]; !ok {
+				seenUIDs[uid] = struct{}{}
+				members = append(members, uid)
+			}
+		}
+		for _, nestedDN := range nestedGroups {
+			key := strings.ToLower(nestedDN)
+			if _, ok := visited[key]; ok {
+				continue
+			}
+			visited[key] = struct{}{}
+			pending = append(pending, nestedDN)
+		}
+	}
+
+	log.WithField("members_count", len(members)).WithField("groups_count", len(visited)).
+		Info("fetched LDAP group members")
+	return members, nil
+}
+
+// searchGroup reads the member attributes and the uid of the LDAP entry with the given DN.
+func (l *LDAPConn) searchGroup(groupDN string) (*ldap.Entry, error) {
+	searchRequest := ldap.NewSearchRequest(
+		groupDN,
+		ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)",
+		groupEntryAttributes,
+		nil,
+	)
+
+	conn := l.getConn()
+	if conn == nil {
+		return nil, errors.New("LDAP connection is nil")
+	}
+	resp, err := search(conn, "group", searchRequest)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, fmt.Errorf("%w: %s", ErrNoGroupFound, groupDN)
+		}
+		return nil, err
+	}
+	if len(resp.Entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoGroupFound, groupDN)
+	}
+	return resp.Entries[0], nil
+}
+
+// hasMembers reports whether the entry has any of the member attributes of a group
+func hasMembers(entry *ldap.Entry) bool {
+	for _, attr := range groupMemberAttributes {
+		if len(entry.GetAttributeValues(attr)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// splitGroupMembers splits the members of a group entry into user uids and the member DNs to read
+// from LDAP. A member DN is a user when it has a uid RDN, any other DN is read to tell whether it
+// is a user or a nested group.
+func splitGroupMembers(log *logrus.Entry, entry *ldap.Entry) ([]string, []string) {
+	uids := append([]string{}, entry.GetAttributeValues("memberUid")...)
+	var nestedGroups []string
+	for _, attr := range []string{"member", "uniqueMember"} {
+		for _, value := range entry.GetAttributeValues(attr) {
+			dn, err := ldap.ParseDN(value)
+			if err != nil {
+				log.WithError(err).WithField("memberDN", value).Warn("invalid LDAP group member DN, skipping")
+				continue
+			}
+			if uid := parseUIDFromDN(dn); uid != "" {
+				uids = append(uids, uid)
+				continue
+			}
+			nestedGroups = append(nestedGroups, value)
+		}
+	}
+	return uids, nestedGroups
+}
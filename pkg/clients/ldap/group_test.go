@@ -0,0 +1,137 @@
+package ldap
+
+import (
+	"errors"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *LDAPTestSuite) TestGetGroupMembers() {
+	assertions := assert.New(suite.T())
+
+	groups := map[string]*ldap.Entry{
+		"cn=team,ou=groups,dc=example,dc=com": {
+			DN: "cn=team,ou=groups,dc=example,dc=com",
+			Attributes: []*ldap.EntryAttribute{
+				{Name: "member", Values: []string{
+					"uid=alice,ou=users,dc=example,dc=com",
+					"cn=subteam,ou=groups,dc=example,dc=com",
+					"cn=gone,ou=groups,dc=example,dc=com",
+				}},
+			},
+		},
+		"cn=subteam,ou=groups,dc=example,dc=com": {
+			DN: "cn=subteam,ou=groups,dc=example,dc=com",
+			Attributes: []*ldap.EntryAttribute{
+				{Name: "uniqueMember", Values: []string{
+					"uid=bob,ou=users,dc=example,dc=com",
+					"uid=alice,ou=users,dc=example,dc=com",
+					// cyclic nesting back to the parent group
+					"CN=team,ou=groups,dc=example,dc=com",
+				}},
+				{Name: "memberUid", Values: []string{"carol"}},
+			},
+		},
+	}
+
+	var searchedDNs []string
+	suite.ldapClient.EXPECT().IsClosing().Return(false).AnyTimes()
+	suite.ldapClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			searchedDNs = append(searchedDNs, req.BaseDN)
+			assertions.Equal(ldap.ScopeBaseObject, req.Scope)
+			assertions.Equal(groupEntryAttributes, req.Attributes)
+			entry, ok := groups[req.BaseDN]
+			if !ok {
+				return nil, ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))
+			}
+			return &ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil
+		}).
+		Times(3)
+
+	ldapConn := &LDAPConn{
+		conn:       suite.ldapClient,
+		baseUserDN: "ou=users,dc=example,dc=com",
+		server:     "ldap://ldap.com:389",
+	}
+
+	members, err := ldapConn.GetGroupMembers(suite.ctx, "cn=team,ou=groups,dc=example,dc=com")
+
+	assertions.NoError(err)
+	assertions.Equal([]string{"alice", "carol", "bob"}, members)
+	assertions.Equal([]string{
+		"cn=team,ou=groups,dc=example,dc=com",
+		"cn=subteam,ou=groups,dc=example,dc=com",
+		"cn=gone,ou=groups,dc=example,dc=com",
+	}, searchedDNs)
+}
+
+func (suite *LDAPTestSuite) TestGetGroupMembers_GroupNotFound() {
+	assertions := assert.New(suite.T())
+
+	suite.ldapClient.EXPECT().IsClosing().Return(false).Times(1)
+	suite.ldapClient.EXPECT().
+		Search(gomock.Any()).
+		Return(nil, ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))).
+		Times(1)
+
+	ldapConn := &LDAPConn{
+		conn:       suite.ldapClient,
+		baseUserDN: "ou=users,dc=example,dc=com",
+		server:     "ldap://ldap.com:389",
+	}
+
+	members, err := ldapConn.GetGroupMembers(suite.ctx, "cn=missing,ou=groups,dc=example,dc=com")
+
+	assertions.ErrorIs(err, ErrNoGroupFound)
+	assertions.Nil(members)
+}
+
+func (suite *LDAPTestSuite) TestGetGroupMembers_MembersNotKeyedByUID() {
+	assertions := assert.New(suite.T())
+
+	entries := map[string]*ldap.Entry{
+		"cn=team,ou=groups,dc=example,dc=com": {
+			DN: "cn=team,ou=groups,dc=example,dc=com",
+			Attributes: []*ldap.EntryAttribute{
+				{Name: "member", Values: []string{
+					"uid=alice,ou=users,dc=example,dc=com",
+					"cn=John Doe,ou=users,dc=example,dc=com",
+					"cn=printer,ou=devices,dc=example,dc=com",
+					"not a DN",
+				}},
+			},
+		},
+		// a user keyed by cn is read to get its uid
+		"cn=John Doe,ou=users,dc=example,dc=com": {
+			DN:         "cn=John Doe,ou=users,dc=example,dc=com",
+			Attributes: []*ldap.EntryAttribute{{Name: "uid", Values: []string{"jdoe"}}},
+		},
+		// neither a user nor a group
+		"cn=printer,ou=devices,dc=example,dc=com": {
+			DN: "cn=printer,ou=devices,dc=example,dc=com",
+		},
+	}
+
+	suite.ldapClient.EXPECT().IsClosing().Return(false).AnyTimes()
+	suite.ldapClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			return &ldap.SearchResult{Entries: []*ldap.Entry{entries[req.BaseDN]}}, nil
+		}).
+		Times(3)
+
+	ldapConn := &LDAPConn{
+		conn:       suite.ldapClient,
+		baseUserDN: "ou=users,dc=example,dc=com",
+		server:     "ldap://ldap.com:389",
+	}
+
+	members, err := ldapConn.GetGroupMembers(suite.ctx, "cn=team,ou=groups,dc=example,dc=com")
+
+	assertions.NoError(err)
+	assertions.Equal([]string{"alice", "jdoe"}, members)
+}
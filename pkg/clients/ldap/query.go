@@ -38,7 +38,7 @@ func (l *LDAPConn) GetQueryMembers(ctx context.Context, query string) ([]string,
 		log.Error("LDAP connection is nil, cannot perform search")
 		return nil, errors.New("LDAP connection is nil")
 	}
-	resp, err := conn.Search(searchRequest)
+	resp, err := search(conn, "query", searchRequest)
 	if err != nil {
 		log.WithError(err).Error("failed to search LDAP for query members")
 		return nil, err
@@ -80,10 +80,17 @@ func (l *LDAPConn) BuildLDAPQueryFromSpec(ctx context.Context, query *v1alpha1.L
 	log := logger.Logger(ctx).WithField("build_ldap_query", "spec")
 	log.WithField("query", query).Info("building LDAP query from spec")
 
+	return BuildQueryFromSpec(query, l.baseUserDN)
+}
+
+// BuildQueryFromSpec builds the LDAP search filter for the given spec query without
+// requiring an LDAP connection, so callers such as the admission webhook can validate
+// a query up front using the same rules applied during reconciliation.
+func BuildQueryFromSpec(query *v1alpha1.LDAPQuery, baseUserDN string) (string, error) {
 	if query == nil {
 		return "", errors.New("ldap query is nil")
 	}
-	return buildQueryFromSpec(query, l.baseUserDN, 1)
+	return buildQueryFromSpec(query, baseUserDN, 1)
 }
 
 func buildQueryFromSpec(query *v1alpha1.LDAPQuery, baseUserDN string, depth int) (string, error) {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-ldap/ldap/v3"
 	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 )
 
 // bulkLDAPBatchSize caps OR-filter batch size
@@ -40,7 +41,7 @@ func (l *LDAPConn) executeSearch(ctx context.Context,
 		return nil, errors.New("LDAP connection is nil")
 	}
 
-	resp, err := conn.Search(searchRequest)
+	resp, err := search(conn, "user", searchRequest)
 	if err != nil {
 		// Handle LDAP "No Such Object" error (code 32)
 		if ldapErr, ok := err.(*ldap.Error); ok {
@@ -153,7 +154,8 @@ func (l *LDAPConn) GetBulkUserLDAPData(
 			return result, errors.New("LDAP connection is nil")
 		}
 
-		resp, err := conn.Search(searchRequest)
+		metrics.LDAPBulkBatches.Inc()
+		resp, err := search(conn, "bulk_users", searchRequest)
 		if err != nil {
 			log.WithError(err).
 				WithField("batch_start", batchStart).
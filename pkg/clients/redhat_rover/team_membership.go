@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	ot "github.com/opentracing/opentracing-go"
 
@@ -29,7 +30,9 @@ import (
 	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
 )
 
-// Fetch all the members and owners of a team by teamID ignoring the serviceaccount members
+// Fetch all the members and owners of a team by teamID ignoring the serviceaccount
+// used by usernaut itself, other serviceaccount members are returned with a prefixed ID
+// and flagged so that the ones not created by usernaut are left alone in the team
 func (rC *RoverClient) FetchTeamMembersByTeamID(ctx context.Context, teamID string) (map[string]*structs.User, error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "backend.redhatrover.FetchTeamMembersByTeamID")
 	defer span.Finish()
@@ -37,38 +40,253 @@ func (rC *RoverClient) FetchTeamMembersByTeamID(ctx context.Context, teamID stri
 	log := logger.Logger(ctx)
 	log.Info("Fetching team member details from rover group")
 
+	roverGroup, err := rC.fetchGroup(ctx, teamID, "backend.redhatrover.FetchTeamMembersByTeamID")
+	if err != nil {
+		log.WithError(err).Error("failed to fetch rover group members")
+		return nil, err
+	}
+
+	owners := make(map[string]struct{}, len(roverGroup.Owners))
+	for _, owner := range roverGroup.Owners {
+		if rC.isManagedMember(owner) {
+			owners[memberID(owner)] = struct{}{}
+		}
+	}
+
+	members := make(map[string]*structs.User)
+	for _, member := range roverGroup.Members {
+		if !rC.isManagedMember(member) {
+			continue
+		}
+		user := &structs.User{
+			ID:             memberID(member),
+			Role:           RoleMember,
+			ServiceAccount: member.Type == MemberTypeServiceAccount,
+		}
+		if _, isOwner := owners[user.ID]; isOwner {
+			user.Role = RoleOwner
+		}
+		members[user.ID] = user
+	}
+
+	return members, nil
+}
+
+// isManagedMember reports whether the rover group member can be managed by usernaut,
+// that is any user or any serviceaccount other than the one usernaut runs as
+func (rC *RoverClient) isManagedMember(m Member) bool {
+	switch m.Type {
+	case MemberTypeUser:
+		return true
+	case MemberTypeServiceAccount:
+		return m.ID != rC.serviceAccountName
+	default:
+		return false
+	}
+}
+
+// fetchGroup fetches the rover group definition by teamID
+func (rC *RoverClient) fetchGroup(ctx context.Context, teamID, spanName string) (*RoverGroup, error) {
 	resp, respCode, err := rC.sendRequest(ctx, rC.url+"/v1/groups/"+teamID,
 		http.MethodGet, nil,
-		headers, "backend.redhatrover.FetchTeamMembersByTeamID")
-
+		headers, spanName)
 	if err != nil {
-		log.WithError(err).Error("failed to fetch rover group members")
 		return nil, err
 	}
 
 	if respCode != http.StatusOK {
-		log.Error("failed to fetch rover group members")
 		return nil, errors.New("failed to fetch rover group members with response code: " + http.StatusText(respCode))
 	}
 
 	var roverGroup RoverGroup
 	if err := json.Unmarshal(resp, &roverGroup); err != nil {
-		log.WithError(err).Error("failed to decode rover group response")
 		return nil, errors.New("failed to decode rover group response: " + err.Error())
 	}
+	return &roverGroup, nil
+}
 
-	members := make(map[string]*structs.User)
-	for _, member := range roverGroup.Members {
-		if member.Type != MemberTypeUser {
-			continue // Only process user type members
+// updateOwners adds and removes owners of a rover group, the serviceaccount used by usernaut is always kept
+func (rC *RoverClient) updateOwners(ctx context.Context, spanName, teamID string, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	log := logger.Logger(ctx).WithField("teamID", teamID)
+
+	roverGroup, err := rC.fetchGroup(ctx, teamID, spanName)
+	if err != nil {
+		log.WithError(err).Error("failed to fetch rover group for owner update")
+		return err
+	}
+
+	toRemove := make(map[string]struct{}, len(remove))
+	for _, id := range remove {
+		toRemove[id] = struct{}{}
+	}
+	changed := false
+	owners := make([]Member, 0, len(roverGroup.Owners)+len(add))
+	existing := make(map[string]struct{}, len(roverGroup.Owners))
+	for _, owner := range roverGroup.Owners {
+		if _, drop := toRemove[memberID(owner)]; drop && rC.isManagedMember(owner) {
+			changed = true
+			continue
 		}
-		user := &structs.User{
-			ID: member.ID,
+		existing[memberID(owner)] = struct{}{}
+		owners = append(owners, owner)
+	}
+	for _, id := range add {
+		if _, ok := existing[id]; ok {
+			continue
 		}
-		members[user.ID] = user
+		existing[id] = struct{}{}
+		owners = append(owners, memberFromID(id))
+		changed = true
 	}
+	if !changed {
+		return nil
+	}
+	roverGroup.Owners = owners
 
-	return members, nil
+	resp, respCode, err := rC.sendRequest(ctx, rC.url+"/v1/groups/"+teamID,
+		http.MethodPut, roverGroup,
+		headers, spanName)
+	if err != nil {
+		log.WithError(err).Error("failed to update rover group owners")
+		return err
+	}
+	if respCode != http.StatusOK {
+		log.Error("failed to update rover group owners")
+		return fmt.Errorf("failed to update rover group owners: %s", string(resp))
+	}
+
+	log.WithField("owners_added", len(add)).WithField("owners_removed", len(remove)).
+		Info("rover group owners updated")
+	return nil
+}
+
+// SetTeamContact sets the contact list of the rover group
+func (rC *RoverClient) SetTeamContact(ctx context.Context, teamID, contact string) error {
+	span, ctx := ot.StartSpanFromContext(ctx, "backend.redhatrover.SetTeamContact")
+	defer span.Finish()
+	log := logger.Logger(ctx).WithField("teamID", teamID)
+
+	roverGroup, err := rC.fetchGroup(ctx, teamID, "backend.redhatrover.SetTeamContact")
+	if err != nil {
+		log.WithError(err).Error("failed to fetch rover group for contact update")
+		return err
+	}
+	if roverGroup.ContactList == contact {
+		return nil
+	}
+	roverGroup.ContactList = contact
+
+	resp, respCode, err := rC.sendRequest(ctx, rC.url+"/v1/groups/"+teamID,
+		http.MethodPut, roverGroup,
+		headers, "backend.redhatrover.SetTeamContact")
+	if err != nil {
+		log.WithError(err).Error("failed to update rover group contact")
+		return err
+	}
+	if respCode != http.StatusOK {
+		log.Error("failed to update rover group contact")
+		return fmt.Errorf("failed to update rover group contact: %s", string(resp))
+	}
+
+	log.Info("rover group contact updated")
+	return nil
+}
+
+// FetchNestedTeams returns the names of the rover groups included in the rover group
+func (rC *RoverClient) FetchNestedTeams(ctx context.Context, teamID string) ([]string, error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "backend.redhatrover.FetchNestedTeams")
+	defer span.Finish()
+	log := logger.Logger(ctx).WithField("teamID", teamID)
+
+	roverGroup, err := rC.fetchGroup(ctx, teamID, "backend.redhatrover.FetchNestedTeams")
+	if err != nil {
+		log.WithError(err).Error("failed to fetch rover group inclusions")
+		return nil, err
+	}
+
+	nested := make([]string, 0, len(roverGroup.RoverGroupInclusions))
+	for _, inclusion := range roverGroup.RoverGroupInclusions {
+		if inclusion.Type == MemberTypeRoverGroup {
+			nested = append(nested, inclusion.ID)
+		}
+	}
+	return nested, nil
+}
+
+// NestTeams includes the given rover groups in the rover group
+func (rC *RoverClient) NestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	return rC.updateInclusions(ctx, "backend.redhatrover.NestTeams", teamID, nestedTeamIDs, nil)
+}
+
+// UnnestTeams removes the given rover groups from the inclusions of the rover group
+func (rC *RoverClient) UnnestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	return rC.updateInclusions(ctx, "backend.redhatrover.UnnestTeams", teamID, nil, nestedTeamIDs)
+}
+
+// updateInclusions adds and removes rover groups to and from the inclusions of a rover group,
+// the other inclusions are kept
+func (rC *RoverClient) updateInclusions(ctx context.Context, spanName, teamID string, add, remove []string) error {
+	span, ctx := ot.StartSpanFromContext(ctx, spanName)
+	defer span.Finish()
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	log := logger.Logger(ctx).WithField("teamID", teamID)
+
+	roverGroup, err := rC.fetchGroup(ctx, teamID, spanName)
+	if err != nil {
+		log.WithError(err).Error("failed to fetch rover group for inclusion update")
+		return err
+	}
+
+	toRemove := make(map[string]struct{}, len(remove))
+	for _, id := range remove {
+		toRemove[id] = struct{}{}
+	}
+	changed := false
+	inclusions := make([]Member, 0, len(roverGroup.RoverGroupInclusions)+len(add))
+	existing := make(map[string]struct{}, len(roverGroup.RoverGroupInclusions))
+	for _, inclusion := range roverGroup.RoverGroupInclusions {
+		if inclusion.Type == MemberTypeRoverGroup {
+			if _, drop := toRemove[inclusion.ID]; drop {
+				changed = true
+				continue
+			}
+			existing[inclusion.ID] = struct{}{}
+		}
+		inclusions = append(inclusions, inclusion)
+	}
+	for _, id := range add {
+		if _, ok := existing[id]; ok {
+			continue
+		}
+		existing[id] = struct{}{}
+		inclusions = append(inclusions, Member{ID: id, Type: MemberTypeRoverGroup})
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	roverGroup.RoverGroupInclusions = inclusions
+
+	resp, respCode, err := rC.sendRequest(ctx, rC.url+"/v1/groups/"+teamID,
+		http.MethodPut, roverGroup,
+		headers, spanName)
+	if err != nil {
+		log.WithError(err).Error("failed to update rover group inclusions")
+		return err
+	}
+	if respCode != http.StatusOK {
+		log.Error("failed to update rover group inclusions")
+		return fmt.Errorf("failed to update rover group inclusions: %s", string(resp))
+	}
+
+	log.WithField("inclusions_added", len(add)).WithField("inclusions_removed", len(remove)).
+		Info("rover group inclusions updated")
+	return nil
 }
 
 const roverBatchSize = 500
@@ -106,7 +324,7 @@ func (rC *RoverClient) modify(
 
 		members := make([]Member, 0, len(batch))
 		for _, id := range batch {
-			members = append(members, Member{ID: id, Type: MemberTypeUser})
+			members = append(members, memberFromID(id))
 		}
 
 		var req MemberModRequest
@@ -140,14 +358,46 @@ func (rC *RoverClient) modify(
 	return nil
 }
 
-// AddUserToTeam adds a user to a team in Rover by teamID and userID
-func (rC *RoverClient) AddUserToTeam(ctx context.Context, teamID string, userIDs []string) error {
-	return rC.modify(ctx, "backend.redhatrover.AddUserToTeam", "add", teamID, userIDs)
+// AddUserToTeam adds a user to a team in Rover by teamID and userID.
+// Users added with the owner role are also made owners of the group.
+func (rC *RoverClient) AddUserToTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	if err := validateRole(role); err != nil {
+		return err
+	}
+	if err := rC.modify(ctx, "backend.redhatrover.AddUserToTeam", "add", teamID, userIDs); err != nil {
+		return err
+	}
+	if strings.EqualFold(role, RoleOwner) {
+		return rC.updateOwners(ctx, "backend.redhatrover.AddUserToTeam", teamID, userIDs, nil)
+	}
+	return nil
 }
 
-// RemoveUserFromTeam removes a user from a team in Rover by teamID and userID
+// RemoveUserFromTeam removes a user from a team in Rover by teamID and userID.
+// The users are removed from the group owners as well.
 func (rC *RoverClient) RemoveUserFromTeam(ctx context.Context, teamID string, userIDs []string) error {
-	return rC.modify(ctx, "backend.redhatrover.RemoveUserFromTeam", "remove", teamID, userIDs)
+	if err := rC.modify(ctx, "backend.redhatrover.RemoveUserFromTeam", "remove", teamID, userIDs); err != nil {
+		return err
+	}
+	return rC.updateOwners(ctx, "backend.redhatrover.RemoveUserFromTeam", teamID, nil, userIDs)
+}
+
+// UpdateUserRoleInTeam grants or revokes group ownership of existing members to match the given role
+func (rC *RoverClient) UpdateUserRoleInTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	if err := validateRole(role); err != nil {
+		return err
+	}
+	if strings.EqualFold(role, RoleOwner) {
+		return rC.updateOwners(ctx, "backend.redhatrover.UpdateUserRoleInTeam", teamID, userIDs, nil)
+	}
+	return rC.updateOwners(ctx, "backend.redhatrover.UpdateUserRoleInTeam", teamID, nil, userIDs)
+}
+
+func validateRole(role string) error {
+	if role == "" || strings.EqualFold(role, RoleMember) || strings.EqualFold(role, RoleOwner) {
+		return nil
+	}
+	return fmt.Errorf("unsupported rover role %q", role)
 }
 
 func (rC *RoverClient) ReconcileGroupParams(ctx context.Context, teamID string, groupParams structs.TeamParams) error {
@@ -16,15 +16,30 @@ limitations under the License.
 
 package redhatrover
 
-import "github.com/redhat-data-and-ai/usernaut/pkg/common/constants"
+import (
+	"strings"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/common/constants"
+)
 
 const (
 	MemberApprovalTypeSelfService = "self-service"
 	MemberTypeUser                = "user"
 	MemberTypeServiceAccount      = "serviceaccount"
+	MemberTypeRoverGroup          = "rovergroup"
 	defaultContactEmail           = "devnull@redhat.com"
+
+	// Roles that can be assigned to members of a Rover group
+	RoleMember = "member"
+	RoleOwner  = "owner"
+
+	// DefaultRole is used for members that don't have an explicit role
+	DefaultRole = RoleMember
 )
 
+// Roles lists the roles supported by the Rover backend
+var Roles = []string{RoleMember, RoleOwner}
+
 var (
 	headers = map[string]string{constants.ContentTypeHeaderKey: "application/json"}
 )
@@ -34,6 +49,26 @@ type Member struct {
 	ID   string `json:"id"`
 }
 
+// serviceAccountIDPrefix marks the IDs of service account members so they
+// can be told apart from user members sharing the same name
+const serviceAccountIDPrefix = MemberTypeServiceAccount + ":"
+
+// memberFromID returns the rover member referenced by an ID returned by the client
+func memberFromID(id string) Member {
+	if name, ok := strings.CutPrefix(id, serviceAccountIDPrefix); ok {
+		return Member{ID: name, Type: MemberTypeServiceAccount}
+	}
+	return Member{ID: id, Type: MemberTypeUser}
+}
+
+// memberID returns the ID used by the client for the given rover member
+func memberID(m Member) string {
+	if m.Type == MemberTypeServiceAccount {
+		return serviceAccountIDPrefix + m.ID
+	}
+	return m.ID
+}
+
 type RoverGroup struct {
 	Name                  string   `json:"name"`
 	Description           string   `json:"description"`
@@ -73,3 +73,13 @@ func TestMemberModRequestGetters(t *testing.T) {
 	assert.Equal(t, []Member{add}, modReq.GetAdditions())
 	assert.Equal(t, []Member{del}, modReq.GetDeletions())
 }
+
+func TestMemberIDRoundTrip(t *testing.T) {
+	user := Member{Type: MemberTypeUser, ID: "alice"}
+	serviceAccount := Member{Type: MemberTypeServiceAccount, ID: "ci-bot"}
+
+	assert.Equal(t, "alice", memberID(user))
+	assert.Equal(t, "serviceaccount:ci-bot", memberID(serviceAccount))
+	assert.Equal(t, user, memberFromID(memberID(user)))
+	assert.Equal(t, serviceAccount, memberFromID(memberID(serviceAccount)))
+}
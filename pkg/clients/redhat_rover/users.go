@@ -35,6 +35,12 @@ func (rC *RoverClient) FetchUserDetails(ctx context.Context, userID string) (*st
 func (rC *RoverClient) CreateUser(ctx context.Context, u *structs.User) (*structs.User, error) {
 	// as rover is the LDAP, no need to create user here
 	// field UserName is used as ID in Rover
+	if u.IsServiceAccount() {
+		return &structs.User{
+			ID:             serviceAccountIDPrefix + u.UserName,
+			ServiceAccount: true,
+		}, nil
+	}
 	return &structs.User{
 		ID: u.UserName,
 	}, nil
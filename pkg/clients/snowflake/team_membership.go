@@ -74,8 +74,9 @@ func (c *SnowflakeClient) processGrantsPage(resp []byte, members map[string]*str
 	return nil
 }
 
-// AddUserToTeam adds users to a team (grants role to users)
-func (c *SnowflakeClient) AddUserToTeam(ctx context.Context, teamID string, userIDs []string) error {
+// AddUserToTeam adds users to a team (grants role to users).
+// Snowflake team membership is a role grant, so member roles are not supported and role is ignored.
+func (c *SnowflakeClient) AddUserToTeam(ctx context.Context, teamID, role string, userIDs []string) error {
 	return c.modifyTeamMembership(ctx, teamID, userIDs, "grants", "add",
 		[]int{http.StatusOK, http.StatusCreated})
 }
@@ -86,6 +87,11 @@ func (c *SnowflakeClient) RemoveUserFromTeam(ctx context.Context, teamID string,
 		[]int{http.StatusOK, http.StatusNoContent})
 }
 
+// UpdateUserRoleInTeam is a no-op as Snowflake has no per-member roles within a team
+func (c *SnowflakeClient) UpdateUserRoleInTeam(ctx context.Context, teamID, role string, userIDs []string) error {
+	return nil
+}
+
 func (c *SnowflakeClient) modifyTeamMembership(ctx context.Context, teamID string,
 	userIDs []string, action, verb string, successStatuses []int) error {
 	log := logger.Logger(ctx).WithFields(logrus.Fields{
@@ -117,7 +123,78 @@ func (c *SnowflakeClient) modifyTeamMembership(ctx context.Context, teamID strin
 	return errors.Join(errs...)
 }
 
-// makeRoleRequest sends a role grant/revoke request for a user
+// FetchNestedTeams returns the roles the team role is granted to, their users inherit the team role
+func (c *SnowflakeClient) FetchNestedTeams(ctx context.Context, teamID string) ([]string, error) {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service": "snowflake",
+		"teamID":  teamID,
+	})
+	log.Info("fetching nested teams")
+
+	nested := make([]string, 0)
+	endpoint := fmt.Sprintf("/api/v2/roles/%s/grants-of", teamID)
+	err := c.fetchAllWithPagination(ctx, endpoint, func(resp []byte) error {
+		var grants []SnowflakeGrant
+		if err := json.Unmarshal(resp, &grants); err != nil {
+			return fmt.Errorf("error unmarshaling grants response: %w", err)
+		}
+		for _, grant := range grants {
+			if grant.GrantedTo == "ROLE" && grant.GranteeName != "" {
+				nested = append(nested, strings.ToLower(grant.GranteeName))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("error fetching nested teams")
+		return nil, err
+	}
+	return nested, nil
+}
+
+// NestTeams grants the team role to the given roles, so that their users inherit it
+func (c *SnowflakeClient) NestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	return c.modifyNestedTeams(ctx, teamID, nestedTeamIDs, "grants", "nest",
+		[]int{http.StatusOK, http.StatusCreated})
+}
+
+// UnnestTeams revokes the team role from the given roles
+func (c *SnowflakeClient) UnnestTeams(ctx context.Context, teamID string, nestedTeamIDs []string) error {
+	return c.modifyNestedTeams(ctx, teamID, nestedTeamIDs, "grants:revoke", "unnest",
+		[]int{http.StatusOK, http.StatusNoContent})
+}
+
+func (c *SnowflakeClient) modifyNestedTeams(ctx context.Context, teamID string,
+	nestedTeamIDs []string, action, verb string, successStatuses []int) error {
+	log := logger.Logger(ctx).WithFields(logrus.Fields{
+		"service":           "snowflake",
+		"teamID":            teamID,
+		"nested_team_count": len(nestedTeamIDs),
+	})
+	log.Infof("%sing teams", verb)
+
+	var errs []error
+	for _, nestedTeamID := range nestedTeamIDs {
+		endpoint := fmt.Sprintf("/api/v2/roles/%s/%s", nestedTeamID, action)
+
+		resp, status, err := c.makeRoleRequest(ctx, teamID, endpoint)
+		if err != nil {
+			log.WithError(err).WithField("nested_team", nestedTeamID).Errorf("failed to %s team", verb)
+			errs = append(errs, fmt.Errorf("failed to %s team %s in team %s: %w", verb, nestedTeamID, teamID, err))
+			continue
+		}
+
+		if !slices.Contains(successStatuses, status) {
+			log.WithFields(logrus.Fields{"nested_team": nestedTeamID, "status": status}).Errorf("failed to %s team", verb)
+			errs = append(errs, fmt.Errorf("failed to %s team %s in team %s, status: %s, body: %s",
+				verb, nestedTeamID, teamID, http.StatusText(status), string(resp)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// makeRoleRequest sends a role grant/revoke request for a user or a role
 func (c *SnowflakeClient) makeRoleRequest(ctx context.Context, teamID, endpoint string) ([]byte, int, error) {
 	payload := map[string]interface{}{
 		"securable": map[string]string{
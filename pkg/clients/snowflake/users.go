@@ -32,6 +32,7 @@ import (
 const (
 	snowflakeUsersPageLimit = 10000
 	defaultSecondaryRoles   = "ALL"
+	userTypeService         = "SERVICE"
 )
 
 // snowflakeUserToStruct converts a SnowflakeUser to a structs.User
@@ -174,7 +175,7 @@ func (c *SnowflakeClient) CreateUser(ctx context.Context, user *structs.User) (*
 	log.Info("creating user")
 	endpoint := "/api/v2/users"
 
-	if user.Email == "" || user.UserName == "" {
+	if user.UserName == "" || (user.Email == "" && !user.IsServiceAccount()) {
 		return nil, fmt.Errorf("email and username are required for Snowflake user creation")
 	}
 
@@ -182,12 +183,19 @@ func (c *SnowflakeClient) CreateUser(ctx context.Context, user *structs.User) (*
 
 	payload := map[string]interface{}{
 		"name":                    quoteSnowflakeIdentifier(userName, false),
-		"email":                   user.Email,
 		"login_name":              user.UserName,
-		"first_name":              user.FirstName,
-		"last_name":               user.LastName,
 		"default_secondary_roles": defaultSecondaryRoles,
 	}
+	if user.Email != "" {
+		payload["email"] = user.Email
+	}
+	if user.IsServiceAccount() {
+		// service users authenticate with key pairs or OAuth only and have no person attributes
+		payload["type"] = userTypeService
+	} else {
+		payload["first_name"] = user.FirstName
+		payload["last_name"] = user.LastName
+	}
 
 	if user.DisplayName != "" {
 		payload["displayName"] = user.DisplayName
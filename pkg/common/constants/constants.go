@@ -4,4 +4,7 @@ const (
 	ContentTypeHeaderKey = "Content-Type"
 	// force reconcile label constant
 	ForceReconcileLabel = "operator.dataverse.redhat.com/force-reconcile"
+	// annotation approving the membership removals blocked by the removal guard,
+	// its value lists the approval tokens reported in the RemovalBlocked condition
+	ApproveRemovalsAnnotation = "operator.dataverse.redhat.com/approve-removals"
 )
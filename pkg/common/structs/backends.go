@@ -3,6 +3,8 @@ package structs
 type BackendParams struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// TeamName overrides the team name derived from the group name, if set
+	TeamName string `json:"team_name,omitempty"`
 }
 
 func (b *BackendParams) GetName() string {
@@ -12,3 +14,7 @@ func (b *BackendParams) GetName() string {
 func (b *BackendParams) GetType() string {
 	return b.Type
 }
+
+func (b *BackendParams) GetTeamName() string {
+	return b.TeamName
+}
@@ -8,6 +8,8 @@ type User struct {
 	LastName    string `json:"last_name,omitempty"`
 	DisplayName string `json:"display_name,omitempty"`
 	Role        string `json:"role,omitempty"`
+	// ServiceAccount marks a non human user, e.g. a bot or CI identity, that doesn't exist in LDAP
+	ServiceAccount bool `json:"service_account,omitempty"`
 }
 
 func (u *User) GetID() string {
@@ -38,6 +40,10 @@ func (u *User) GetRole() string {
 	return u.Role
 }
 
+func (u *User) IsServiceAccount() bool {
+	return u.ServiceAccount
+}
+
 type LDAPUser struct {
 	CN          string `json:"cn,omitempty"`
 	DisplayName string `json:"displayName,omitempty"`
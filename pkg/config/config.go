@@ -51,6 +51,22 @@ type APIServerConfig struct {
 // ControllerConfig represents controller-specific configuration
 type ControllerConfig struct {
 	MaxConcurrentReconciles int `yaml:"maxConcurrentReconciles"`
+	// ResyncInterval is the default interval after which a Group is reconciled again,
+	// Groups can override it with spec.resyncInterval
+	ResyncInterval string `yaml:"resyncInterval"`
+	// RemovalGuard blocks the membership removals of a team above its limits until they are approved,
+	// Groups can override it with spec.removal_guard
+	RemovalGuard RemovalGuardConfig `yaml:"removalGuard"`
+	// MaxConcurrentBackends is the number of backends of a Group processed at the same time
+	// during a reconciliation
+	MaxConcurrentBackends int `yaml:"maxConcurrentBackends"`
+}
+
+// RemovalGuardConfig holds the limits of membership removals per team and reconciliation,
+// a limit of 0 disables it
+type RemovalGuardConfig struct {
+	MaxRemovals       int `yaml:"maxRemovals"`
+	MaxRemovalPercent int `yaml:"maxRemovalPercent"`
 }
 
 type CORSConfig struct {
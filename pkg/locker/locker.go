@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package locker provides the named locks that serialize the updates of the cache records shared
+// by the group reconciliations, the offboarding job and the cache preload. The locks are held in
+// Redis when the redis cache driver is used, so that they hold across replicas, and in process
+// otherwise.
+package locker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/redis"
+)
+
+// ErrLeaseLost is the cause of the cancellation of the context of a lease that expired
+// before it was released
+var ErrLeaseLost = errors.New("lock lease lost")
+
+// releaseTimeout bounds the release of the locks of a lease, which doesn't use the context
+// of the lease as that context may already be canceled
+const releaseTimeout = 5 * time.Second
+
+// Locker acquires named locks
+type Locker interface {
+	// Lock blocks until all the keys are locked, or ctx is done. The keys are acquired all at once,
+	// so callers locking several keys can't deadlock each other. The work protected by the locks
+	// must use the context of the returned lease, which is canceled once the lease is lost.
+	Lock(ctx context.Context, keys ...string) (*Lease, error)
+}
+
+// New returns the Locker matching the driver of c: locks held in Redis for the redis driver,
+// so that they are shared by all the replicas using it, and in-process locks otherwise
+func New(c cache.Cache) Locker {
+	if rc, ok := c.(*redis.RedisCache); ok {
+		return NewRedisLocker(rc.Client(), DefaultLeaseTTL)
+	}
+	return NewMemoryLocker()
+}
+
+// GroupKey is the lock key of the cache records of a group, held for the whole reconciliation
+// of the group
+func GroupKey(groupName string) string {
+	return "group:" + groupName
+}
+
+// UserKey is the lock key of the cache records of a user, shared by all the groups the user
+// is a member of
+func UserKey(uid string) string {
+	return "user:" + uid
+}
+
+// ServiceAccountKey is the lock key of the cache record of a service account
+func ServiceAccountKey(name string) string {
+	return "serviceaccount:" + name
+}
+
+// TeamsKey is the lock key of the TeamStore records of a backend, held by the cache preload while it
+// fetches and stores the teams of the backend and by the reconciliations while they rename or release
+// a team of the backend, so that the preload doesn't store a team changed in the meantime
+func TeamsKey(backendKey string) string {
+	return "teams:" + backendKey
+}
+
+// MigrationKey is the lock key held while the store migrations are applied, at startup or by the
+// migrate command, so that replicas starting together don't migrate the records concurrently
+const MigrationKey = "store:migration"
+
+// Do runs fn while holding the locks of the keys, fn gets the context of the lease
+func Do(ctx context.Context, l Locker, keys []string, fn func(ctx context.Context) error) error {
+	lease, err := l.Lock(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	fnErr := fn(lease.Context())
+	return errors.Join(fnErr, lease.Unlock())
+}
+
+// uniqueKeys returns the sorted keys without duplicates, e.g. the old and new name of a group that
+// isn't renamed
+func uniqueKeys(keys []string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(keys)))
+}
+
+// Lease is a set of locks held together until they are released with Unlock
+type Lease struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	release func(ctx context.Context) error
+
+	once       sync.Once
+	releaseErr error
+}
+
+func newLease(parent context.Context, release func(ctx context.Context) error) *Lease {
+	ctx, cancel := context.WithCancelCause(parent)
+	return &Lease{ctx: ctx, cancel: cancel, release: release}
+}
+
+// Context is canceled when the lease is lost or released, or the context it was acquired with is done
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// Unlock releases the locks, it is safe to call it more than once
+func (l *Lease) Unlock() error {
+	l.once.Do(func() {
+		l.cancel(context.Canceled)
+		ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer cancel()
+		l.releaseErr = l.release(ctx)
+	})
+	return l.releaseErr
+}
@@ -0,0 +1,119 @@
+package locker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+)
+
+func newTestRedisLocker(t *testing.T, ttl time.Duration) (*RedisLocker, *miniredis.Miniredis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisLocker(client, ttl), srv
+}
+
+// testLocker checks the behaviour shared by the lockers
+func testLocker(t *testing.T, l Locker) {
+	ctx := context.Background()
+
+	first, err := l.Lock(ctx, GroupKey("team-a"), UserKey("alice@example.com"))
+	require.NoError(t, err)
+
+	// a lease holding one of the keys blocks the acquisition of all of them
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = l.Lock(waitCtx, UserKey("alice@example.com"), UserKey("bob@example.com"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// other keys are not blocked, including bob's that was not left half locked
+	second, err := l.Lock(ctx, GroupKey("team-b"), UserKey("bob@example.com"))
+	require.NoError(t, err)
+	require.NoError(t, second.Unlock())
+
+	acquired := make(chan *Lease)
+	go func() {
+		lease, err := l.Lock(ctx, UserKey("alice@example.com"))
+		assert.NoError(t, err)
+		acquired <- lease
+	}()
+	require.NoError(t, first.Unlock())
+	require.ErrorIs(t, first.Context().Err(), context.Canceled)
+	// releasing twice is a no-op
+	require.NoError(t, first.Unlock())
+
+	third := <-acquired
+	require.NotNil(t, third)
+	require.NoError(t, third.Unlock())
+}
+
+func TestMemoryLocker(t *testing.T) {
+	t.Parallel()
+	testLocker(t, NewMemoryLocker())
+}
+
+func TestRedisLocker(t *testing.T) {
+	t.Parallel()
+	l, srv := newTestRedisLocker(t, DefaultLeaseTTL)
+	testLocker(t, l)
+
+	// released locks don't linger in redis
+	assert.False(t, srv.Exists(keyPrefix+UserKey("alice@example.com")))
+}
+
+func TestRedisLocker_LeaseLost(t *testing.T) {
+	t.Parallel()
+	l, srv := newTestRedisLocker(t, 30*time.Millisecond)
+
+	lease, err := l.Lock(context.Background(), GroupKey("team-a"))
+	require.NoError(t, err)
+	owner, err := srv.Get(keyPrefix + GroupKey("team-a"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, owner)
+
+	// the lock expired and was taken by someone else, the holder stops its work and its
+	// release leaves the lock of the new holder in place
+	require.NoError(t, srv.Set(keyPrefix+GroupKey("team-a"), "other"))
+	select {
+	case <-lease.Context().Done():
+		assert.ErrorIs(t, context.Cause(lease.Context()), ErrLeaseLost)
+	case <-time.After(time.Second):
+		t.Fatal("lease not lost")
+	}
+	require.NoError(t, lease.Unlock())
+	owner, err = srv.Get(keyPrefix + GroupKey("team-a"))
+	require.NoError(t, err)
+	assert.Equal(t, "other", owner)
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+	l := NewMemoryLocker()
+
+	var leaseCtx context.Context
+	err := Do(context.Background(), l, []string{UserKey("alice@example.com")}, func(ctx context.Context) error {
+		leaseCtx = ctx
+		return nil
+	})
+	require.NoError(t, err)
+	// the locks are released once fn returns
+	assert.ErrorIs(t, leaseCtx.Err(), context.Canceled)
+	lease, err := l.Lock(context.Background(), UserKey("alice@example.com"))
+	require.NoError(t, err)
+	require.NoError(t, lease.Unlock())
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryLocker{}, New(c))
+}
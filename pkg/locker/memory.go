@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locker
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryLocker holds the locks in process, it is used with the memory cache driver whose
+// records are not shared with other replicas anyway
+type MemoryLocker struct {
+	mu   sync.Mutex
+	held map[string]struct{}
+	// released is closed, and replaced, whenever locks are released to wake up the waiters
+	released chan struct{}
+}
+
+// NewMemoryLocker returns a Locker holding the locks in process
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		held:     make(map[string]struct{}),
+		released: make(chan struct{}),
+	}
+}
+
+// Lock blocks until none of the keys is held and locks all of them
+func (l *MemoryLocker) Lock(ctx context.Context, keys ...string) (*Lease, error) {
+	keys = uniqueKeys(keys)
+	for {
+		l.mu.Lock()
+		if l.free(keys) {
+			for _, key := range keys {
+				l.held[key] = struct{}{}
+			}
+			l.mu.Unlock()
+			return newLease(ctx, func(context.Context) error {
+				l.unlock(keys)
+				return nil
+			}), nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// free reports whether none of the keys is held, l.mu must be held
+func (l *MemoryLocker) free(keys []string) bool {
+	for _, key := range keys {
+		if _, ok := l.held[key]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *MemoryLocker) unlock(keys []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		delete(l.held, key)
+	}
+	close(l.released)
+	l.released = make(chan struct{})
+}
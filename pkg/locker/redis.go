@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locker
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"math/rand/v2"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultLeaseTTL is the time after which the locks of a holder that stopped renewing them,
+	// e.g. because its replica crashed, are released by Redis
+	DefaultLeaseTTL = 30 * time.Second
+
+	// keyPrefix is prepended to the lock keys so that they don't collide with the cache records. Its
+	// hash tag puts all the lock keys in the same Redis Cluster slot, so that the scripts can acquire,
+	// renew and release several keys at once without failing with CROSSSLOT.
+	keyPrefix = "lock:{usernaut}:"
+
+	minRetryInterval = 20 * time.Millisecond
+	maxRetryInterval = time.Second
+)
+
+// acquireScript sets all the keys, or none of them when one is already held, to the owner ARGV[1] of
+// the lease for ARGV[2] milliseconds, and returns whether they were set
+var acquireScript = redis.NewScript(`
+for i = 1, #KEYS do
+	if redis.call('exists', KEYS[i]) == 1 then
+		return 0
+	end
+end
+for i = 1, #KEYS do
+	redis.call('set', KEYS[i], ARGV[1], 'NX', 'PX', ARGV[2])
+end
+return 1
+`)
+
+// renewScript extends the keys still held by the owner ARGV[1] by ARGV[2] milliseconds
+// and returns how many of them were extended
+var renewScript = redis.NewScript(`
+local renewed = 0
+for i = 1, #KEYS do
+	if redis.call('get', KEYS[i]) == ARGV[1] then
+		redis.call('pexpire', KEYS[i], ARGV[2])
+		renewed = renewed + 1
+	end
+end
+return renewed
+`)
+
+// releaseScript deletes the keys still held by the owner ARGV[1]
+var releaseScript = redis.NewScript(`
+for i = 1, #KEYS do
+	if redis.call('get', KEYS[i]) == ARGV[1] then
+		redis.call('del', KEYS[i])
+	end
+end
+return 0
+`)
+
+// RedisLocker holds the locks in Redis with SET NX and a lease: the locks expire after the lease TTL
+// unless their holder renews them, which it does every third of the TTL while it holds them. The
+// locks are set to a random owner value unique to the lease, so that a holder whose lease expired
+// can neither renew nor release the locks of the next holder.
+type RedisLocker struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// NewRedisLocker returns a Locker holding the locks in Redis with leases of the given TTL
+func NewRedisLocker(client redis.UniversalClient, ttl time.Duration) *RedisLocker {
+	return &RedisLocker{client: client, ttl: ttl}
+}
+
+// Lock retries, with a jittered exponential backoff, until all the keys are free and locks them
+func (l *RedisLocker) Lock(ctx context.Context, keys ...string) (*Lease, error) {
+	keys = uniqueKeys(keys)
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = keyPrefix + key
+	}
+
+	owner := cryptorand.Text()
+	retryInterval := minRetryInterval
+	for {
+		acquired, err := acquireScript.Run(ctx, l.client, redisKeys, owner, l.ttl.Milliseconds()).Bool()
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			lease := newLease(ctx, func(ctx context.Context) error {
+				return releaseScript.Run(ctx, l.client, redisKeys, owner).Err()
+			})
+			go l.renew(lease, redisKeys, owner)
+			return lease, nil
+		}
+
+		select {
+		case <-time.After(retryInterval/2 + rand.N(retryInterval/2)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		retryInterval = min(2*retryInterval, maxRetryInterval)
+	}
+}
+
+// renew extends the locks of the lease until it is released. The lease is lost, and its context
+// canceled, when one of its locks is held by someone else or couldn't be renewed before it expired.
+func (l *RedisLocker) renew(lease *Lease, redisKeys []string, owner string) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-lease.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := renewScript.Run(lease.ctx, l.client, redisKeys, owner, l.ttl.Milliseconds()).Int()
+		switch {
+		case err == nil && renewed == len(redisKeys):
+			renewedAt = time.Now()
+		case err == nil || time.Since(renewedAt) >= l.ttl:
+			lease.cancel(ErrLeaseLost)
+			return
+		}
+	}
+}
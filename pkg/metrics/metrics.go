@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics of Usernaut. They are registered with the
+// controller-runtime metrics registry and served on the metrics endpoint of the manager.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "usernaut"
+
+// Results of the reconciliations, LDAP searches and offboarding of users
+const (
+	ResultSuccess  = "success"
+	ResultError    = "error"
+	ResultNotFound = "not_found"
+
+	ResultOffboarded = "offboarded"
+	ResultExcluded   = "excluded"
+)
+
+// Operations on the users of a backend
+const (
+	UsersCreated = "created"
+	UsersAdded   = "added"
+	UsersRemoved = "removed"
+)
+
+// codeError is the code of the backend requests that got no response
+const codeError = "error"
+
+var (
+	// ReconcileDuration is the duration of the reconciliations of each Group CR
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "group_reconcile_duration_seconds",
+		Help:      "Duration of the reconciliations of the Groups by group and result.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"group", "result"})
+
+	// BackendRequests counts the requests sent to the backend APIs
+	BackendRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "backend_requests_total",
+		Help:      "Requests sent to the backend APIs by backend, method and status code.",
+	}, []string{"backend", "method", "code"})
+
+	// BackendRequestDuration is the latency of the requests sent to the backend APIs
+	BackendRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "backend_request_duration_seconds",
+		Help:      "Latency of the requests sent to the backend APIs by backend and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"backend", "method"})
+
+	// BackendUsers counts the users created in the backends and added to or removed from their teams
+	BackendUsers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "backend_users_total",
+		Help:      "Users created in the backends, added to or removed from their teams by backend and operation.",
+	}, []string{"backend", "operation"})
+
+	// LDAPSearchDuration is the latency of the LDAP searches
+	LDAPSearchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ldap_search_duration_seconds",
+		Help:      "Latency of the LDAP searches by operation and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "result"})
+
+	// LDAPBulkBatches counts the batches of the bulk LDAP user fetches
+	LDAPBulkBatches = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ldap_bulk_batches_total",
+		Help:      "Batches of the bulk LDAP user fetches.",
+	})
+
+	// OffboardingUsers counts the users processed by the offboarding job
+	OffboardingUsers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "offboarding_users_total",
+		Help:      "Users offboarded, excluded or failed to offboard by the offboarding job by result.",
+	}, []string{"result"})
+
+	// OffboardingLastRun is the time of the last completed run of the offboarding job
+	OffboardingLastRun = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "offboarding_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed run of the offboarding job.",
+	})
+
+	// StoreEntries is the number of entries of the stores
+	StoreEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "store_entries",
+		Help:      "Entries of the cache stores by store, updated when the store is scanned.",
+	}, []string{"store"})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		ReconcileDuration,
+		BackendRequests,
+		BackendRequestDuration,
+		BackendUsers,
+		LDAPSearchDuration,
+		LDAPBulkBatches,
+		OffboardingUsers,
+		OffboardingLastRun,
+		StoreEntries,
+	)
+}
+
+// ObserveReconcile records the duration and result of the reconciliation of a group
+func ObserveReconcile(group string, duration time.Duration, err error) {
+	ReconcileDuration.WithLabelValues(group, result(err)).Observe(duration.Seconds())
+}
+
+// ObserveBackendRequest records a request sent to a backend API, statusCode is ignored when the
+// request got no response
+func ObserveBackendRequest(backend, method string, statusCode int, duration time.Duration, err error) {
+	code := codeError
+	if err == nil {
+		code = strconv.Itoa(statusCode)
+	}
+	BackendRequests.WithLabelValues(backend, method, code).Inc()
+	BackendRequestDuration.WithLabelValues(backend, method).Observe(duration.Seconds())
+}
+
+// AddBackendUsers counts the users of an operation on a backend
+func AddBackendUsers(backend, operation string, count int) {
+	if count == 0 {
+		return
+	}
+	BackendUsers.WithLabelValues(backend, operation).Add(float64(count))
+}
+
+// ObserveLDAPSearch records the latency and result of an LDAP search, a missing entry is not an error
+func ObserveLDAPSearch(operation string, duration time.Duration, err error) {
+	searchResult := result(err)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		searchResult = ResultNotFound
+	}
+	LDAPSearchDuration.WithLabelValues(operation, searchResult).Observe(duration.Seconds())
+}
+
+// InstrumentRoundTripper records the requests of the backend SDK clients sent through next,
+// as ObserveBackendRequest does for the requests sent by the request package
+func InstrumentRoundTripper(backend string, next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+		ObserveBackendRequest(backend, req.Method, statusCode, time.Since(start), err)
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.GetCounter().GetValue()
+}
+
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	require.NoError(t, observer.(prometheus.Metric).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestInstrumentRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: InstrumentRoundTripper("test_sdk", http.DefaultTransport)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, float64(1), counterValue(t, BackendRequests.WithLabelValues("test_sdk", http.MethodGet, "404")))
+	assert.Equal(t, uint64(1), histogramCount(t, BackendRequestDuration.WithLabelValues("test_sdk", http.MethodGet)))
+}
+
+func TestObserveBackendRequest(t *testing.T) {
+	t.Parallel()
+
+	ObserveBackendRequest("test_requester", "FetchTeam", 200, time.Second, nil)
+	ObserveBackendRequest("test_requester", "FetchTeam", 0, time.Second, errors.New("connection refused"))
+
+	assert.Equal(t, float64(1), counterValue(t, BackendRequests.WithLabelValues("test_requester", "FetchTeam", "200")))
+	assert.Equal(t, float64(1), counterValue(t, BackendRequests.WithLabelValues("test_requester", "FetchTeam", "error")))
+}
+
+func TestObserveLDAPSearch(t *testing.T) {
+	t.Parallel()
+
+	ObserveLDAPSearch("test_search", time.Millisecond, nil)
+	ObserveLDAPSearch("test_search", time.Millisecond, ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("missing")))
+	ObserveLDAPSearch("test_search", time.Millisecond, errors.New("timeout"))
+
+	for _, result := range []string{ResultSuccess, ResultNotFound, ResultError} {
+		assert.Equal(t, uint64(1), histogramCount(t, LDAPSearchDuration.WithLabelValues("test_search", result)), result)
+	}
+}
+
+func TestAddBackendUsers(t *testing.T) {
+	t.Parallel()
+
+	AddBackendUsers("test_backend", UsersAdded, 0)
+	AddBackendUsers("test_backend", UsersAdded, 3)
+	assert.Equal(t, float64(3), counterValue(t, BackendUsers.WithLabelValues("test_backend", UsersAdded)))
+}
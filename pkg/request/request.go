@@ -25,6 +25,7 @@ import (
 
 	"github.com/gojek/heimdall/v7"
 	"github.com/redhat-data-and-ai/usernaut/pkg/logger"
+	"github.com/redhat-data-and-ai/usernaut/pkg/metrics"
 	"github.com/sirupsen/logrus"
 
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
@@ -117,7 +118,14 @@ func (r *Requester) sendRequest(httpClient heimdall.Doer, methodName string,
 	}).Info("RECEIVED_HTTP_RESPONSE")
 
 	// Calculate time taken to receive response
-	durationMs := float64(time.Since(start).Nanoseconds() / 1000000)
+	duration := time.Since(start)
+	durationMs := float64(duration.Nanoseconds() / 1000000)
+
+	statusCode := 0
+	if response != nil {
+		statusCode = response.StatusCode
+	}
+	metrics.ObserveBackendRequest(serviceName, methodName, statusCode, duration, err)
 
 	log.WithFields(logrus.Fields{
 		"service":    serviceName,
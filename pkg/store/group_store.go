@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 )
@@ -13,6 +14,8 @@ type BackendInfo struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// NestedTeams are the IDs of the backend teams nested in this team by the operator
+	NestedTeams []string `json:"nested_teams,omitempty"`
 }
 
 // GroupData represents the consolidated data stored for a group
@@ -22,10 +25,19 @@ type GroupData struct {
 	Backends map[string]BackendInfo `json:"backends"` // key: "backendName_backendType"
 }
 
+// membersField is the field of the group hash holding the JSON array of member uids,
+// each backend is held in its own field so that the backends of a group processed
+// concurrently don't overwrite each other
+const membersField = "members"
+
+// backendFieldPrefix prefixes the fields of the group hash holding the JSON BackendInfo of a backend
+const backendFieldPrefix = "backend:"
+
 // GroupStore handles consolidated group cache operations
 // Key format: "group:<groupName>"
-// Value: JSON object with members and backends
-// NOTE: This store does NOT handle locking - callers must ensure proper synchronization
+// Value: hash of {"members": JSON array, "backend:<backendName_backendType>": JSON BackendInfo}
+// NOTE: Each update is a single atomic cache operation, callers must still synchronize
+// sequences of operations that have to be consistent with each other
 type GroupStore struct {
 	cache cache.Cache
 }
@@ -47,47 +59,83 @@ func backendKey(backendName, backendType string) string {
 	return backendName + "_" + backendType
 }
 
+// backendField returns the field of the group hash holding a backend
+func backendField(backendName, backendType string) string {
+	return backendFieldPrefix + backendKey(backendName, backendType)
+}
+
 // Get retrieves the full group data from cache
 // Returns empty GroupData if the group is not found in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *GroupStore) Get(ctx context.Context, groupName string) (*GroupData, error) {
-	key := s.groupKey(groupName)
-	val, err := s.cache.Get(ctx, key)
+	fields, err := s.cache.HGetAll(ctx, s.groupKey(groupName))
 	if err != nil {
-		// Group not found, return empty data
-		return &GroupData{
-			Members:  []string{},
-			Backends: make(map[string]BackendInfo),
-		}, nil
+		return nil, fmt.Errorf("failed to get group data from cache: %w", err)
 	}
+	return decodeGroupData(fields)
+}
 
-	var data GroupData
-	if err := json.Unmarshal([]byte(val.(string)), &data); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal group data: %w", err)
+// decodeGroupData assembles the GroupData from the fields of the group hash
+func decodeGroupData(fields map[string]string) (*GroupData, error) {
+	data := &GroupData{
+		Members:  []string{},
+		Backends: make(map[string]BackendInfo),
+	}
+	for field, value := range fields {
+		switch {
+		case field == membersField:
+			if err := json.Unmarshal([]byte(value), &data.Members); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal group members: %w", err)
+			}
+		case strings.HasPrefix(field, backendFieldPrefix):
+			var info BackendInfo
+			if err := json.Unmarshal([]byte(value), &info); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal group backend: %w", err)
+			}
+			data.Backends[strings.TrimPrefix(field, backendFieldPrefix)] = info
+		}
 	}
 
-	// Ensure maps and slices are initialized
+	// Ensure slices are initialized
 	if data.Members == nil {
 		data.Members = []string{}
 	}
-	if data.Backends == nil {
-		data.Backends = make(map[string]BackendInfo)
+	return data, nil
+}
+
+// encodeGroupData returns the fields of the group hash holding the data
+func encodeGroupData(data *GroupData) (map[string]string, error) {
+	members := data.Members
+	if members == nil {
+		members = []string{}
+	}
+	membersJSON, err := json.Marshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal group members: %w", err)
 	}
 
-	return &data, nil
+	fields := map[string]string{membersField: string(membersJSON)}
+	for key, info := range data.Backends {
+		infoJSON, err := json.Marshal(info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal group backend: %w", err)
+		}
+		fields[backendFieldPrefix+key] = string(infoJSON)
+	}
+	return fields, nil
 }
 
-// Set stores the full group data in cache
+// Set stores the full group data in cache, replacing the existing record atomically
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *GroupStore) Set(ctx context.Context, groupName string, data *GroupData) error {
 	key := s.groupKey(groupName)
 
-	jsonData, err := json.Marshal(data)
+	fields, err := encodeGroupData(data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal group data: %w", err)
+		return err
 	}
 
-	if err := s.cache.Set(ctx, key, string(jsonData), cache.NoExpiration); err != nil {
+	if err := s.cache.HReplace(ctx, key, fields); err != nil {
 		return fmt.Errorf("failed to set group data in cache: %w", err)
 	}
 
@@ -104,17 +152,27 @@ func (s *GroupStore) Delete(ctx context.Context, groupName string) error {
 // Exists checks if a group exists in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *GroupStore) Exists(ctx context.Context, groupName string) (bool, error) {
-	key := s.groupKey(groupName)
-	_, err := s.cache.Get(ctx, key)
+	fields, err := s.cache.HGetAll(ctx, s.groupKey(groupName))
 	if err != nil {
-		return false, nil
+		return false, fmt.Errorf("failed to get group data from cache: %w", err)
 	}
-	return true, nil
+	return len(fields) > 0, nil
+}
+
+// Rename moves the group record to newName atomically
+// Returns false if a group newName already exists or the group is not found in cache
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *GroupStore) Rename(ctx context.Context, groupName, newName string) (bool, error) {
+	renamed, err := s.cache.RenameNX(ctx, s.groupKey(groupName), s.groupKey(newName))
+	if err != nil {
+		return false, fmt.Errorf("failed to rename group in cache: %w", err)
+	}
+	return renamed, nil
 }
 
 // --- Member Operations ---
 
-// GetMembers returns the list of user emails for a group
+// GetMembers returns the list of user uids for a group
 // Returns an empty slice if the group is not found in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *GroupStore) GetMembers(ctx context.Context, groupName string) ([]string, error) {
@@ -125,17 +183,22 @@ func (s *GroupStore) GetMembers(ctx context.Context, groupName string) ([]string
 	return data.Members, nil
 }
 
-// SetMembers sets the complete list of user emails for a group
+// SetMembers sets the complete list of user uids for a group
 // This replaces any existing members while preserving backends
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *GroupStore) SetMembers(ctx context.Context, groupName string, members []string) error {
-	data, err := s.Get(ctx, groupName)
+	if members == nil {
+		members = []string{}
+	}
+	membersJSON, err := json.Marshal(members)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to marshal group members: %w", err)
 	}
 
-	data.Members = members
-	return s.Set(ctx, groupName, data)
+	if err := s.cache.HSet(ctx, s.groupKey(groupName), map[string]string{membersField: string(membersJSON)}); err != nil {
+		return fmt.Errorf("failed to set group members in cache: %w", err)
+	}
+	return nil
 }
 
 // --- Backend Operations ---
@@ -170,36 +233,57 @@ func (s *GroupStore) GetBackendID(ctx context.Context, groupName, backendName, b
 
 // SetBackend sets a backend for a group
 // If the group doesn't exist, it will be created
-// If the backend exists, it will be updated
+// If the backend exists, it will be updated, its nested teams are kept unless the ID changes
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *GroupStore) SetBackend(ctx context.Context, groupName, backendName, backendType, backendID string) error {
-	data, err := s.Get(ctx, groupName)
-	if err != nil {
-		return err
-	}
-
-	key := backendKey(backendName, backendType)
-	data.Backends[key] = BackendInfo{
+	info := BackendInfo{
 		ID:   backendID,
 		Name: backendName,
 		Type: backendType,
 	}
+	existing, exists, err := s.getBackend(ctx, groupName, backendName, backendType)
+	if err != nil {
+		return err
+	}
+	if exists && existing.ID == backendID {
+		info.NestedTeams = existing.NestedTeams
+	}
 
-	return s.Set(ctx, groupName, data)
+	return s.setBackend(ctx, groupName, info)
 }
 
-// DeleteBackend removes a specific backend from a group's record
-// NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *GroupStore) DeleteBackend(ctx context.Context, groupName, backendName, backendType string) error {
+// getBackend returns the info of a backend of a group
+func (s *GroupStore) getBackend(ctx context.Context, groupName, backendName, backendType string) (BackendInfo, bool,
+	error) {
 	data, err := s.Get(ctx, groupName)
 	if err != nil {
-		return err
+		return BackendInfo{}, false, err
 	}
+	info, exists := data.Backends[backendKey(backendName, backendType)]
+	return info, exists, nil
+}
 
-	key := backendKey(backendName, backendType)
-	delete(data.Backends, key)
+// setBackend writes the field of the group hash holding a backend
+func (s *GroupStore) setBackend(ctx context.Context, groupName string, info BackendInfo) error {
+	infoJSON, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to marshal group backend: %w", err)
+	}
 
-	return s.Set(ctx, groupName, data)
+	field := backendField(info.Name, info.Type)
+	if err := s.cache.HSet(ctx, s.groupKey(groupName), map[string]string{field: string(infoJSON)}); err != nil {
+		return fmt.Errorf("failed to set group backend in cache: %w", err)
+	}
+	return nil
+}
+
+// DeleteBackend removes a specific backend from a group's record
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *GroupStore) DeleteBackend(ctx context.Context, groupName, backendName, backendType string) error {
+	if err := s.cache.HDel(ctx, s.groupKey(groupName), backendField(backendName, backendType)); err != nil {
+		return fmt.Errorf("failed to delete group backend from cache: %w", err)
+	}
+	return nil
 }
 
 // BackendExists checks if a specific backend exists for a group
@@ -214,3 +298,36 @@ func (s *GroupStore) BackendExists(ctx context.Context, groupName, backendName,
 	_, exists := data.Backends[key]
 	return exists, nil
 }
+
+// GetNestedTeams returns the IDs of the teams nested in the team of a specific backend
+// Returns an empty slice if the backend is not found
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *GroupStore) GetNestedTeams(ctx context.Context, groupName, backendName, backendType string) ([]string, error) {
+	data, err := s.Get(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := backendKey(backendName, backendType)
+	if backend, exists := data.Backends[key]; exists && backend.NestedTeams != nil {
+		return backend.NestedTeams, nil
+	}
+	return []string{}, nil
+}
+
+// SetNestedTeams sets the IDs of the teams nested in the team of a specific backend
+// Returns an error if the backend is not found
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *GroupStore) SetNestedTeams(ctx context.Context, groupName, backendName, backendType string,
+	teamIDs []string) error {
+	backend, exists, err := s.getBackend(ctx, groupName, backendName, backendType)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("backend %s not found for group %s", backendKey(backendName, backendType), groupName)
+	}
+	backend.NestedTeams = teamIDs
+
+	return s.setBackend(ctx, groupName, backend)
+}
@@ -52,7 +52,7 @@ func TestGroupStore_Get(t *testing.T) {
 			name:      "invalid JSON returns error",
 			groupName: "invalid-group",
 			setup: func(t *testing.T, store *GroupStore, c cache.Cache) {
-				err := c.Set(context.Background(), "group:invalid-group", "invalid json{{{", cache.NoExpiration)
+				err := c.HSet(context.Background(), "group:invalid-group", map[string]string{"members": "invalid json{{{"})
 				require.NoError(t, err)
 			},
 			wantMembers: nil,
@@ -378,6 +378,37 @@ func TestGroupStore_DeleteBackend(t *testing.T) {
 	assert.Equal(t, "team_456", backends["rover_rover"].ID)
 }
 
+func TestGroupStore_NestedTeams(t *testing.T) {
+	store, _ := setupGroupStore(t)
+	ctx := context.Background()
+
+	// Unknown backend
+	err := store.SetNestedTeams(ctx, "data-team", "rover", "rover", []string{"child-team"})
+	require.Error(t, err)
+	nested, err := store.GetNestedTeams(ctx, "data-team", "rover", "rover")
+	require.NoError(t, err)
+	assert.Empty(t, nested)
+
+	err = store.SetBackend(ctx, "data-team", "rover", "rover", "data-team")
+	require.NoError(t, err)
+	err = store.SetNestedTeams(ctx, "data-team", "rover", "rover", []string{"child-team"})
+	require.NoError(t, err)
+
+	// Nested teams are kept while the team ID doesn't change
+	err = store.SetBackend(ctx, "data-team", "rover", "rover", "data-team")
+	require.NoError(t, err)
+	nested, err = store.GetNestedTeams(ctx, "data-team", "rover", "rover")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"child-team"}, nested)
+
+	// A new team has nothing nested yet
+	err = store.SetBackend(ctx, "data-team", "rover", "rover", "new-team")
+	require.NoError(t, err)
+	nested, err = store.GetNestedTeams(ctx, "data-team", "rover", "rover")
+	require.NoError(t, err)
+	assert.Empty(t, nested)
+}
+
 func TestGroupStore_BackendExists(t *testing.T) {
 	store, _ := setupGroupStore(t)
 	ctx := context.Background()
@@ -411,13 +442,14 @@ func TestGroupStore_KeyPrefix(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify key has correct prefix
-	val, err := c.Get(ctx, "group:data-team")
+	val, err := c.HGetAll(ctx, "group:data-team")
 	assert.NoError(t, err)
-	assert.NotNil(t, val)
+	assert.Equal(t, map[string]string{"members": `["user@example.com"]`}, val)
 
 	// Verify key without prefix doesn't exist
-	_, err = c.Get(ctx, "data-team")
-	assert.Error(t, err)
+	val, err = c.HGetAll(ctx, "data-team")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
 }
 
 func TestGroupStore_ConsolidatedData(t *testing.T) {
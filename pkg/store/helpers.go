@@ -2,56 +2,54 @@ package store
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 )
 
-// deleteBackendHelper is a shared helper function for deleting a backend from an entity's record
-// It handles the common logic of:
-// 1. Getting the entity from cache
-// 2. Removing the specified backend
-// 3. Either updating the remaining backends or deleting the entire entry if no backends remain
+// The user, team and service account records share the same layout: a hash holding one
+// field per backend, {"backend_name_type": "backend_id"}. Each field is written with a single
+// HSET or HDEL, so concurrent updates of different backends of a record don't overwrite each
+// other, and the record is removed by the cache once its last backend is deleted.
+
+// getBackendsHelper returns the backends of the record at key
+// Returns an empty map if the record is not found in cache
 //
 // Parameters:
 //   - ctx: context for cache operations
 //   - c: the cache instance
 //   - key: the full cache key (with prefix already applied)
-//   - backendKey: the backend identifier to remove
 //   - entityType: the entity type name (e.g., "user", "team") for error messages
-func deleteBackendHelper(ctx context.Context, c cache.Cache, key, backendKey, entityType string) error {
-	// Get existing backends
-	backends := make(map[string]string)
-	val, err := c.Get(ctx, key)
+func getBackendsHelper(ctx context.Context, c cache.Cache, key, entityType string) (map[string]string, error) {
+	backends, err := c.HGetAll(ctx, key)
 	if err != nil {
-		// Entity doesn't exist, nothing to delete
-		return nil
+		return nil, fmt.Errorf("failed to get %s backends from cache: %w", entityType, err)
 	}
+	return backends, nil
+}
 
-	if err := json.Unmarshal([]byte(val.(string)), &backends); err != nil {
-		return fmt.Errorf("failed to unmarshal %s backends: %w", entityType, err)
+// setBackendHelper sets a backend ID in the record at key, creating the record if needed
+func setBackendHelper(ctx context.Context, c cache.Cache, key, backendKey, backendID, entityType string) error {
+	if err := c.HSet(ctx, key, map[string]string{backendKey: backendID}); err != nil {
+		return fmt.Errorf("failed to set %s in cache: %w", entityType, err)
 	}
+	return nil
+}
 
-	// Delete the backend
-	delete(backends, backendKey)
-
-	// If there are remaining backends, update the cache
-	if len(backends) > 0 {
-		data, err := json.Marshal(backends)
-		if err != nil {
-			return fmt.Errorf("failed to marshal %s backends: %w", entityType, err)
-		}
-
-		if err := c.Set(ctx, key, string(data), cache.NoExpiration); err != nil {
-			return fmt.Errorf("failed to update %s in cache: %w", entityType, err)
-		}
-	} else {
-		// No backends left, delete the entire entry
-		if err := c.Delete(ctx, key); err != nil {
-			return fmt.Errorf("failed to delete %s from cache: %w", entityType, err)
-		}
+// deleteBackendHelper removes a backend from the record at key
+// If this was the last backend, the record itself is removed
+func deleteBackendHelper(ctx context.Context, c cache.Cache, key, backendKey, entityType string) error {
+	if err := c.HDel(ctx, key, backendKey); err != nil {
+		return fmt.Errorf("failed to delete %s backend from cache: %w", entityType, err)
 	}
-
 	return nil
 }
+
+// existsHelper checks if the hash record at key exists
+func existsHelper(ctx context.Context, c cache.Cache, key, entityType string) (bool, error) {
+	fields, err := c.HGetAll(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s from cache: %w", entityType, err)
+	}
+	return len(fields) > 0, nil
+}
@@ -3,33 +3,55 @@ package store
 import "context"
 
 // UserStoreInterface defines operations for user-related cache operations
+// Key format: "user:<uid>", the users are identified by their LDAP uid
 // This interface enables mocking in tests and follows the dependency inversion principle
 type UserStoreInterface interface {
 	// GetBackends returns a map of backend IDs for a user
 	// Returns an empty map if the user is not found in cache
 	// Map format: {"backend_name_type": "backend_user_id"}
-	GetBackends(ctx context.Context, email string) (map[string]string, error)
+	GetBackends(ctx context.Context, uid string) (map[string]string, error)
 
 	// SetBackend sets a backend ID for a user
 	// If the user doesn't exist, it will be created
 	// If the user exists, the backend ID will be added/updated in the map
-	SetBackend(ctx context.Context, email, backendKey, backendID string) error
+	SetBackend(ctx context.Context, uid, backendKey, backendID string) error
 
 	// DeleteBackend removes a specific backend ID from a user's record
 	// If this was the last backend, the entire user entry is deleted
-	DeleteBackend(ctx context.Context, email, backendKey string) error
+	DeleteBackend(ctx context.Context, uid, backendKey string) error
 
 	// Delete removes a user entirely from cache
-	Delete(ctx context.Context, email string) error
+	Delete(ctx context.Context, uid string) error
 
 	// Exists checks if a user exists in cache
-	Exists(ctx context.Context, email string) (bool, error)
+	Exists(ctx context.Context, uid string) (bool, error)
+
+	// GetByBackendID returns the uid of the user owning a backend user ID
+	// Returns an empty string if no user owns it
+	GetByBackendID(ctx context.Context, backendKey, backendID string) (string, error)
+
+	// GetBackendUsers returns the users of a backend
+	// Returns an empty map if the backend has no users in cache
+	// Map format: {"backend_user_id": "uid"}
+	GetBackendUsers(ctx context.Context, backendKey string) (map[string]string, error)
 
 	// GetByPattern searches for users matching a pattern and returns their data
 	// Pattern should NOT include the "user:" prefix - it will be added automatically
-	// Example: pattern "*@example.com" searches for "user:*@example.com"
-	// Returns: map[email]backends where backends is map[backendKey]backendID
+	// Example: pattern "j*" searches for "user:j*"
+	// Returns: map[uid]backends where backends is map[backendKey]backendID
 	GetByPattern(ctx context.Context, pattern string) (map[string]map[string]string, error)
+
+	// GetEmail returns the email of a user
+	// Returns an empty string if the user has no identity record
+	GetEmail(ctx context.Context, uid string) (string, error)
+
+	// GetUIDByEmail returns the uid of the user with an email
+	// Returns an empty string if the email is not linked to a user
+	GetUIDByEmail(ctx context.Context, email string) (string, error)
+
+	// SetEmail records the email of a user, creating its identity record if needed
+	// The previous email of the user is no longer linked to it
+	SetEmail(ctx context.Context, uid, email string) error
 }
 
 // TeamStoreInterface defines operations for team-related cache operations
@@ -58,15 +80,46 @@ type TeamStoreInterface interface {
 	Exists(ctx context.Context, teamName string) (bool, error)
 }
 
+// ServiceAccountStoreInterface defines operations for service account related cache operations
+// Key format: "serviceaccount:<name>"
+// Service accounts don't exist in LDAP, so they are stored apart from the users that are
+// subject to offboarding.
+type ServiceAccountStoreInterface interface {
+	// GetBackends returns a map of backend user IDs for a service account
+	// Returns an empty map if the service account is not found in cache
+	// Map format: {"backend_name_type": "backend_user_id"}
+	GetBackends(ctx context.Context, name string) (map[string]string, error)
+
+	// SetBackend sets a backend user ID for a service account
+	// If the service account doesn't exist, it will be created
+	// If the service account exists, the backend ID will be added/updated in the map
+	SetBackend(ctx context.Context, name, backendKey, backendID string) error
+
+	// DeleteBackend removes a specific backend ID from a service account's record
+	// If this was the last backend, the entire service account entry is deleted
+	DeleteBackend(ctx context.Context, name, backendKey string) error
+
+	// Delete removes a service account entirely from cache
+	Delete(ctx context.Context, name string) error
+
+	// Exists checks if a service account exists in cache
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// GetBackendServiceAccounts returns the service accounts created in a backend
+	// Returns an empty map if the backend has no service accounts in cache
+	// Map format: {"backend_user_id": "name"}
+	GetBackendServiceAccounts(ctx context.Context, backendKey string) (map[string]string, error)
+}
+
 // GroupStoreInterface defines operations for consolidated group cache operations
 // Key format: "group:<groupName>"
-// Value: JSON object containing members and backends
+// Value: hash of {"members": JSON array of uids, "backend:<backendName_backendType>": JSON BackendInfo}
 type GroupStoreInterface interface {
 	// Get retrieves the full group data from cache
 	// Returns empty GroupData if the group is not found in cache
 	Get(ctx context.Context, groupName string) (*GroupData, error)
 
-	// Set stores the full group data in cache
+	// Set stores the full group data in cache, replacing the existing record atomically
 	Set(ctx context.Context, groupName string, data *GroupData) error
 
 	// Delete removes a group entirely from cache
@@ -75,13 +128,17 @@ type GroupStoreInterface interface {
 	// Exists checks if a group exists in cache
 	Exists(ctx context.Context, groupName string) (bool, error)
 
+	// Rename moves the group record to newName atomically
+	// Returns false if a group newName already exists or the group is not found in cache
+	Rename(ctx context.Context, groupName, newName string) (bool, error)
+
 	// --- Member Operations ---
 
-	// GetMembers returns the list of user emails for a group
+	// GetMembers returns the list of user uids for a group
 	// Returns an empty slice if the group is not found in cache
 	GetMembers(ctx context.Context, groupName string) ([]string, error)
 
-	// SetMembers sets the complete list of user emails for a group
+	// SetMembers sets the complete list of user uids for a group
 	// This replaces any existing members while preserving backends
 	SetMembers(ctx context.Context, groupName string, members []string) error
 
@@ -98,7 +155,7 @@ type GroupStoreInterface interface {
 
 	// SetBackend sets a backend for a group
 	// If the group doesn't exist, it will be created
-	// If the backend exists, it will be updated
+	// If the backend exists, it will be updated, its nested teams are kept unless the ID changes
 	SetBackend(ctx context.Context, groupName, backendName, backendType, backendID string) error
 
 	// DeleteBackend removes a specific backend from a group's record
@@ -106,31 +163,39 @@ type GroupStoreInterface interface {
 
 	// BackendExists checks if a specific backend exists for a group
 	BackendExists(ctx context.Context, groupName, backendName, backendType string) (bool, error)
+
+	// GetNestedTeams returns the IDs of the teams nested in the team of a specific backend
+	// Returns an empty slice if the backend is not found
+	GetNestedTeams(ctx context.Context, groupName, backendName, backendType string) ([]string, error)
+
+	// SetNestedTeams sets the IDs of the teams nested in the team of a specific backend
+	// Returns an error if the backend is not found
+	SetNestedTeams(ctx context.Context, groupName, backendName, backendType string, teamIDs []string) error
 }
 
 // UserGroupsStoreInterface defines operations for user-to-groups reverse index
-// Key format: "user:groups:<email>"
+// Key format: "user:groups:<uid>"
 type UserGroupsStoreInterface interface {
 	// GetGroups returns the list of groups for a user
 	// Returns an empty slice if the user is not found in cache
-	GetGroups(ctx context.Context, email string) ([]string, error)
+	GetGroups(ctx context.Context, uid string) ([]string, error)
 
 	// AddGroup adds a group to a user's group list if not already present
-	AddGroup(ctx context.Context, email, groupName string) error
+	AddGroup(ctx context.Context, uid, groupName string) error
 
 	// SetGroups sets the complete list of groups for a user
-	// This replaces any existing groups
-	SetGroups(ctx context.Context, email string, groups []string) error
+	// This replaces any existing groups atomically
+	SetGroups(ctx context.Context, uid string, groups []string) error
 
 	// RemoveGroup removes a specific group from a user's group list
 	// If this was the last group, the entry is deleted
-	RemoveGroup(ctx context.Context, email, groupName string) error
+	RemoveGroup(ctx context.Context, uid, groupName string) error
 
 	// Delete removes the user's groups entry entirely
-	Delete(ctx context.Context, email string) error
+	Delete(ctx context.Context, uid string) error
 
 	// Exists checks if a user has any groups in cache
-	Exists(ctx context.Context, email string) (bool, error)
+	Exists(ctx context.Context, uid string) (bool, error)
 }
 
 // StoreInterface is the main interface that combines all store operations
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// legacyPatterns are the key patterns of the records that were stored as JSON strings before the
+// store used hashes and sets
+var legacyPatterns = []string{"user:*", "team:*", "serviceaccount:*", "group:*"}
+
+// ConvertLegacyRecords converts the records stored as JSON strings by previous versions into the
+// hashes and sets used by the store, and returns the number of records converted. Records already
+// converted are left untouched, so it is safe to run it again.
+// NOTE: It runs as a store migration, see Migrations
+func (s *Store) ConvertLegacyRecords(ctx context.Context) (int, error) {
+	converted := 0
+	for _, pattern := range legacyPatterns {
+		// GetByPattern only returns the string records
+		records, err := s.cache.GetByPattern(ctx, pattern)
+		if err != nil {
+			return converted, fmt.Errorf("failed to search legacy records %s: %w", pattern, err)
+		}
+		for key, val := range records {
+			raw, ok := val.(string)
+			if !ok {
+				continue
+			}
+			if err := s.convertLegacyRecord(ctx, key, raw); err != nil {
+				return converted, fmt.Errorf("failed to convert legacy record %s: %w", key, err)
+			}
+			converted++
+		}
+	}
+	return converted, nil
+}
+
+// convertLegacyRecord replaces the JSON string record at key with its hash or set atomically
+func (s *Store) convertLegacyRecord(ctx context.Context, key, raw string) error {
+	switch {
+	case strings.HasPrefix(key, "group:"):
+		var data GroupData
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return fmt.Errorf("failed to unmarshal group data: %w", err)
+		}
+		// Set replaces the string record
+		return s.Group.Set(ctx, strings.TrimPrefix(key, "group:"), &data)
+
+	case strings.HasPrefix(key, "user:groups:"):
+		var groups []string
+		if err := json.Unmarshal([]byte(raw), &groups); err != nil {
+			return fmt.Errorf("failed to unmarshal user groups: %w", err)
+		}
+		// SetGroups replaces the string record
+		return s.UserGroups.SetGroups(ctx, strings.TrimPrefix(key, "user:groups:"), groups)
+
+	default:
+		// user:, team: and serviceaccount: records are maps of backend IDs
+		var backends map[string]string
+		if err := json.Unmarshal([]byte(raw), &backends); err != nil {
+			return fmt.Errorf("failed to unmarshal backends: %w", err)
+		}
+		return s.cache.HReplace(ctx, key, backends)
+	}
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// schemaKey is the hash recording the layout of the records, its version field holds the version of
+// the last migration applied. The records of a store without it predate the versioning, version 0.
+const (
+	schemaKey          = "store:schema"
+	schemaVersionField = "version"
+)
+
+// Migration changes the records written by previous versions to the layout the store expects. A
+// migration must be idempotent: if it fails, or the process stops before the schema version is
+// recorded, it is applied again.
+type Migration struct {
+	// Version is the schema version of the records once the migration is applied
+	Version     int
+	Description string
+	// Apply migrates the records and returns the number of records it changed
+	Apply func(ctx context.Context, s *Store) (int, error)
+}
+
+// Migrations returns the migrations of the store, ordered by version. resolveUID looks up the uid of
+// the users cached by email by previous versions.
+// Add a migration here, with the next version, whenever the layout of the records changes.
+func Migrations(resolveUID UIDResolver) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "convert the records stored as JSON strings to hashes and sets",
+			Apply: func(ctx context.Context, s *Store) (int, error) {
+				return s.ConvertLegacyRecords(ctx)
+			},
+		},
+		{
+			Version:     2,
+			Description: "index the backend IDs of the users",
+			Apply: func(ctx context.Context, s *Store) (int, error) {
+				return s.IndexBackendUsers(ctx)
+			},
+		},
+		{
+			Version:     3,
+			Description: "move the users cached by email to their uid",
+			Apply: func(ctx context.Context, s *Store) (int, error) {
+				return s.LinkUserIdentities(ctx, resolveUID)
+			},
+		},
+	}
+}
+
+// AppliedMigration is a migration applied by Migrate, along with the number of records it changed
+type AppliedMigration struct {
+	Migration
+	Records int
+}
+
+// SchemaVersion returns the schema version of the records, 0 if it was never recorded
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	val, err := s.cache.HGet(ctx, schemaKey, schemaVersionField)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schema version from cache: %w", err)
+	}
+	if val == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", val, err)
+	}
+	return version, nil
+}
+
+// setSchemaVersion records the schema version of the records
+func (s *Store) setSchemaVersion(ctx context.Context, version int) error {
+	if err := s.cache.HSet(ctx, schemaKey, map[string]string{schemaVersionField: strconv.Itoa(version)}); err != nil {
+		return fmt.Errorf("failed to set schema version in cache: %w", err)
+	}
+	return nil
+}
+
+// PendingMigrations returns the migrations newer than the schema version of the records, in order.
+// It fails if the records were migrated by a newer version, which this version can't read.
+func (s *Store) PendingMigrations(ctx context.Context, migrations []Migration) ([]Migration, error) {
+	latest := 0
+	for _, m := range migrations {
+		if m.Version <= latest {
+			return nil, fmt.Errorf("migration %d (%s) is out of order", m.Version, m.Description)
+		}
+		latest = m.Version
+	}
+
+	version, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if version > latest {
+		return nil, fmt.Errorf("schema version %d of the records is newer than the latest migration %d", version, latest)
+	}
+
+	for i, m := range migrations {
+		if m.Version > version {
+			return migrations[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+// Migrate applies the pending migrations in order, recording the schema version after each one, and
+// returns the migrations applied. It stops at the first migration that fails.
+// NOTE: Caller must hold the migration lock, and no process running a previous version may use the store
+func (s *Store) Migrate(ctx context.Context, migrations []Migration) ([]AppliedMigration, error) {
+	pending, err := s.PendingMigrations(ctx, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]AppliedMigration, 0, len(pending))
+	for _, m := range pending {
+		records, err := m.Apply(ctx, s)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+		if err := s.setSchemaVersion(ctx, m.Version); err != nil {
+			return applied, err
+		}
+		applied = append(applied, AppliedMigration{Migration: m, Records: records})
+	}
+	return applied, nil
+}
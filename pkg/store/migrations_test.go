@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMigration returns a migration recording the number of times it was applied
+func countingMigration(version int, applied map[int]int) Migration {
+	return Migration{
+		Version:     version,
+		Description: "test migration",
+		Apply: func(ctx context.Context, s *Store) (int, error) {
+			applied[version]++
+			return version * 10, nil
+		},
+	}
+}
+
+func TestStore_Migrate(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	version, err := store.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+
+	applied := make(map[int]int)
+	migrations := []Migration{countingMigration(1, applied), countingMigration(2, applied)}
+	done, err := store.Migrate(ctx, migrations)
+	require.NoError(t, err)
+	require.Len(t, done, 2)
+	assert.Equal(t, 1, done[0].Version)
+	assert.Equal(t, 20, done[1].Records)
+
+	version, err = store.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	// only the new migrations are applied
+	migrations = append(migrations, countingMigration(3, applied))
+	pending, err := store.PendingMigrations(ctx, migrations)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 3, pending[0].Version)
+
+	done, err = store.Migrate(ctx, migrations)
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, applied)
+
+	done, err = store.Migrate(ctx, migrations)
+	require.NoError(t, err)
+	assert.Empty(t, done)
+}
+
+func TestStore_Migrate_Failure(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	applied := make(map[int]int)
+	failure := errors.New("boom")
+	migrations := []Migration{
+		countingMigration(1, applied),
+		{Version: 2, Description: "failing migration", Apply: func(ctx context.Context, s *Store) (int, error) {
+			return 0, failure
+		}},
+		countingMigration(3, applied),
+	}
+
+	// the migrations stop at the failure, which is applied again next time
+	done, err := store.Migrate(ctx, migrations)
+	require.ErrorIs(t, err, failure)
+	assert.Len(t, done, 1)
+	assert.Equal(t, map[int]int{1: 1}, applied)
+
+	version, err := store.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+}
+
+func TestStore_PendingMigrations_Errors(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+	applied := make(map[int]int)
+
+	_, err = store.PendingMigrations(ctx, []Migration{countingMigration(2, applied), countingMigration(1, applied)})
+	assert.ErrorContains(t, err, "out of order")
+
+	// records migrated by a newer version
+	require.NoError(t, c.HSet(ctx, "store:schema", map[string]string{"version": "5"}))
+	_, err = store.Migrate(ctx, []Migration{countingMigration(1, applied)})
+	assert.ErrorContains(t, err, "newer than the latest migration")
+	assert.Empty(t, applied)
+
+	require.NoError(t, c.HSet(ctx, "store:schema", map[string]string{"version": "five"}))
+	_, err = store.SchemaVersion(ctx)
+	assert.ErrorContains(t, err, "invalid schema version")
+}
+
+func TestMigrations(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	// records written by previous versions: a JSON string user keyed by email
+	require.NoError(t, c.Set(ctx, "user:alice@example.com", `{"fivetran_prod":"user_1"}`, 0))
+	resolveUID := func(ctx context.Context, email string) (string, error) {
+		return map[string]string{"alice@example.com": "alice"}[email], nil
+	}
+
+	migrations := Migrations(resolveUID)
+	done, err := store.Migrate(ctx, migrations)
+	require.NoError(t, err)
+	assert.Len(t, done, len(migrations))
+
+	version, err := store.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+
+	backends, err := store.User.GetBackends(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fivetran_prod": "user_1"}, backends)
+	owner, err := store.User.GetByBackendID(ctx, "fivetran_prod", "user_1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+	email, err := store.User.GetEmail(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
+)
+
+// ServiceAccountStore handles service account related cache operations with "serviceaccount:" prefix
+// Key format: "serviceaccount:<name>"
+// Value: hash of {"backend_name_type": "backend_user_id"}
+// Service accounts are kept apart from the "user:" keys so that the user offboarding job,
+// which checks every cached user against LDAP, never sees them.
+// NOTE: Each update is a single atomic cache operation, callers must still synchronize
+// sequences of operations that have to be consistent with each other
+type ServiceAccountStore struct {
+	cache cache.Cache
+}
+
+// newServiceAccountStore creates a new ServiceAccountStore instance
+func newServiceAccountStore(c cache.Cache) *ServiceAccountStore {
+	return &ServiceAccountStore{
+		cache: c,
+	}
+}
+
+// serviceAccountKey returns the prefixed cache key for a service account
+func (s *ServiceAccountStore) serviceAccountKey(name string) string {
+	return "serviceaccount:" + name
+}
+
+// GetBackends returns a map of backend user IDs for a service account
+// Returns an empty map if the service account is not found in cache
+// Map format: {"backend_name_type": "backend_user_id"}
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *ServiceAccountStore) GetBackends(ctx context.Context, name string) (map[string]string, error) {
+	return getBackendsHelper(ctx, s.cache, s.serviceAccountKey(name), "service account")
+}
+
+// SetBackend sets a backend user ID for a service account
+// If the service account doesn't exist, it will be created
+// If the service account exists, the backend ID will be added/updated in the map
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *ServiceAccountStore) SetBackend(ctx context.Context, name, backendKey, backendID string) error {
+	return setBackendHelper(ctx, s.cache, s.serviceAccountKey(name), backendKey, backendID, "service account")
+}
+
+// DeleteBackend removes a specific backend ID from a service account's record
+// If this was the last backend, the entire service account entry is deleted
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *ServiceAccountStore) DeleteBackend(ctx context.Context, name, backendKey string) error {
+	return deleteBackendHelper(ctx, s.cache, s.serviceAccountKey(name), backendKey, "service account")
+}
+
+// Delete removes a service account entirely from cache
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *ServiceAccountStore) Delete(ctx context.Context, name string) error {
+	key := s.serviceAccountKey(name)
+	return s.cache.Delete(ctx, key)
+}
+
+// Exists checks if a service account exists in cache
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *ServiceAccountStore) Exists(ctx context.Context, name string) (bool, error) {
+	return existsHelper(ctx, s.cache, s.serviceAccountKey(name), "service account")
+}
+
+// GetBackendServiceAccounts returns the service accounts created in a backend
+// Returns an empty map if the backend has no service accounts in cache
+// Map format: {"backend_user_id": "name"}
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *ServiceAccountStore) GetBackendServiceAccounts(ctx context.Context, backendKey string) (map[string]string,
+	error) {
+	results, err := s.cache.HGetAllByPattern(ctx, s.serviceAccountKey("*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search service accounts: %w", err)
+	}
+
+	serviceAccounts := make(map[string]string)
+	for key, backends := range results {
+		if backendID := backends[backendKey]; backendID != "" {
+			serviceAccounts[backendID] = strings.TrimPrefix(key, s.serviceAccountKey(""))
+		}
+	}
+	return serviceAccounts, nil
+}
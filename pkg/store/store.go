@@ -1,33 +1,229 @@
 package store
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 )
 
+// ErrGroupExists is returned when a group is renamed to the name of a group already in cache
+var ErrGroupExists = errors.New("group already exists")
+
 // Store provides a high-level interface for managing users, teams, groups, and metadata in cache
-// It encapsulates key prefixing and JSON serialization
+// It encapsulates key prefixing and the layout of the records, which are stored as hashes and sets
+// so that each update is a single atomic cache operation
 // NOTE: This store does NOT handle locking - callers are responsible for proper synchronization
 type Store struct {
-	User       UserStoreInterface
-	Team       TeamStoreInterface  // For preload with transformed team names
-	Group      GroupStoreInterface // For reconciliation with original group names
-	UserGroups UserGroupsStoreInterface
+	User           UserStoreInterface
+	Team           TeamStoreInterface  // For preload with transformed team names
+	Group          GroupStoreInterface // For reconciliation with original group names
+	UserGroups     UserGroupsStoreInterface
+	ServiceAccount ServiceAccountStoreInterface // For service accounts, which bypass LDAP
+
+	cache cache.Cache
 }
 
 // New creates a new Store instance with all sub-stores initialized
 func New(cache cache.Cache) *Store {
 	return &Store{
-		User:       newUserStore(cache),
-		Team:       newTeamStore(cache),
-		Group:      newGroupStore(cache),
-		UserGroups: newUserGroupsStore(cache),
+		User:           newUserStore(cache),
+		Team:           newTeamStore(cache),
+		Group:          newGroupStore(cache),
+		UserGroups:     newUserGroupsStore(cache),
+		ServiceAccount: newServiceAccountStore(cache),
+		cache:          cache,
 	}
 }
 
 // Compile-time interface compliance checks
 var (
-	_ UserStoreInterface       = (*UserStore)(nil)
-	_ TeamStoreInterface       = (*TeamStore)(nil)
-	_ GroupStoreInterface      = (*GroupStore)(nil)
-	_ UserGroupsStoreInterface = (*UserGroupsStore)(nil)
+	_ UserStoreInterface           = (*UserStore)(nil)
+	_ TeamStoreInterface           = (*TeamStore)(nil)
+	_ GroupStoreInterface          = (*GroupStore)(nil)
+	_ UserGroupsStoreInterface     = (*UserGroupsStore)(nil)
+	_ ServiceAccountStoreInterface = (*ServiceAccountStore)(nil)
 )
+
+// RenameGroup moves the group record and the user:groups reverse index entries of its members
+// from oldName to newName. Each step is idempotent, so a rename that failed half way can be retried.
+// Renaming a group that is not in cache is a no-op, renaming it to the name of a group already in
+// cache fails with ErrGroupExists so that the records of the other group are kept.
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *Store) RenameGroup(ctx context.Context, oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+	exists, err := s.Group.Exists(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	taken, err := s.Group.Exists(ctx, newName)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("failed to rename group %s to %s: %w", oldName, newName, ErrGroupExists)
+	}
+
+	data, err := s.Group.Get(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	// Point the reverse index to the new name first, the old group record is only removed
+	// once everything referencing it has been moved
+	for _, uid := range data.Members {
+		if err := s.UserGroups.AddGroup(ctx, uid, newName); err != nil {
+			return fmt.Errorf("failed to add renamed group to user groups index: %w", err)
+		}
+		if err := s.UserGroups.RemoveGroup(ctx, uid, oldName); err != nil {
+			return fmt.Errorf("failed to remove old group from user groups index: %w", err)
+		}
+	}
+
+	// The group record is moved in a single atomic operation
+	renamed, err := s.Group.Rename(ctx, oldName, newName)
+	if err != nil {
+		return err
+	}
+	if !renamed {
+		return fmt.Errorf("failed to rename group %s to %s: %w", oldName, newName, ErrGroupExists)
+	}
+	return nil
+}
+
+// IndexBackendUsers adds the backend IDs of the cached users missing from the reverse index of their
+// backend, e.g. the users cached before the index was maintained, and returns the number of IDs indexed.
+// An ID already linked to a user is left untouched.
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *Store) IndexBackendUsers(ctx context.Context) (int, error) {
+	users, err := s.User.GetByPattern(ctx, "*")
+	if err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	for uid, backends := range users {
+		for backendKey, backendID := range backends {
+			owner, err := s.User.GetByBackendID(ctx, backendKey, backendID)
+			if err != nil {
+				return indexed, err
+			}
+			if owner != "" {
+				continue
+			}
+			if err := s.cache.HSet(ctx, backendUsersKey(backendKey), map[string]string{backendID: uid}); err != nil {
+				return indexed, fmt.Errorf("failed to index backend user in cache: %w", err)
+			}
+			indexed++
+		}
+	}
+	return indexed, nil
+}
+
+// RekeyUser moves the records of a user identified by oldKey, e.g. a backend user identified by its email
+// before it was linked to its uid, to uid: its backend IDs, its user:groups reverse index entries and its
+// membership of the groups. The backend IDs already recorded for uid are kept. Each step is idempotent,
+// so a move that failed half way can be retried.
+// NOTE: Caller must hold the locks of both users
+func (s *Store) RekeyUser(ctx context.Context, oldKey, uid string) error {
+	if oldKey == uid {
+		return nil
+	}
+
+	oldBackends, err := s.User.GetBackends(ctx, oldKey)
+	if err != nil {
+		return err
+	}
+	backends, err := s.User.GetBackends(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for backendKey, backendID := range oldBackends {
+		if _, exists := backends[backendKey]; exists {
+			continue
+		}
+		if err := s.User.SetBackend(ctx, uid, backendKey, backendID); err != nil {
+			return err
+		}
+	}
+
+	groups, err := s.UserGroups.GetGroups(ctx, oldKey)
+	if err != nil {
+		return err
+	}
+	for _, groupName := range groups {
+		if err := s.UserGroups.AddGroup(ctx, uid, groupName); err != nil {
+			return fmt.Errorf("failed to add group to user groups index: %w", err)
+		}
+		members, err := s.Group.GetMembers(ctx, groupName)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(members, oldKey) {
+			continue
+		}
+		members = slices.DeleteFunc(members, func(member string) bool { return member == oldKey })
+		if !slices.Contains(members, uid) {
+			members = append(members, uid)
+		}
+		if err := s.Group.SetMembers(ctx, groupName, members); err != nil {
+			return err
+		}
+	}
+
+	// the old records are only removed once everything has been moved
+	if err := s.UserGroups.Delete(ctx, oldKey); err != nil {
+		return fmt.Errorf("failed to delete user groups index: %w", err)
+	}
+	return s.User.Delete(ctx, oldKey)
+}
+
+// UIDResolver returns the uid of the user with an email, or an empty string if there is none
+type UIDResolver func(ctx context.Context, email string) (string, error)
+
+// LinkUserIdentities moves the users keyed by their email, e.g. those cached by previous versions,
+// to the uid resolveUID returns for the email and links the email to the uid. It returns the number
+// of users moved. The users whose uid is not found are kept under their email.
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *Store) LinkUserIdentities(ctx context.Context, resolveUID UIDResolver) (int, error) {
+	users, err := s.User.GetByPattern(ctx, "*")
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for userKey := range users {
+		email, err := s.User.GetEmail(ctx, userKey)
+		if err != nil {
+			return linked, err
+		}
+		if email != "" {
+			// already keyed by its uid
+			continue
+		}
+
+		uid, err := resolveUID(ctx, userKey)
+		if err != nil {
+			return linked, fmt.Errorf("failed to resolve the uid of user %s: %w", userKey, err)
+		}
+		if uid == "" {
+			continue
+		}
+		if err := s.RekeyUser(ctx, userKey, uid); err != nil {
+			return linked, err
+		}
+		if err := s.User.SetEmail(ctx, uid, userKey); err != nil {
+			return linked, err
+		}
+		linked++
+	}
+	return linked, nil
+}
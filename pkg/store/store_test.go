@@ -2,8 +2,12 @@ package store
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"sync"
 	"testing"
 
+	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache/inmemory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +33,7 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, store.Team)
 	assert.NotNil(t, store.Group)
 	assert.NotNil(t, store.UserGroups)
+	assert.NotNil(t, store.ServiceAccount)
 }
 
 func TestStore_InterfaceCompliance(t *testing.T) {
@@ -55,6 +60,9 @@ func TestStore_InterfaceCompliance(t *testing.T) {
 
 	// Verify UserGroups implements UserGroupsStoreInterface
 	var _ UserGroupsStoreInterface = store.UserGroups
+
+	// Verify ServiceAccount implements ServiceAccountStoreInterface
+	var _ ServiceAccountStoreInterface = store.ServiceAccount
 }
 
 func TestStore_IndependentOperations(t *testing.T) {
@@ -123,3 +131,297 @@ func TestStore_KeyNamespacing(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "id2", groupBackends["backend1_backend1"].ID)
 }
+
+func TestStore_ServiceAccountsAreNotUsers(t *testing.T) {
+	c, err := inmemory.NewCache(&inmemory.Config{
+		DefaultExpiration: 300,
+		CleanupInterval:   600,
+	})
+	require.NoError(t, err)
+
+	store := New(c)
+	ctx := testContext(t)
+
+	err = store.ServiceAccount.SetBackend(ctx, "ci-bot", "gitlab_gitlab", "42")
+	require.NoError(t, err)
+	err = store.ServiceAccount.SetBackend(ctx, "ci-bot", "snowflake_snowflake", "CI_BOT")
+	require.NoError(t, err)
+
+	backends, err := store.ServiceAccount.GetBackends(ctx, "ci-bot")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gitlab_gitlab": "42", "snowflake_snowflake": "CI_BOT"}, backends)
+
+	serviceAccounts, err := store.ServiceAccount.GetBackendServiceAccounts(ctx, "gitlab_gitlab")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"42": "ci-bot"}, serviceAccounts)
+
+	// the user offboarding job scans the users, service accounts must not show up there
+	users, err := store.User.GetByPattern(ctx, "*")
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	err = store.ServiceAccount.DeleteBackend(ctx, "ci-bot", "gitlab_gitlab")
+	require.NoError(t, err)
+	err = store.ServiceAccount.DeleteBackend(ctx, "ci-bot", "snowflake_snowflake")
+	require.NoError(t, err)
+	exists, err := store.ServiceAccount.Exists(ctx, "ci-bot")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestStore_RenameGroup(t *testing.T) {
+	c, err := inmemory.NewCache(&inmemory.Config{
+		DefaultExpiration: 300,
+		CleanupInterval:   600,
+	})
+	require.NoError(t, err)
+
+	store := New(c)
+	ctx := testContext(t)
+
+	err = store.Group.SetBackend(ctx, "old-team", "fivetran", "fivetran", "team_456")
+	require.NoError(t, err)
+	err = store.Group.SetMembers(ctx, "old-team", []string{"user@example.com"})
+	require.NoError(t, err)
+	err = store.UserGroups.SetGroups(ctx, "user@example.com", []string{"other-team", "old-team"})
+	require.NoError(t, err)
+
+	err = store.RenameGroup(ctx, "old-team", "new-team")
+	require.NoError(t, err)
+
+	exists, err := store.Group.Exists(ctx, "old-team")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	data, err := store.Group.Get(ctx, "new-team")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user@example.com"}, data.Members)
+	assert.Equal(t, "team_456", data.Backends["fivetran_fivetran"].ID)
+
+	userGroups, err := store.UserGroups.GetGroups(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"other-team", "new-team"}, userGroups)
+
+	// Renaming again is a no-op as the old group is gone
+	err = store.RenameGroup(ctx, "old-team", "new-team")
+	require.NoError(t, err)
+	data, err = store.Group.Get(ctx, "new-team")
+	require.NoError(t, err)
+	assert.Equal(t, "team_456", data.Backends["fivetran_fivetran"].ID)
+
+	// Renaming onto a group already in cache keeps the records of both groups
+	err = store.Group.SetBackend(ctx, "other-team", "fivetran", "fivetran", "team_789")
+	require.NoError(t, err)
+	err = store.RenameGroup(ctx, "other-team", "new-team")
+	assert.ErrorIs(t, err, ErrGroupExists)
+	data, err = store.Group.Get(ctx, "new-team")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user@example.com"}, data.Members)
+	assert.Equal(t, "team_456", data.Backends["fivetran_fivetran"].ID)
+	exists, err = store.Group.Exists(ctx, "other-team")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestStore_ConcurrentBackendUpdates(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	// backends of a group processed concurrently update the same user and group records
+	backendKeys := []string{"fivetran_fivetran", "gitlab_gitlab", "rover_rover", "snowflake_snowflake"}
+	var wg sync.WaitGroup
+	for _, backendKey := range backendKeys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.User.SetBackend(ctx, "alice@example.com", backendKey, "id-"+backendKey))
+			assert.NoError(t, store.Group.SetBackend(ctx, "team-a", backendKey, backendKey, "team-"+backendKey))
+		}()
+	}
+	wg.Wait()
+
+	userBackends, err := store.User.GetBackends(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Len(t, userBackends, len(backendKeys))
+
+	groupBackends, err := store.Group.GetBackends(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Len(t, groupBackends, len(backendKeys))
+}
+
+func TestStore_ConvertLegacyRecords(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	// records written as JSON strings by previous versions
+	legacy := map[string]string{
+		"user:alice@example.com":        `{"fivetran_fivetran":"user_1"}`,
+		"user:groups:alice@example.com": `["team-a","team-b"]`,
+		"team:team-a":                   `{"fivetran_fivetran":"team_1"}`,
+		"serviceaccount:bot":            `{"gitlab_gitlab":"user_2"}`,
+		"group:team-a": `{"members":["alice@example.com"],"backends":{"fivetran_fivetran":` +
+			`{"id":"team_1","name":"fivetran","type":"fivetran","nested_teams":["team_2"]}}}`,
+	}
+	for key, value := range legacy {
+		require.NoError(t, c.Set(ctx, key, value, cache.NoExpiration))
+	}
+	// a record already converted is left as is
+	require.NoError(t, store.User.SetBackend(ctx, "bob@example.com", "gitlab_gitlab", "user_3"))
+
+	converted, err := store.ConvertLegacyRecords(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(legacy), converted)
+
+	userBackends, err := store.User.GetBackends(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fivetran_fivetran": "user_1"}, userBackends)
+	userBackends, err = store.User.GetBackends(ctx, "bob@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gitlab_gitlab": "user_3"}, userBackends)
+
+	groups, err := store.UserGroups.GetGroups(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, groups)
+
+	teamBackends, err := store.Team.GetBackends(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fivetran_fivetran": "team_1"}, teamBackends)
+
+	saBackends, err := store.ServiceAccount.GetBackends(ctx, "bot")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gitlab_gitlab": "user_2"}, saBackends)
+
+	data, err := store.Group.Get(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, data.Members)
+	assert.Equal(t, []string{"team_2"}, data.Backends["fivetran_fivetran"].NestedTeams)
+
+	// running it again is a no-op
+	converted, err = store.ConvertLegacyRecords(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, converted)
+}
+
+func TestStore_ConvertLegacyRecords_InvalidJSON(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	require.NoError(t, c.Set(ctx, "user:alice@example.com", "invalid json", cache.NoExpiration))
+
+	_, err = store.ConvertLegacyRecords(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "user:alice@example.com")
+}
+
+func TestStore_IndexBackendUsers(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	// a user cached before the index was maintained
+	require.NoError(t, c.HSet(ctx, "user:alice@example.com", map[string]string{"snowflake_prod": "ALICE"}))
+	require.NoError(t, store.User.SetBackend(ctx, "bob@example.com", "snowflake_prod", "BOB"))
+
+	indexed, err := store.IndexBackendUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, indexed)
+
+	users, err := store.User.GetBackendUsers(ctx, "snowflake_prod")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ALICE": "alice@example.com", "BOB": "bob@example.com"}, users)
+
+	indexed, err = store.IndexBackendUsers(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, indexed)
+}
+
+func TestStore_RekeyUser(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	// alice was cached by email, and already has a snowflake user under her uid
+	require.NoError(t, store.User.SetBackend(ctx, "alice@example.com", "fivetran_prod", "user_1"))
+	require.NoError(t, store.User.SetBackend(ctx, "alice@example.com", "snowflake_prod", "ALICE_OLD"))
+	require.NoError(t, store.User.SetBackend(ctx, "alice", "snowflake_prod", "ALICE"))
+	require.NoError(t, store.UserGroups.AddGroup(ctx, "alice@example.com", "team-a"))
+	require.NoError(t, store.UserGroups.AddGroup(ctx, "alice", "team-b"))
+	require.NoError(t, store.Group.SetMembers(ctx, "team-a", []string{"alice@example.com", "bob"}))
+	require.NoError(t, store.Group.SetMembers(ctx, "team-b", []string{"alice"}))
+
+	require.NoError(t, store.RekeyUser(ctx, "alice@example.com", "alice"))
+	// moving the user again is a no-op
+	require.NoError(t, store.RekeyUser(ctx, "alice@example.com", "alice"))
+
+	exists, err := store.User.Exists(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	backends, err := store.User.GetBackends(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fivetran_prod": "user_1", "snowflake_prod": "ALICE"}, backends)
+
+	owner, err := store.User.GetByBackendID(ctx, "fivetran_prod", "user_1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", owner)
+
+	groups, err := store.UserGroups.GetGroups(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, groups)
+	groups, err = store.UserGroups.GetGroups(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	members, err := store.Group.GetMembers(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob", "alice"}, members)
+	members, err = store.Group.GetMembers(ctx, "team-b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, members)
+}
+
+func TestStore_LinkUserIdentities(t *testing.T) {
+	ctx := testContext(t)
+	c, err := inmemory.NewCache(nil)
+	require.NoError(t, err)
+	store := New(c)
+
+	require.NoError(t, store.User.SetBackend(ctx, "alice@example.com", "fivetran_prod", "user_1"))
+	require.NoError(t, store.UserGroups.AddGroup(ctx, "alice@example.com", "team-a"))
+	// bob is already keyed by his uid, carol is not in LDAP
+	require.NoError(t, store.User.SetBackend(ctx, "bob", "fivetran_prod", "user_2"))
+	require.NoError(t, store.User.SetEmail(ctx, "bob", "bob@example.com"))
+	require.NoError(t, store.User.SetBackend(ctx, "carol@example.com", "fivetran_prod", "user_3"))
+
+	resolved := make(map[string]int)
+	resolveUID := func(ctx context.Context, email string) (string, error) {
+		resolved[email]++
+		return map[string]string{"alice@example.com": "alice"}[email], nil
+	}
+
+	linked, err := store.LinkUserIdentities(ctx, resolveUID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, linked)
+	assert.Equal(t, map[string]int{"alice@example.com": 1, "carol@example.com": 1}, resolved)
+
+	users, err := store.User.GetByPattern(ctx, "*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob", "carol@example.com"}, slices.Collect(maps.Keys(users)))
+	groups, err := store.UserGroups.GetGroups(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, groups)
+	uid, err := store.User.GetUIDByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", uid)
+
+	linked, err = store.LinkUserIdentities(ctx, resolveUID)
+	require.NoError(t, err)
+	assert.Zero(t, linked)
+}
@@ -2,18 +2,17 @@ package store
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 )
 
 // TeamStore handles team-related cache operations with "team:" prefix
 // Key format: "team:<transformedTeamName>"
-// Value: JSON map of {"backend_name_type": "backend_team_id"}
+// Value: hash of {"backend_name_type": "backend_team_id"}
 // This store is used for preloading team data from backends where teams
 // are identified by their transformed names.
-// NOTE: This store does NOT handle locking - callers must ensure proper synchronization
+// NOTE: Each update is a single atomic cache operation, callers must still synchronize
+// sequences of operations that have to be consistent with each other
 type TeamStore struct {
 	cache cache.Cache
 }
@@ -35,19 +34,7 @@ func (s *TeamStore) teamKey(teamName string) string {
 // Map format: {"backend_name_type": "backend_team_id"}
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *TeamStore) GetBackends(ctx context.Context, teamName string) (map[string]string, error) {
-	key := s.teamKey(teamName)
-	val, err := s.cache.Get(ctx, key)
-	if err != nil {
-		// Team not found, return empty map (not an error condition)
-		return make(map[string]string), nil
-	}
-
-	var backends map[string]string
-	if err := json.Unmarshal([]byte(val.(string)), &backends); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal team backends: %w", err)
-	}
-
-	return backends, nil
+	return getBackendsHelper(ctx, s.cache, s.teamKey(teamName), "team")
 }
 
 // SetBackend sets a backend ID for a team
@@ -55,40 +42,14 @@ func (s *TeamStore) GetBackends(ctx context.Context, teamName string) (map[strin
 // If the team exists, the backend ID will be added/updated in the map
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *TeamStore) SetBackend(ctx context.Context, teamName, backendKey, teamID string) error {
-	key := s.teamKey(teamName)
-
-	// Get existing backends or create new map
-	backends := make(map[string]string)
-	val, err := s.cache.Get(ctx, key)
-	if err == nil {
-		// Team exists, unmarshal existing data
-		if err := json.Unmarshal([]byte(val.(string)), &backends); err != nil {
-			return fmt.Errorf("failed to unmarshal existing team backends: %w", err)
-		}
-	}
-
-	// Update the backend ID
-	backends[backendKey] = teamID
-
-	// Marshal and store back
-	data, err := json.Marshal(backends)
-	if err != nil {
-		return fmt.Errorf("failed to marshal team backends: %w", err)
-	}
-
-	if err := s.cache.Set(ctx, key, string(data), cache.NoExpiration); err != nil {
-		return fmt.Errorf("failed to set team in cache: %w", err)
-	}
-
-	return nil
+	return setBackendHelper(ctx, s.cache, s.teamKey(teamName), backendKey, teamID, "team")
 }
 
 // DeleteBackend removes a specific backend ID from a team's record
 // If this was the last backend, the entire team entry is deleted
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *TeamStore) DeleteBackend(ctx context.Context, teamName, backendKey string) error {
-	key := s.teamKey(teamName)
-	return deleteBackendHelper(ctx, s.cache, key, backendKey, "team")
+	return deleteBackendHelper(ctx, s.cache, s.teamKey(teamName), backendKey, "team")
 }
 
 // Delete removes a team entirely from cache
@@ -101,10 +62,5 @@ func (s *TeamStore) Delete(ctx context.Context, teamName string) error {
 // Exists checks if a team exists in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *TeamStore) Exists(ctx context.Context, teamName string) (bool, error) {
-	key := s.teamKey(teamName)
-	_, err := s.cache.Get(ctx, key)
-	if err != nil {
-		return false, nil
-	}
-	return true, nil
+	return existsHelper(ctx, s.cache, s.teamKey(teamName), "team")
 }
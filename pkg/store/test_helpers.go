@@ -22,7 +22,7 @@ type EntityStoreInterface interface {
 // SetBackendTestCase defines a test case for SetBackend operations
 type SetBackendTestCase struct {
 	Name        string
-	Identifier  string // email for users, name for teams
+	Identifier  string // uid for users, name for teams
 	BackendKey  string
 	BackendID   string
 	SetupFunc   func(t *testing.T, store EntityStoreInterface)
@@ -2,16 +2,17 @@ package store
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/redhat-data-and-ai/usernaut/pkg/cache"
 )
 
 // UserGroupsStore handles user-to-groups reverse index cache operations
-// Key format: "user:groups:<email>"
-// Value: JSON array of group names
-// NOTE: This store does NOT handle locking - callers must ensure proper synchronization
+// Key format: "user:groups:<uid>"
+// Value: set of group names
+// NOTE: Each update is a single atomic cache operation, callers must still synchronize
+// sequences of operations that have to be consistent with each other
 type UserGroupsStore struct {
 	cache cache.Cache
 }
@@ -24,144 +25,70 @@ func newUserGroupsStore(c cache.Cache) *UserGroupsStore {
 }
 
 // userGroupsKey returns the prefixed cache key for user's groups
-func (s *UserGroupsStore) userGroupsKey(email string) string {
-	return "user:groups:" + email
+func (s *UserGroupsStore) userGroupsKey(uid string) string {
+	return "user:groups:" + uid
 }
 
-// GetGroups returns the list of groups for a user
+// GetGroups returns the sorted list of groups for a user
 // Returns an empty slice if the user is not found in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserGroupsStore) GetGroups(ctx context.Context, email string) ([]string, error) {
-	key := s.userGroupsKey(email)
-	val, err := s.cache.Get(ctx, key)
+func (s *UserGroupsStore) GetGroups(ctx context.Context, uid string) ([]string, error) {
+	key := s.userGroupsKey(uid)
+	groups, err := s.cache.SMembers(ctx, key)
 	if err != nil {
-		// User not found, return empty slice (not an error condition)
-		return []string{}, nil
-	}
-
-	var groups []string
-	if err := json.Unmarshal([]byte(val.(string)), &groups); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal user groups: %w", err)
-	}
-
-	// Ensure we always return an empty slice instead of nil
-	if groups == nil {
-		return []string{}, nil
+		return nil, fmt.Errorf("failed to get user groups from cache: %w", err)
 	}
 
+	slices.Sort(groups)
 	return groups, nil
 }
 
 // AddGroup adds a group to a user's group list if not already present
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserGroupsStore) AddGroup(ctx context.Context, email, groupName string) error {
-	key := s.userGroupsKey(email)
-
-	// Get existing groups
-	groups, err := s.GetGroups(ctx, email)
-	if err != nil {
-		return err
-	}
-
-	// Check if group already exists
-	for _, g := range groups {
-		if g == groupName {
-			// Group already exists, nothing to do
-			return nil
-		}
-	}
-
-	// Add the new group
-	groups = append(groups, groupName)
-
-	// Marshal and store
-	data, err := json.Marshal(groups)
-	if err != nil {
-		return fmt.Errorf("failed to marshal user groups: %w", err)
+func (s *UserGroupsStore) AddGroup(ctx context.Context, uid, groupName string) error {
+	key := s.userGroupsKey(uid)
+	if err := s.cache.SAdd(ctx, key, groupName); err != nil {
+		return fmt.Errorf("failed to add group to user groups in cache: %w", err)
 	}
-
-	if err := s.cache.Set(ctx, key, string(data), cache.NoExpiration); err != nil {
-		return fmt.Errorf("failed to set user groups in cache: %w", err)
-	}
-
 	return nil
 }
 
 // SetGroups sets the complete list of groups for a user
-// This replaces any existing groups
+// This replaces any existing groups atomically
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserGroupsStore) SetGroups(ctx context.Context, email string, groups []string) error {
-	key := s.userGroupsKey(email)
-
-	data, err := json.Marshal(groups)
-	if err != nil {
-		return fmt.Errorf("failed to marshal user groups: %w", err)
-	}
-
-	if err := s.cache.Set(ctx, key, string(data), cache.NoExpiration); err != nil {
+func (s *UserGroupsStore) SetGroups(ctx context.Context, uid string, groups []string) error {
+	key := s.userGroupsKey(uid)
+	if err := s.cache.SReplace(ctx, key, groups...); err != nil {
 		return fmt.Errorf("failed to set user groups in cache: %w", err)
 	}
-
 	return nil
 }
 
 // RemoveGroup removes a specific group from a user's group list
 // If this was the last group, the entry is deleted
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserGroupsStore) RemoveGroup(ctx context.Context, email, groupName string) error {
-	key := s.userGroupsKey(email)
-
-	// Get existing groups
-	groups, err := s.GetGroups(ctx, email)
-	if err != nil {
-		return err
-	}
-
-	// If no groups, nothing to remove
-	if len(groups) == 0 {
-		return nil
-	}
-
-	// Find and remove the group
-	newGroups := make([]string, 0, len(groups))
-	for _, g := range groups {
-		if g != groupName {
-			newGroups = append(newGroups, g)
-		}
+func (s *UserGroupsStore) RemoveGroup(ctx context.Context, uid, groupName string) error {
+	key := s.userGroupsKey(uid)
+	if err := s.cache.SRem(ctx, key, groupName); err != nil {
+		return fmt.Errorf("failed to remove group from user groups in cache: %w", err)
 	}
-
-	// If no groups left, delete the entry
-	if len(newGroups) == 0 {
-		return s.cache.Delete(ctx, key)
-	}
-
-	// Update with remaining groups
-	data, err := json.Marshal(newGroups)
-	if err != nil {
-		return fmt.Errorf("failed to marshal user groups: %w", err)
-	}
-
-	if err := s.cache.Set(ctx, key, string(data), cache.NoExpiration); err != nil {
-		return fmt.Errorf("failed to update user groups in cache: %w", err)
-	}
-
 	return nil
 }
 
 // Delete removes the user's groups entry entirely
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserGroupsStore) Delete(ctx context.Context, email string) error {
-	key := s.userGroupsKey(email)
+func (s *UserGroupsStore) Delete(ctx context.Context, uid string) error {
+	key := s.userGroupsKey(uid)
 	return s.cache.Delete(ctx, key)
 }
 
 // Exists checks if a user has any groups in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserGroupsStore) Exists(ctx context.Context, email string) (bool, error) {
-	key := s.userGroupsKey(email)
-	_, err := s.cache.Get(ctx, key)
+func (s *UserGroupsStore) Exists(ctx context.Context, uid string) (bool, error) {
+	key := s.userGroupsKey(uid)
+	groups, err := s.cache.SMembers(ctx, key)
 	if err != nil {
-		return false, nil
+		return false, fmt.Errorf("failed to get user groups from cache: %w", err)
 	}
-	return true, nil
+	return len(groups) > 0, nil
 }
@@ -60,11 +60,11 @@ func TestUserGroupsStore_GetGroups(t *testing.T) {
 				err = store.AddGroup(ctx, "user@example.com", "ml-team")
 				require.NoError(t, err)
 			},
-			want:    []string{"data-team", "platform-team", "ml-team"},
+			want:    []string{"data-team", "ml-team", "platform-team"},
 			wantErr: false,
 		},
 		{
-			name:  "invalid JSON returns error",
+			name:  "record of the wrong type returns error",
 			email: "invalid@example.com",
 			setup: func(t *testing.T, store *UserGroupsStore, c cache.Cache) {
 				err := c.Set(context.Background(), "user:groups:invalid@example.com", "invalid json{{{", cache.NoExpiration)
@@ -72,7 +72,7 @@ func TestUserGroupsStore_GetGroups(t *testing.T) {
 			},
 			want:        nil,
 			wantErr:     true,
-			errContains: "failed to unmarshal",
+			errContains: "failed to get user groups",
 		},
 	}
 
@@ -415,11 +415,12 @@ func TestUserGroupsStore_KeyPrefix(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify key has correct prefix
-	val, err := c.Get(ctx, "user:groups:user@example.com")
+	val, err := c.SMembers(ctx, "user:groups:user@example.com")
 	assert.NoError(t, err)
-	assert.NotNil(t, val)
+	assert.Equal(t, []string{"data-team"}, val)
 
 	// Verify key without prefix doesn't exist
-	_, err = c.Get(ctx, "user@example.com")
-	assert.Error(t, err)
+	val, err = c.SMembers(ctx, "user@example.com")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
 }
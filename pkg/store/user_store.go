@@ -2,7 +2,6 @@ package store
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,7 +9,19 @@ import (
 )
 
 // UserStore handles all user-related cache operations with "user:" prefix
-// NOTE: This store does NOT handle locking - callers must ensure proper synchronization
+// The users are identified by their LDAP uid, which doesn't change when their email does. The
+// backend users whose email is not linked to a uid yet, e.g. those found by the preload, are
+// identified by their email until they are moved to their uid with Store.RekeyUser.
+// Key format: "user:<uid>"
+// Value: hash of {"backend_name_type": "backend_user_id"}
+// The store also maintains the reverse index from the backend user IDs to the users:
+// Key format: "backenduser:<backend_name_type>"
+// Value: hash of {"backend_user_id": "uid"}
+// and the identity records linking the uids and the emails of the users:
+// Key format: "identity:<uid>", value: hash of {"email": "email"}
+// Key format: "email:<email>", value: hash of {"uid": "uid"}
+// NOTE: Each update is a single atomic cache operation, callers must still synchronize
+// sequences of operations that have to be consistent with each other
 type UserStore struct {
 	cache cache.Cache
 }
@@ -23,118 +34,233 @@ func newUserStore(c cache.Cache) *UserStore {
 }
 
 // userKey returns the prefixed cache key for a user
-func (s *UserStore) userKey(email string) string {
-	return "user:" + email
+func (s *UserStore) userKey(uid string) string {
+	return "user:" + uid
+}
+
+// backendUsersKey returns the prefixed cache key of the reverse index of a backend
+func backendUsersKey(backendKey string) string {
+	return "backenduser:" + backendKey
+}
+
+// identityKey returns the prefixed cache key of the identity record of a user
+func identityKey(uid string) string {
+	return "identity:" + uid
+}
+
+// emailKey returns the prefixed cache key of the record linking an email to the uid of its user
+func emailKey(email string) string {
+	return "email:" + email
 }
 
 // GetBackends returns a map of backend IDs for a user
 // Returns an empty map if the user is not found in cache
 // Map format: {"backend_name_type": "backend_user_id"}
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserStore) GetBackends(ctx context.Context, email string) (map[string]string, error) {
-	key := s.userKey(email)
-	val, err := s.cache.Get(ctx, key)
-	if err != nil {
-		// User not found, return empty map (not an error condition)
-		return make(map[string]string), nil
-	}
-
-	var backends map[string]string
-	if err := json.Unmarshal([]byte(val.(string)), &backends); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal user backends: %w", err)
-	}
-
-	return backends, nil
+func (s *UserStore) GetBackends(ctx context.Context, uid string) (map[string]string, error) {
+	return getBackendsHelper(ctx, s.cache, s.userKey(uid), "user")
 }
 
 // SetBackend sets a backend ID for a user
 // If the user doesn't exist, it will be created
 // If the user exists, the backend ID will be added/updated in the map
+// The reverse index is updated to point the backend ID to the user
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserStore) SetBackend(ctx context.Context, email, backendKey, backendID string) error {
-	key := s.userKey(email)
-
-	// Get existing backends or create new map
-	backends := make(map[string]string)
-	val, err := s.cache.Get(ctx, key)
-	if err == nil {
-		// User exists, unmarshal existing data
-		if err := json.Unmarshal([]byte(val.(string)), &backends); err != nil {
-			return fmt.Errorf("failed to unmarshal existing user backends: %w", err)
+func (s *UserStore) SetBackend(ctx context.Context, uid, backendKey, backendID string) error {
+	previousID, err := s.cache.HGet(ctx, s.userKey(uid), backendKey)
+	if err != nil {
+		return fmt.Errorf("failed to get user backend from cache: %w", err)
+	}
+
+	// The index is updated first, the user record still references the previous ID if an update fails
+	// so that retrying it removes that ID from the index
+	if previousID != "" && previousID != backendID {
+		if err := s.unindexBackendUser(ctx, uid, backendKey, previousID); err != nil {
+			return err
 		}
 	}
+	if err := s.cache.HSet(ctx, backendUsersKey(backendKey), map[string]string{backendID: uid}); err != nil {
+		return fmt.Errorf("failed to index backend user in cache: %w", err)
+	}
+	return setBackendHelper(ctx, s.cache, s.userKey(uid), backendKey, backendID, "user")
+}
 
-	// Update the backend ID
-	backends[backendKey] = backendID
+// DeleteBackend removes a specific backend ID from a user's record
+// If this was the last backend, the entire user entry is deleted
+// The backend ID is removed from the reverse index
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *UserStore) DeleteBackend(ctx context.Context, uid, backendKey string) error {
+	backendID, err := s.cache.HGet(ctx, s.userKey(uid), backendKey)
+	if err != nil {
+		return fmt.Errorf("failed to get user backend from cache: %w", err)
+	}
 
-	// Marshal and store back
-	data, err := json.Marshal(backends)
+	if backendID != "" {
+		if err := s.unindexBackendUser(ctx, uid, backendKey, backendID); err != nil {
+			return err
+		}
+	}
+	return deleteBackendHelper(ctx, s.cache, s.userKey(uid), backendKey, "user")
+}
+
+// Delete removes a user entirely from cache, along with its backend IDs in the reverse index
+// and its identity record
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *UserStore) Delete(ctx context.Context, uid string) error {
+	backends, err := s.GetBackends(ctx, uid)
 	if err != nil {
-		return fmt.Errorf("failed to marshal user backends: %w", err)
+		return err
 	}
 
-	if err := s.cache.Set(ctx, key, string(data), cache.NoExpiration); err != nil {
-		return fmt.Errorf("failed to set user in cache: %w", err)
+	for backendKey, backendID := range backends {
+		if err := s.unindexBackendUser(ctx, uid, backendKey, backendID); err != nil {
+			return err
+		}
+	}
+	if err := s.unlinkEmail(ctx, uid); err != nil {
+		return err
 	}
+	if err := s.cache.Delete(ctx, identityKey(uid)); err != nil {
+		return fmt.Errorf("failed to delete user identity from cache: %w", err)
+	}
+	key := s.userKey(uid)
+	return s.cache.Delete(ctx, key)
+}
 
+// unindexBackendUser removes a backend ID from the reverse index, unless it was linked to another user since
+func (s *UserStore) unindexBackendUser(ctx context.Context, uid, backendKey, backendID string) error {
+	owner, err := s.cache.HGet(ctx, backendUsersKey(backendKey), backendID)
+	if err != nil {
+		return fmt.Errorf("failed to get backend user from cache: %w", err)
+	}
+	if owner != uid {
+		return nil
+	}
+	if err := s.cache.HDel(ctx, backendUsersKey(backendKey), backendID); err != nil {
+		return fmt.Errorf("failed to unindex backend user in cache: %w", err)
+	}
 	return nil
 }
 
-// DeleteBackend removes a specific backend ID from a user's record
-// If this was the last backend, the entire user entry is deleted
+// Exists checks if a user exists in cache
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserStore) DeleteBackend(ctx context.Context, email, backendKey string) error {
-	key := s.userKey(email)
-	return deleteBackendHelper(ctx, s.cache, key, backendKey, "user")
+func (s *UserStore) Exists(ctx context.Context, uid string) (bool, error) {
+	return existsHelper(ctx, s.cache, s.userKey(uid), "user")
 }
 
-// Delete removes a user entirely from cache
+// GetByBackendID returns the uid of the user owning a backend user ID
+// Returns an empty string if no user owns it
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserStore) Delete(ctx context.Context, email string) error {
-	key := s.userKey(email)
-	return s.cache.Delete(ctx, key)
+func (s *UserStore) GetByBackendID(ctx context.Context, backendKey, backendID string) (string, error) {
+	uid, err := s.cache.HGet(ctx, backendUsersKey(backendKey), backendID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get backend user from cache: %w", err)
+	}
+	return uid, nil
 }
 
-// Exists checks if a user exists in cache
+// GetBackendUsers returns the users of a backend
+// Returns an empty map if the backend has no users in cache
+// Map format: {"backend_user_id": "uid"}
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
-func (s *UserStore) Exists(ctx context.Context, email string) (bool, error) {
-	key := s.userKey(email)
-	_, err := s.cache.Get(ctx, key)
+func (s *UserStore) GetBackendUsers(ctx context.Context, backendKey string) (map[string]string, error) {
+	users, err := s.cache.HGetAll(ctx, backendUsersKey(backendKey))
 	if err != nil {
-		return false, nil
+		return nil, fmt.Errorf("failed to get backend users from cache: %w", err)
 	}
-	return true, nil
+	return users, nil
 }
 
 // GetByPattern searches for users matching a pattern and returns their data
 // Pattern should NOT include the "user:" prefix - it will be added automatically
-// Example: pattern "*@example.com" searches for "user:*@example.com"
-// Returns: map[email]backends where backends is map[backendKey]backendID
+// Example: pattern "j*" searches for "user:j*"
+// Returns: map[uid]backends where backends is map[backendKey]backendID
+// The "user:groups:" reverse index entries are sets, so they are never returned
 // NOTE: Caller must hold appropriate lock if concurrent access is possible
 func (s *UserStore) GetByPattern(ctx context.Context, pattern string) (map[string]map[string]string, error) {
 	// Add user: prefix to the pattern
 	fullPattern := "user:" + pattern
 
-	// Use cache's GetByPattern to find matching keys
-	results, err := s.cache.GetByPattern(ctx, fullPattern)
+	results, err := s.cache.HGetAllByPattern(ctx, fullPattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search users by pattern: %w", err)
 	}
 
-	// Parse results into structured format
-	userMap := make(map[string]map[string]string)
-	for key, value := range results {
-		// Extract email from key (remove "user:" prefix)
-		email := strings.TrimPrefix(key, "user:")
+	userMap := make(map[string]map[string]string, len(results))
+	for key, backends := range results {
+		// Extract the uid from key (remove "user:" prefix)
+		userMap[strings.TrimPrefix(key, "user:")] = backends
+	}
 
-		var backends map[string]string
-		if err := json.Unmarshal([]byte(value.(string)), &backends); err != nil {
-			// Skip invalid entries
-			continue
-		}
+	return userMap, nil
+}
 
-		userMap[email] = backends
+// GetEmail returns the email of a user
+// Returns an empty string if the user has no identity record
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *UserStore) GetEmail(ctx context.Context, uid string) (string, error) {
+	email, err := s.cache.HGet(ctx, identityKey(uid), "email")
+	if err != nil {
+		return "", fmt.Errorf("failed to get user identity from cache: %w", err)
 	}
+	return email, nil
+}
 
-	return userMap, nil
+// GetUIDByEmail returns the uid of the user with an email
+// Returns an empty string if the email is not linked to a user
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *UserStore) GetUIDByEmail(ctx context.Context, email string) (string, error) {
+	uid, err := s.cache.HGet(ctx, emailKey(email), "uid")
+	if err != nil {
+		return "", fmt.Errorf("failed to get user email from cache: %w", err)
+	}
+	return uid, nil
+}
+
+// SetEmail records the email of a user, creating its identity record if needed
+// The previous email of the user is no longer linked to it
+// NOTE: Caller must hold appropriate lock if concurrent access is possible
+func (s *UserStore) SetEmail(ctx context.Context, uid, email string) error {
+	previous, err := s.GetEmail(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if previous == email {
+		return nil
+	}
+
+	// The previous email is unlinked first, the identity record still references it if an update
+	// fails so that retrying it unlinks it
+	if previous != "" {
+		if err := s.unlinkEmail(ctx, uid); err != nil {
+			return err
+		}
+	}
+	if err := s.cache.HSet(ctx, emailKey(email), map[string]string{"uid": uid}); err != nil {
+		return fmt.Errorf("failed to set user email in cache: %w", err)
+	}
+	if err := s.cache.HSet(ctx, identityKey(uid), map[string]string{"email": email}); err != nil {
+		return fmt.Errorf("failed to set user identity in cache: %w", err)
+	}
+	return nil
+}
+
+// unlinkEmail removes the link from the current email of a user to its uid, unless the email
+// was linked to another user since
+func (s *UserStore) unlinkEmail(ctx context.Context, uid string) error {
+	email, err := s.GetEmail(ctx, uid)
+	if err != nil || email == "" {
+		return err
+	}
+	owner, err := s.GetUIDByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if owner != uid {
+		return nil
+	}
+	if err := s.cache.Delete(ctx, emailKey(email)); err != nil {
+		return fmt.Errorf("failed to delete user email from cache: %w", err)
+	}
+	return nil
 }
@@ -63,7 +63,7 @@ func TestUserStore_GetBackends(t *testing.T) {
 			WantErr: false,
 		},
 		{
-			Name:       "invalid JSON returns error",
+			Name:       "record of the wrong type returns error",
 			Identifier: "invalid@example.com",
 			SetupFunc: func(t *testing.T, store EntityStoreInterface, c cache.Cache) {
 				err := c.Set(context.Background(), "user:invalid@example.com", "invalid json{{{", cache.NoExpiration)
@@ -71,7 +71,7 @@ func TestUserStore_GetBackends(t *testing.T) {
 			},
 			Want:        nil,
 			WantErr:     true,
-			ErrContains: "failed to unmarshal",
+			ErrContains: "failed to get user backends",
 		},
 	}
 
@@ -131,7 +131,7 @@ func TestUserStore_SetBackend(t *testing.T) {
 			WantErr: false,
 		},
 		{
-			Name:       "handles invalid existing JSON",
+			Name:       "handles existing record of the wrong type",
 			Identifier: "corrupt@example.com",
 			BackendKey: "fivetran_prod",
 			BackendID:  "user_123",
@@ -142,7 +142,7 @@ func TestUserStore_SetBackend(t *testing.T) {
 			},
 			VerifyFunc:  func(t *testing.T, store EntityStoreInterface) {},
 			WantErr:     true,
-			ErrContains: "failed to unmarshal",
+			ErrContains: "failed to get user backend",
 		},
 	}
 
@@ -198,7 +198,7 @@ func TestUserStore_DeleteBackend(t *testing.T) {
 			WantErr:    false,
 		},
 		{
-			Name:       "handles invalid JSON",
+			Name:       "handles record of the wrong type",
 			Identifier: "corrupt@example.com",
 			BackendKey: "fivetran_prod",
 			SetupFunc: func(t *testing.T, store EntityStoreInterface) {
@@ -208,7 +208,7 @@ func TestUserStore_DeleteBackend(t *testing.T) {
 			},
 			VerifyFunc:  func(t *testing.T, store EntityStoreInterface) {},
 			WantErr:     true,
-			ErrContains: "failed to unmarshal",
+			ErrContains: "failed to get user backend",
 		},
 	}
 
@@ -323,18 +323,20 @@ func TestUserStore_GetByPattern(t *testing.T) {
 			wantErr:      false,
 		},
 		{
-			name:    "skips invalid JSON entries",
+			name:    "skips records that are not hashes",
 			pattern: "*@example.com",
 			setup: func(t *testing.T, store *UserStore) {
 				ctx := context.Background()
 				err := store.SetBackend(ctx, "valid@example.com", "fivetran_prod", "user_123")
 				require.NoError(t, err)
-				// Add invalid JSON
+				// Add a string record and a user groups reverse index entry
 				err = store.cache.Set(ctx, "user:invalid@example.com", "invalid json", cache.NoExpiration)
 				require.NoError(t, err)
+				err = store.cache.SAdd(ctx, "user:groups:valid@example.com", "data-team")
+				require.NoError(t, err)
 			},
 			wantEmails:   []string{"valid@example.com"},
-			wantNotFound: []string{"invalid@example.com"},
+			wantNotFound: []string{"invalid@example.com", "groups:valid@example.com"},
 			wantCount:    1,
 			wantErr:      false,
 		},
@@ -388,11 +390,87 @@ func TestUserStore_KeyPrefix(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify key has correct prefix
-	val, err := c.Get(ctx, "user:user@example.com")
+	val, err := c.HGetAll(ctx, "user:user@example.com")
 	assert.NoError(t, err)
-	assert.NotNil(t, val)
+	assert.Equal(t, map[string]string{"fivetran_prod": "user_123"}, val)
 
 	// Verify key without prefix doesn't exist
-	_, err = c.Get(ctx, "user@example.com")
-	assert.Error(t, err)
+	val, err = c.HGetAll(ctx, "user@example.com")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+}
+
+func TestUserStore_BackendIndex(t *testing.T) {
+	store, _ := setupUserStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.SetBackend(ctx, "alice@example.com", "snowflake_prod", "ALICE"))
+	require.NoError(t, store.SetBackend(ctx, "alice@example.com", "fivetran_prod", "user_1"))
+	require.NoError(t, store.SetBackend(ctx, "bob@example.com", "snowflake_prod", "BOB"))
+
+	email, err := store.GetByBackendID(ctx, "snowflake_prod", "ALICE")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+
+	users, err := store.GetBackendUsers(ctx, "snowflake_prod")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ALICE": "alice@example.com", "BOB": "bob@example.com"}, users)
+
+	// the previous ID of a user is removed from the index
+	require.NoError(t, store.SetBackend(ctx, "alice@example.com", "snowflake_prod", "ALICE2"))
+	email, err = store.GetByBackendID(ctx, "snowflake_prod", "ALICE")
+	require.NoError(t, err)
+	assert.Empty(t, email)
+
+	// an ID linked to another user is kept in the index when the previous user is deleted
+	require.NoError(t, store.SetBackend(ctx, "carol@example.com", "fivetran_prod", "user_1"))
+	require.NoError(t, store.DeleteBackend(ctx, "alice@example.com", "fivetran_prod"))
+	email, err = store.GetByBackendID(ctx, "fivetran_prod", "user_1")
+	require.NoError(t, err)
+	assert.Equal(t, "carol@example.com", email)
+
+	require.NoError(t, store.Delete(ctx, "alice@example.com"))
+	users, err = store.GetBackendUsers(ctx, "snowflake_prod")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"BOB": "bob@example.com"}, users)
+
+	users, err = store.GetBackendUsers(ctx, "unknown_backend")
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestUserStore_Identity(t *testing.T) {
+	store, _ := setupUserStore(t)
+	ctx := context.Background()
+
+	email, err := store.GetEmail(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, email)
+
+	require.NoError(t, store.SetEmail(ctx, "alice", "alice@example.com"))
+	email, err = store.GetEmail(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+	uid, err := store.GetUIDByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", uid)
+
+	// a changed email is re-linked, the previous one no longer resolves to the user
+	require.NoError(t, store.SetEmail(ctx, "alice", "alice.smith@example.com"))
+	uid, err = store.GetUIDByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, uid)
+	uid, err = store.GetUIDByEmail(ctx, "alice.smith@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", uid)
+
+	// an email taken over by another user stays linked to it when the previous user is deleted
+	require.NoError(t, store.SetEmail(ctx, "asmith", "alice.smith@example.com"))
+	require.NoError(t, store.Delete(ctx, "alice"))
+	email, err = store.GetEmail(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, email)
+	uid, err = store.GetUIDByEmail(ctx, "alice.smith@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "asmith", uid)
 }
@@ -299,6 +299,15 @@ func GetTransformedGroupName(cfg *config.AppConfig, typeName, inputStr string) (
 	return "", fmt.Errorf("no matching pattern found for backend type %s and input string is %s", typeName, inputStr)
 }
 
+// GetBackendTeamName returns the name of the team of the group in a backend of the given type,
+// an explicit teamName takes precedence over the transformation patterns
+func GetBackendTeamName(cfg *config.AppConfig, typeName, teamName, groupName string) (string, error) {
+	if teamName != "" {
+		return teamName, nil
+	}
+	return GetTransformedGroupName(cfg, typeName, groupName)
+}
+
 // StandardizeNameForBackend standardizes a user's first or last name for systems (e.g. Fivetran)
 // that do not support certain special characters. It replaces period (.), parenthesis (( )), and comma (,)
 // with a space, then collapses multiple spaces and trims.
@@ -351,6 +351,23 @@ func TestGetTransformGroupName(t *testing.T) {
 	}
 }
 
+func TestGetBackendTeamName(t *testing.T) {
+	cfg := &config.AppConfig{
+		Pattern: map[string][]config.PatternEntry{
+			"fivetran": {{Input: "^dataverse-source-(.*)$", Output: "$1_group"}},
+		},
+	}
+
+	teamName, err := GetBackendTeamName(cfg, "fivetran", "", "dataverse-source-sfsales")
+	assert.NoError(t, err)
+	assert.Equal(t, "sfsales_group", teamName)
+
+	// the override wins even when no pattern matches the group name
+	teamName, err = GetBackendTeamName(cfg, "fivetran", "legacy_team", "No_Mapping")
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy_team", teamName)
+}
+
 func TestStandardizeNameForBackend(t *testing.T) {
 	tests := []struct {
 		name     string